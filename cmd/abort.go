@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var abortCmd = &cobra.Command{
+	Use:     "abort",
+	Aliases: []string{"rebase-abort"},
+	Short:   "Abort an in-progress rebase left behind by a conflict",
+	Example: `  # Give up on a conflicted rebase and return to a clean tree
+  frond abort`,
+	Args: cobra.NoArgs,
+	RunE: runAbort,
+}
+
+func init() {
+	rootCmd.AddCommand(abortCmd)
+}
+
+func runAbort(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	aborted := true
+	if err := git.RebaseAbort(ctx); err != nil {
+		if !errors.Is(err, git.ErrNoRebaseInProgress) {
+			return fmt.Errorf("aborting rebase: %w", err)
+		}
+		aborted = false
+	}
+
+	if jsonOut {
+		return printJSON(abortResult{Aborted: aborted})
+	}
+	if !aborted {
+		fmt.Println("no rebase in progress")
+		return nil
+	}
+	fmt.Println("Aborted in-progress rebase; working tree restored")
+	return nil
+}
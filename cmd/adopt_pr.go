@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var adoptPRCmd = &cobra.Command{
+	Use:   "adopt-pr <branch> <number>",
+	Short: "Bind an existing PR to a tracked branch",
+	Long: "Record PR <number> as the PR of tracked <branch>, after checking on GitHub that the PR exists and its head is that branch. " +
+		"Use it when a PR was opened outside frond, or to repair state that lost or mixed up PR numbers. A PR already recorded for the branch is replaced.",
+	Example: `  # The PR was opened from the GitHub UI
+  frond adopt-pr feature/auth 123`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runAdoptPR,
+}
+
+func init() {
+	rootCmd.AddCommand(adoptPRCmd)
+}
+
+func runAdoptPR(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	pr, err := strconv.Atoi(args[1])
+	if err != nil || pr <= 0 {
+		return fmt.Errorf("invalid PR number %q", args[1])
+	}
+
+	if err := gh.Available(); err != nil {
+		return fmt.Errorf("gh CLI is required. Install: https://cli.github.com")
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	name, err := resolveTracked(s.Branches, args[0])
+	if err != nil {
+		return err
+	}
+	b := s.Branches[name]
+	previous := b.PR
+
+	if previous == nil || *previous != pr {
+		if err := validateExistingPR(ctx, s.Branches, name, pr); err != nil {
+			return err
+		}
+		b.PR = &pr
+		s.Branches[name] = b
+		if err := state.Write(ctx, s); err != nil {
+			return fmt.Errorf("writing state: %w", err)
+		}
+	}
+
+	if jsonOut {
+		return printJSON(adoptPRResult{
+			Branch:   name,
+			PR:       pr,
+			Previous: previous,
+		})
+	}
+	switch {
+	case previous == nil:
+		fmt.Printf("'%s' now has PR #%d\n", name, pr)
+	case *previous == pr:
+		fmt.Printf("'%s' already has PR #%d\n", name, pr)
+	default:
+		fmt.Printf("'%s' now has PR #%d (was #%d)\n", name, pr, *previous)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// openBrowser opens url in the user's default browser without waiting for
+// it to exit. It is a variable so tests can capture the URL instead.
+var openBrowser = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait() //nolint:errcheck // reap the opener; its exit status does not matter
+	return nil
+}
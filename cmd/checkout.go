@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/nvandessel/frond/internal/dag"
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var checkoutCmd = &cobra.Command{
+	Use:     "checkout <branch>",
+	Aliases: []string{"co"},
+	Short:   "Check out a tracked branch by full or short name",
+	Long: "Check out a tracked branch. The argument may be the full name or, when it is unambiguous, " +
+		"the short name (the part after the last '/'). Unknown names suggest the closest tracked branch.",
+	Example: `  # Same as 'git checkout pay/stripe-client' when no other branch ends in stripe-client
+  frond checkout stripe-client`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runCheckout,
+}
+
+func init() {
+	rootCmd.AddCommand(checkoutCmd)
+}
+
+func runCheckout(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	target, err := resolveTracked(s.Branches, args[0])
+	if err != nil {
+		// Only suggest for unknown names; ambiguous ones already list
+		// the candidates.
+		if guess := closestTracked(s.Branches, args[0]); guess != "" && len(shortNameMatches(s.Branches, args[0])) == 0 {
+			return fmt.Errorf("%w; did you mean '%s'?", err, guess)
+		}
+		return err
+	}
+
+	current, err := git.CurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("getting current branch: %w", err)
+	}
+	return navigateTo(ctx, current, target)
+}
+
+// closestTracked returns the tracked branch whose full or short name is the
+// fewest edits away from arg, or "" when none is close enough to be a
+// plausible typo. Ties go to the alphabetically first branch.
+func closestTracked(branches map[string]state.Branch, arg string) string {
+	names := make([]string, 0, len(branches))
+	for name := range branches {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	best, bestDist := "", 0
+	for _, name := range names {
+		d := min(levenshtein(arg, name), levenshtein(arg, dag.ShortName(name)))
+		if best == "" || d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	// More edits than half the input is a different name, not a typo.
+	if best == "" || bestDist > max(len([]rune(arg))/2, 1) {
+		return ""
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b, counting runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nvandessel/frond/internal/dag"
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var cloneStackCmd = &cobra.Command{
+	Use:   "clone-stack <path> [<branch>]",
+	Short: "Check out a stack in a new worktree",
+	Long: "Create a git worktree at <path> and check out each branch of the stack containing <branch> (default: the current branch) there, parents first. " +
+		"The stack is the branch, its tracked ancestors and its descendants. The worktree shares frond.json with this one, so frond works there unchanged. " +
+		"Git allows a branch in only one worktree at a time, so branches checked out elsewhere (usually the one you are on) are skipped; the worktree ends on the last branch it could check out.",
+	Example: `  # Review the stack next to your main checkout
+  frond clone-stack ../review
+
+  # Clone a different stack
+  frond clone-stack ../pay pay/stripe-client`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runCloneStack,
+}
+
+func init() {
+	rootCmd.AddCommand(cloneStackCmd)
+}
+
+func runCloneStack(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	var name string
+	if len(args) > 1 {
+		if name, err = resolveTracked(s.Branches, args[1]); err != nil {
+			return err
+		}
+	} else {
+		if name, err = git.CurrentBranch(ctx); err != nil {
+			return fmt.Errorf("getting current branch: %w", err)
+		}
+		if _, tracked := s.Branches[name]; !tracked {
+			return fmt.Errorf("current branch '%s' is not tracked", name)
+		}
+	}
+
+	order, err := dag.TopoSort(stateToDag(s.Branches))
+	if err != nil {
+		return fmt.Errorf("sorting branches: %w", err)
+	}
+	var stack []string
+	for _, b := range order {
+		if b == name || descendsFrom(s.Branches, name, b) || descendsFrom(s.Branches, b, name) {
+			stack = append(stack, b)
+		}
+	}
+
+	checkedOut, err := git.WorktreeBranches(ctx)
+	if err != nil {
+		return err
+	}
+
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", args[0], err)
+	}
+	trunk := dag.RootOf(stateToDag(s.Branches), name)
+	if err := git.WorktreeAdd(ctx, path, trunk); err != nil {
+		return err
+	}
+
+	result := cloneStackResult{Path: path, Branches: []string{}, Skipped: []string{}}
+	for _, b := range stack {
+		if _, busy := checkedOut[b]; busy {
+			result.Skipped = append(result.Skipped, b)
+			continue
+		}
+		if err := git.CheckoutIn(ctx, path, b); err != nil {
+			return err
+		}
+		result.Branches = append(result.Branches, b)
+		result.Head = b
+	}
+
+	if jsonOut {
+		return printJSON(result)
+	}
+	fmt.Printf("Created worktree %s\n", path)
+	for _, b := range result.Branches {
+		fmt.Printf("  checked out %s\n", b)
+	}
+	for _, b := range result.Skipped {
+		fmt.Printf("  skipped %s (checked out in %s)\n", b, checkedOut[b])
+	}
+	if result.Head == "" {
+		fmt.Printf("Every branch is checked out elsewhere; the worktree is detached at %s\n", trunk)
+	} else {
+		fmt.Printf("Worktree is on %s\n", result.Head)
+	}
+	return nil
+}
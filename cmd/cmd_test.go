@@ -1,16 +1,23 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/nvandessel/frond/internal/gh"
 	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
@@ -149,14 +156,34 @@ func installFakeGH(t *testing.T, dir string) {
 	t.Setenv("FAKEGH_PR_COUNTER", "")
 	t.Setenv("FAKEGH_PR_STATE", "")
 	t.Setenv("FAKEGH_EXISTING_COMMENT", "")
+	t.Setenv("FAKEGH_PR_AUTHOR", "")
+	t.Setenv("FAKEGH_USER", "")
+	t.Setenv("FAKEGH_PR_HEAD", "")
+	t.Setenv("FAKEGH_PR_LIST", "")
+	t.Setenv("FAKEGH_UNAUTHENTICATED", "")
+	t.Setenv("FAKEGH_MERGED_PRS", "")
+	t.Setenv("FAKEGH_LATEST_RELEASE", "")
+	t.Setenv("FAKEGH_CHECKS", "")
+	t.Setenv("FAKEGH_MERGEABLE", "")
+	t.Setenv("FAKEGH_PR_EXISTS", "")
+	t.Setenv("FAKEGH_PENDING_VIEWS", "")
+	t.Setenv("FAKEGH_VIEW_COUNTER", "")
 }
 
 // resetCobraFlags resets all cobra flag values to their defaults so tests
-// don't leak flag state between runs.
+// don't leak flag state between runs. It also drops any context left behind
+// by Execute, whose signal context is cancelled on return and would otherwise
+// be inherited by every later run.
 func resetCobraFlags() {
+	resetContexts(rootCmd)
 	for _, cmd := range rootCmd.Commands() {
 		cmd.Flags().VisitAll(func(f *pflag.Flag) {
-			_ = f.Value.Set(f.DefValue)
+			// Set appends for slice flags, so clear those instead.
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				_ = sv.Replace(nil)
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
 			f.Changed = false
 		})
 	}
@@ -166,6 +193,15 @@ func resetCobraFlags() {
 	})
 }
 
+// resetContexts gives cmd and all of its subcommands a fresh background
+// context.
+func resetContexts(cmd *cobra.Command) {
+	cmd.SetContext(context.Background())
+	for _, sub := range cmd.Commands() {
+		resetContexts(sub)
+	}
+}
+
 // readState reads frond.json from the temp repo's .git directory.
 func readState(t *testing.T, repoDir string) *state.State {
 	t.Helper()
@@ -383,9 +419,20 @@ func TestStatusJSON(t *testing.T) {
 		t.Fatalf("frond new feat-a: %v", err)
 	}
 
-	err := runTier(t, "status", "--json")
-	if err != nil {
-		t.Fatalf("frond status --json: %v", err)
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--json"); err != nil {
+			t.Fatalf("frond status --json: %v", err)
+		}
+	})
+	var result struct {
+		Driver string `json:"driver"`
+		Trunk  string `json:"trunk"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if result.Driver != "native" || result.Trunk != "main" {
+		t.Errorf("envelope = %+v, want driver native, trunk main", result)
 	}
 }
 
@@ -719,6 +766,71 @@ func TestNewWithAfterDeps(t *testing.T) {
 	}
 }
 
+func TestNewAfterCurrent(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "dep-a"); err != nil {
+		t.Fatalf("frond new dep-a: %v", err)
+	}
+	resetCobraFlags()
+	// Still on dep-a: the new branch sits on main but waits for dep-a.
+	if err := runTier(t, "new", "follow-up", "--on", "main", "--after-current"); err != nil {
+		t.Fatalf("frond new --after-current: %v", err)
+	}
+
+	s := readState(t, dir)
+	b := s.Branches["follow-up"]
+	if b.Parent != "main" {
+		t.Errorf("follow-up parent = %q, want main", b.Parent)
+	}
+	if len(b.After) != 1 || b.After[0] != "dep-a" {
+		t.Errorf("follow-up after = %v, want [dep-a]", b.After)
+	}
+
+	// On trunk there is nothing to depend on.
+	gitRun(t, dir, "checkout", "main")
+	resetCobraFlags()
+	err := runTier(t, "new", "orphan", "--after-current")
+	if err == nil || !strings.Contains(err.Error(), "not tracked") {
+		t.Errorf("err = %v, want 'not tracked'", err)
+	}
+}
+
+func TestParentAliasForOn(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "base"); err != nil {
+		t.Fatalf("frond new base: %v", err)
+	}
+	resetCobraFlags()
+	if err := runTier(t, "new", "child", "--parent", "base"); err != nil {
+		t.Fatalf("frond new --parent: %v", err)
+	}
+	gitRun(t, dir, "checkout", "-b", "loose", "base")
+	resetCobraFlags()
+	if err := runTier(t, "track", "loose", "--parent", "base"); err != nil {
+		t.Fatalf("frond track --parent: %v", err)
+	}
+
+	s := readState(t, dir)
+	for _, name := range []string{"child", "loose"} {
+		if got := s.Branches[name].Parent; got != "base" {
+			t.Errorf("%s parent = %q, want base", name, got)
+		}
+	}
+
+	// Same value twice is fine; different values are rejected.
+	resetCobraFlags()
+	if err := runTier(t, "new", "agree", "--on", "base", "--parent", "base"); err != nil {
+		t.Errorf("frond new --on base --parent base: %v", err)
+	}
+	resetCobraFlags()
+	err := runTier(t, "new", "conflict", "--on", "main", "--parent", "base")
+	if err == nil || !strings.Contains(err.Error(), "disagree") {
+		t.Errorf("err = %v, want 'disagree'", err)
+	}
+}
+
 func TestNewInvalidBranchName(t *testing.T) {
 	setupTestEnv(t)
 
@@ -1653,7 +1765,4164 @@ func TestNewEmptySyncResult(t *testing.T) {
 	if r.Merged == nil || r.Rebased == nil || r.Unblocked == nil || r.Conflicts == nil {
 		t.Error("newEmptySyncResult should initialize all slices")
 	}
-	if r.Reparented == nil || r.Blocked == nil {
+	if r.Reparented == nil || r.Blocked == nil || r.Skipped == nil {
 		t.Error("newEmptySyncResult should initialize all maps")
 	}
 }
+
+// captureStdout runs fn with os.Stdout redirected and returns what it wrote.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fn()
+
+	w.Close()
+	os.Stdout = orig
+	return <-done
+}
+
+// captureStderr captures stderr output during fn execution.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+
+	orig := os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stderr = w
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fn()
+
+	w.Close()
+	os.Stderr = orig
+	return <-done
+}
+
+// gitRun runs a git command in dir and fails the test on error.
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	c := exec.Command("git", args...)
+	c.Dir = dir
+	if out, err := c.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %s\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// gitOutput runs a git command in dir and returns its stdout.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	c := exec.Command("git", args...)
+	c.Dir = dir
+	out, err := c.Output()
+	if err != nil {
+		t.Fatalf("git %s: %s", strings.Join(args, " "), err)
+	}
+	return string(out)
+}
+
+func TestTrunkAddRootsSecondaryStack(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	gitRun(t, dir, "branch", "release/1.x")
+
+	if err := runTier(t, "trunk", "add", "release/1.x"); err != nil {
+		t.Fatalf("frond trunk add: %v", err)
+	}
+	if err := runTier(t, "new", "feat-main"); err != nil {
+		t.Fatalf("frond new feat-main: %v", err)
+	}
+	gitRun(t, dir, "checkout", "release/1.x")
+	if err := runTier(t, "new", "backport"); err != nil {
+		t.Fatalf("frond new backport: %v", err)
+	}
+
+	s := readState(t, dir)
+	if got := s.Branches["backport"].Parent; got != "release/1.x" {
+		t.Errorf("backport parent = %q, want %q", got, "release/1.x")
+	}
+
+	gitRun(t, dir, "branch", "fix")
+	if err := runTier(t, "track", "fix", "--on", "release/1.x"); err != nil {
+		t.Fatalf("frond track --on secondary trunk: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status"); err != nil {
+			t.Fatalf("frond status: %v", err)
+		}
+	})
+	if !strings.Contains(out, "main\n└── feat-main") {
+		t.Errorf("status missing primary tree:\n%s", out)
+	}
+	if !strings.Contains(out, "release/1.x\n├── backport") {
+		t.Errorf("status missing secondary tree:\n%s", out)
+	}
+}
+
+func TestTrunkRemove(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	gitRun(t, dir, "branch", "release/1.x")
+	if err := runTier(t, "trunk", "add", "release/1.x"); err != nil {
+		t.Fatalf("frond trunk add: %v", err)
+	}
+	if err := runTier(t, "new", "backport", "--on", "release/1.x"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+
+	if err := runTier(t, "trunk", "remove", "release/1.x"); err == nil {
+		t.Fatal("expected error removing a trunk that still roots branches")
+	}
+	if err := runTier(t, "trunk", "remove", "main"); err == nil {
+		t.Fatal("expected error removing the primary trunk")
+	}
+
+	if err := runTier(t, "untrack", "backport"); err != nil {
+		t.Fatalf("frond untrack: %v", err)
+	}
+	if err := runTier(t, "trunk", "remove", "release/1.x"); err != nil {
+		t.Fatalf("frond trunk remove: %v", err)
+	}
+	if s := readState(t, dir); len(s.Trunks) != 0 {
+		t.Errorf("Trunks = %v, want empty", s.Trunks)
+	}
+}
+
+func TestGraphJSONEdges(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "new", "edge-a"); err != nil {
+		t.Fatalf("frond new edge-a: %v", err)
+	}
+	if err := runTier(t, "new", "edge-b", "--on", "main", "--after", "edge-a"); err != nil {
+		t.Fatalf("frond new edge-b: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "graph", "--json-edges"); err != nil {
+			t.Fatalf("frond graph --json-edges: %v", err)
+		}
+	})
+
+	var edges struct {
+		ParentEdges [][2]string `json:"parent_edges"`
+		AfterEdges  [][2]string `json:"after_edges"`
+	}
+	if err := json.Unmarshal([]byte(out), &edges); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if len(edges.ParentEdges) != 2 {
+		t.Errorf("parent_edges = %v, want 2 edges", edges.ParentEdges)
+	}
+	if len(edges.AfterEdges) != 1 || edges.AfterEdges[0] != [2]string{"edge-b", "edge-a"} {
+		t.Errorf("after_edges = %v, want [[edge-b edge-a]]", edges.AfterEdges)
+	}
+}
+
+func TestCloneStack(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "base"); err != nil {
+		t.Fatalf("frond new base: %v", err)
+	}
+	resetCobraFlags()
+	if err := runTier(t, "new", "top"); err != nil {
+		t.Fatalf("frond new top: %v", err)
+	}
+	gitRun(t, dir, "checkout", "main")
+	resetCobraFlags()
+	if err := runTier(t, "new", "unrelated"); err != nil {
+		t.Fatalf("frond new unrelated: %v", err)
+	}
+	gitRun(t, dir, "checkout", "base")
+
+	wt := filepath.Join(t.TempDir(), "review")
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "clone-stack", wt, "--json"); err != nil {
+			t.Fatalf("frond clone-stack: %v", err)
+		}
+	})
+	var result cloneStackResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if result.Head != "top" || len(result.Branches) != 1 || len(result.Skipped) != 1 || result.Skipped[0] != "base" {
+		t.Errorf("clone-stack result = %+v, want top checked out and base skipped", result)
+	}
+	if got := strings.TrimSpace(gitOutput(t, wt, "rev-parse", "--abbrev-ref", "HEAD")); got != "top" {
+		t.Errorf("worktree HEAD = %q, want top", got)
+	}
+	// The worktree shares frond.json.
+	if got := strings.TrimSpace(gitOutput(t, wt, "rev-parse", "--git-common-dir")); got != filepath.Join(dir, ".git") {
+		t.Errorf("worktree common dir = %q", got)
+	}
+}
+
+func TestGraphDOTDepthLimit(t *testing.T) {
+	setupTestEnv(t)
+
+	for _, name := range []string{"deep-1", "deep-2", "deep-3"} {
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+
+	if err := runTier(t, "graph", "--depth-limit", "1"); err == nil {
+		t.Error("--depth-limit without --dot or --mermaid should fail")
+	}
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "graph", "--dot", "--depth-limit", "1"); err != nil {
+			t.Fatalf("frond graph --dot: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"deep-1" -> "main"`) || strings.Contains(out, `"deep-2"`) || !strings.Contains(out, "+2 hidden") {
+		t.Errorf("graph --dot --depth-limit 1 =\n%s\nwant deep-1 shown and two branches folded", out)
+	}
+
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "graph", "--mermaid"); err != nil {
+			t.Fatalf("frond graph --mermaid: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "graph BT\n") || !strings.Contains(out, `["deep-3"]`) {
+		t.Errorf("graph --mermaid =\n%s\nwant every branch", out)
+	}
+}
+
+func TestInitImport(t *testing.T) {
+	src := setupTestEnv(t)
+
+	if err := runTier(t, "new", "imp-a"); err != nil {
+		t.Fatalf("frond new imp-a: %v", err)
+	}
+	if err := runTier(t, "new", "imp-b", "--after", "imp-a"); err != nil {
+		t.Fatalf("frond new imp-b: %v", err)
+	}
+	setPR(t, src, "imp-a", 7)
+	export := captureStdout(t, func() {
+		if err := runTier(t, "status", "--json"); err != nil {
+			t.Fatalf("frond status --json: %v", err)
+		}
+	})
+	exportPath := filepath.Join(t.TempDir(), "stack.json")
+	if err := os.WriteFile(exportPath, []byte(export), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := setupTestEnv(t)
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "init", "--import", exportPath, "--json"); err != nil {
+			t.Fatalf("frond init --import: %v", err)
+		}
+	})
+	var result initResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing %q: %v", out, err)
+	}
+	if !result.Created || result.Trunk != "main" || len(result.Imported) != 2 {
+		t.Errorf("init result = %+v, want created with 2 imported", result)
+	}
+	st := readState(t, dir)
+	if b := st.Branches["imp-b"]; b.Parent != "imp-a" || len(b.After) != 1 || b.After[0] != "imp-a" {
+		t.Errorf("imp-b = %+v, want parent and after imp-a", b)
+	}
+	if pr := st.Branches["imp-a"].PR; pr == nil || *pr != 7 {
+		t.Errorf("imp-a PR = %v, want 7", pr)
+	}
+	if st.LastSeq < 2 {
+		t.Errorf("LastSeq = %d, want at least the imported seqs", st.LastSeq)
+	}
+
+	// A second import would clobber tracked branches.
+	resetCobraFlags()
+	if err := runTier(t, "init", "--import", exportPath); err == nil || !strings.Contains(err.Error(), "already tracks") {
+		t.Errorf("second import error = %v, want refusal", err)
+	}
+
+	// A broken graph is rejected before anything is written.
+	dir = setupTestEnv(t)
+	badPath := filepath.Join(t.TempDir(), "bad.json")
+	bad := `{"trunk":"main","branches":[{"name":"x","parent":"gone","after":[]}]}`
+	if err := os.WriteFile(badPath, []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resetCobraFlags()
+	if err := runTier(t, "init", "--import", badPath); err == nil || !strings.Contains(err.Error(), "invalid graph") {
+		t.Errorf("bad import error = %v, want invalid graph", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "frond.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("failed import left frond.json behind: %v", err)
+	}
+}
+
+func TestGraphRoots(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "new", "root-a"); err != nil {
+		t.Fatalf("frond new root-a: %v", err)
+	}
+	if err := runTier(t, "new", "child-a"); err != nil {
+		t.Fatalf("frond new child-a: %v", err)
+	}
+	if err := runTier(t, "new", "root-b", "--on", "main"); err != nil {
+		t.Fatalf("frond new root-b: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "graph", "--roots"); err != nil {
+			t.Fatalf("frond graph --roots: %v", err)
+		}
+	})
+	if out != "root-a\nroot-b\n" {
+		t.Errorf("output = %q, want root-a and root-b", out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runTier(t, "graph", "--roots", "--json"); err != nil {
+			t.Fatalf("frond graph --roots --json: %v", err)
+		}
+	})
+	var result struct {
+		Roots []string `json:"roots"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if len(result.Roots) != 2 {
+		t.Errorf("roots = %v, want 2", result.Roots)
+	}
+}
+
+func TestUntrackMiddleOfChainReparentsToGrandparent(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "level-1"); err != nil {
+		t.Fatalf("frond new level-1: %v", err)
+	}
+	if err := runTier(t, "new", "level-2", "--on", "level-1"); err != nil {
+		t.Fatalf("frond new level-2: %v", err)
+	}
+	if err := runTier(t, "new", "level-3", "--on", "level-2"); err != nil {
+		t.Fatalf("frond new level-3: %v", err)
+	}
+
+	if err := runTier(t, "untrack", "level-2"); err != nil {
+		t.Fatalf("frond untrack level-2: %v", err)
+	}
+
+	s := readState(t, dir)
+	if got := s.Branches["level-3"].Parent; got != "level-1" {
+		t.Errorf("level-3 parent = %q, want %q", got, "level-1")
+	}
+}
+
+func TestRemoveTrackedNearestSurvivingAncestor(t *testing.T) {
+	s := &state.State{
+		Trunk: "main",
+		Branches: map[string]state.Branch{
+			"a": {Parent: "main", After: []string{}},
+			"b": {Parent: "a", After: []string{}},
+			"c": {Parent: "b", After: []string{"a"}},
+			"d": {Parent: "c", After: []string{}},
+		},
+	}
+
+	outcome := removeTracked(s, "b", "c", "a")
+
+	if got := s.Branches["d"].Parent; got != "main" {
+		t.Errorf("d parent = %q, want %q", got, "main")
+	}
+	if len(s.Branches) != 1 {
+		t.Errorf("branches = %v, want only d", s.Branches)
+	}
+	if outcome.reparented["d"] != "main" {
+		t.Errorf("reparented = %v, want d → main", outcome.reparented)
+	}
+}
+
+func TestAbortNothingInProgress(t *testing.T) {
+	setupTestEnv(t)
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "abort", "--json"); err != nil {
+			t.Fatalf("frond abort: %v", err)
+		}
+	})
+	var res abortResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if res.Aborted {
+		t.Error("aborted = true with no rebase in progress")
+	}
+}
+
+// startConflictingRebase leaves a rebase of "conflict" onto main stopped on
+// a conflict in the temp repo.
+func startConflictingRebase(t *testing.T, dir string) {
+	t.Helper()
+
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "shared.txt"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("original\n")
+	gitRun(t, dir, "add", "shared.txt")
+	gitRun(t, dir, "commit", "-m", "add shared")
+	gitRun(t, dir, "checkout", "-b", "conflict")
+	writeFile("branch\n")
+	gitRun(t, dir, "commit", "-am", "branch change")
+	gitRun(t, dir, "checkout", "main")
+	writeFile("main\n")
+	gitRun(t, dir, "commit", "-am", "main change")
+
+	rebase := exec.Command("git", "rebase", "main", "conflict")
+	rebase.Dir = dir
+	if err := rebase.Run(); err == nil {
+		t.Fatal("expected rebase to stop on a conflict")
+	}
+}
+
+func TestSyncRestacksLinearChain(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	// main <- chain-a <- chain-b <- chain-c, each with its own commit.
+	for _, name := range []string{"chain-a", "chain-b", "chain-c"} {
+		resetCobraFlags()
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+		gitRun(t, dir, "commit", "--allow-empty", "-m", name+" work")
+	}
+	gitRun(t, dir, "checkout", "main")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "main moves on")
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "sync", "--no-snapshot", "--use-update-refs", "--json"); err != nil {
+			t.Fatalf("frond sync: %v", err)
+		}
+	})
+	var r syncResult
+	if err := json.Unmarshal([]byte(out), &r); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if !slices.Equal(r.Rebased, []string{"chain-a", "chain-b", "chain-c"}) {
+		t.Errorf("rebased = %v, want the whole chain", r.Rebased)
+	}
+	for _, pair := range [][2]string{{"main", "chain-a"}, {"chain-a", "chain-b"}, {"chain-b", "chain-c"}} {
+		if err := exec.Command("git", "-C", dir, "merge-base", "--is-ancestor", pair[0], pair[1]).Run(); err != nil {
+			t.Errorf("%s is not an ancestor of %s after sync", pair[0], pair[1])
+		}
+	}
+	if log := strings.TrimSpace(gitOutput(t, dir, "log", "--format=%s", "chain-b..chain-c")); log != "chain-c work" {
+		t.Errorf("chain-c commits = %q, want only its own", log)
+	}
+	if got := strings.TrimSpace(gitOutput(t, dir, "branch", "--show-current")); got != "main" {
+		t.Errorf("current branch after sync = %q, want main", got)
+	}
+
+	// The per-branch loop, the default, gives the same result.
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "main moves again")
+	resetCobraFlags()
+	if err := runTier(t, "sync", "--no-snapshot"); err != nil {
+		t.Fatalf("frond sync: %v", err)
+	}
+	for _, pair := range [][2]string{{"main", "chain-a"}, {"chain-a", "chain-b"}, {"chain-b", "chain-c"}} {
+		if err := exec.Command("git", "-C", dir, "merge-base", "--is-ancestor", pair[0], pair[1]).Run(); err != nil {
+			t.Errorf("%s is not an ancestor of %s after per-branch sync", pair[0], pair[1])
+		}
+	}
+
+	// An untracked branch inside the run must not be dragged along.
+	gitRun(t, dir, "branch", "chain-keep", "chain-b")
+	keep := gitOutput(t, dir, "rev-parse", "chain-keep")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "main moves a third time")
+	resetCobraFlags()
+	if err := runTier(t, "sync", "--no-snapshot", "--use-update-refs"); err != nil {
+		t.Fatalf("frond sync --use-update-refs: %v", err)
+	}
+	if got := gitOutput(t, dir, "rev-parse", "chain-keep"); got != keep {
+		t.Error("--use-update-refs moved the untracked chain-keep")
+	}
+	for _, pair := range [][2]string{{"main", "chain-a"}, {"chain-a", "chain-b"}, {"chain-b", "chain-c"}} {
+		if err := exec.Command("git", "-C", dir, "merge-base", "--is-ancestor", pair[0], pair[1]).Run(); err != nil {
+			t.Errorf("%s is not an ancestor of %s after sync with an untracked branch", pair[0], pair[1])
+		}
+	}
+}
+
+func TestSyncBatchesPRStates(t *testing.T) {
+	dir := setupTestEnv(t)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	for _, name := range []string{"bt-merged", "bt-open", "bt-old"} {
+		gitRun(t, dir, "checkout", "main")
+		resetCobraFlags()
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	setPR(t, dir, "bt-merged", 1)
+	setPR(t, dir, "bt-open", 2)
+	setPR(t, dir, "bt-old", 3) // too old for the batch; viewed on its own
+	t.Setenv("FAKEGH_PR_LIST", `[{"number": 1, "state": "MERGED", "author": {"login": "a"}}, {"number": 2, "state": "OPEN", "author": {"login": "a"}}]`)
+
+	// No remote: --no-fetch is what lets sync run at all.
+	resetCobraFlags()
+	if err := runTier(t, "sync", "--no-snapshot"); err == nil {
+		t.Fatal("sync without a remote should fail to fetch")
+	}
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "sync", "--no-snapshot", "--no-fetch", "--json"); err != nil {
+			t.Fatalf("frond sync --no-fetch: %v", err)
+		}
+	})
+	var r syncResult
+	if err := json.Unmarshal([]byte(out), &r); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if !slices.Equal(r.Merged, []string{"bt-merged"}) {
+		t.Errorf("merged = %v, want [bt-merged]", r.Merged)
+	}
+
+	data, err := os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := string(data)
+	if strings.Count(calls, "pr list") != 1 || !strings.Contains(calls, "pr view 3 ") {
+		t.Errorf("want one pr list and a view of PR 3 only; gh calls:\n%s", calls)
+	}
+	if strings.Contains(calls, "pr view 1 ") || strings.Contains(calls, "pr view 2 ") {
+		t.Errorf("batched PRs were viewed one by one; gh calls:\n%s", calls)
+	}
+}
+
+func TestSyncNotifyMerged(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	for _, name := range []string{"nm-dep", "nm-open"} {
+		gitRun(t, dir, "checkout", "main")
+		resetCobraFlags()
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	setPR(t, dir, "nm-dep", 1)
+	setPR(t, dir, "nm-open", 2)
+	t.Setenv("FAKEGH_PR_LIST", `[{"number": 1, "state": "MERGED", "author": {"login": "alice"}}, {"number": 2, "state": "OPEN", "author": {"login": "bob"}}]`)
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "sync", "--no-snapshot", "--no-fetch", "--notify-merged"); err != nil {
+			t.Fatalf("frond sync --notify-merged: %v", err)
+		}
+	})
+	if !strings.Contains(out, "nm-dep (PR #1 by @alice) merged") {
+		t.Errorf("output missing merge author:\n%s", out)
+	}
+	if m := readState(t, dir).Merged["nm-dep"]; m.Author != "alice" {
+		t.Errorf("recorded merge = %+v, want author alice", m)
+	}
+
+	// The JSON carries the same, and only for merged branches.
+	setPR(t, dir, "nm-open", 1)
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "sync", "--no-snapshot", "--no-fetch", "--notify-merged", "--json"); err != nil {
+			t.Fatalf("frond sync --notify-merged --json: %v", err)
+		}
+	})
+	var r syncResult
+	if err := json.Unmarshal([]byte(out), &r); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if len(r.MergedAuthors) != 1 || r.MergedAuthors["nm-open"] != "alice" {
+		t.Errorf("merged_authors = %v, want nm-open by alice", r.MergedAuthors)
+	}
+}
+
+func TestSyncConflictStrategy(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	writeShared := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "shared.txt"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeShared("original\n")
+	gitRun(t, dir, "add", "shared.txt")
+	gitRun(t, dir, "commit", "-m", "add shared")
+
+	// clash conflicts with main; clash-child is stacked on it; calm is independent.
+	if err := runTier(t, "new", "clash"); err != nil {
+		t.Fatalf("frond new clash: %v", err)
+	}
+	writeShared("branch\n")
+	gitRun(t, dir, "commit", "-am", "branch change")
+	resetCobraFlags()
+	if err := runTier(t, "new", "clash-child"); err != nil {
+		t.Fatalf("frond new clash-child: %v", err)
+	}
+	gitRun(t, dir, "checkout", "main")
+	resetCobraFlags()
+	if err := runTier(t, "new", "calm"); err != nil {
+		t.Fatalf("frond new calm: %v", err)
+	}
+	gitRun(t, dir, "checkout", "main")
+	writeShared("main\n")
+	gitRun(t, dir, "commit", "-am", "main change")
+
+	resetCobraFlags()
+	if err := runTier(t, "sync", "--conflict-strategy", "later"); err == nil {
+		t.Error("expected error for an unknown --conflict-strategy")
+	}
+
+	resetCobraFlags()
+	var err error
+	out := captureStdout(t, func() {
+		err = runTier(t, "sync", "--conflict-strategy", "skip", "--no-snapshot", "--json")
+	})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("sync --conflict-strategy skip error = %v, want exit 2", err)
+	}
+	var r syncResult
+	if err := json.Unmarshal([]byte(out), &r); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if !slices.Equal(r.Conflicts, []string{"clash"}) || !slices.Equal(r.Rebased, []string{"calm"}) {
+		t.Errorf("conflicts = %v, rebased = %v; want [clash], [calm]", r.Conflicts, r.Rebased)
+	}
+	if !strings.Contains(r.Skipped["clash-child"], "conflicted clash") || r.InProgress != "" {
+		t.Errorf("skipped = %v, in_progress = %q", r.Skipped, r.InProgress)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "rebase-merge")); !os.IsNotExist(err) {
+		t.Error("skip left a rebase in progress")
+	}
+
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		err = runTier(t, "sync", "--conflict-strategy", "stop", "--no-snapshot", "--json")
+	})
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("sync --conflict-strategy stop error = %v, want exit 2", err)
+	}
+	r = syncResult{}
+	if err := json.Unmarshal([]byte(out), &r); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if r.InProgress != "clash" || len(r.Skipped) != 0 {
+		t.Errorf("in_progress = %q, skipped = %v; want clash and nothing skipped", r.InProgress, r.Skipped)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "rebase-merge")); err != nil {
+		t.Errorf("stop should leave the rebase in progress: %v", err)
+	}
+}
+
+func TestAbortStoppedRebase(t *testing.T) {
+	dir := setupTestEnv(t)
+	startConflictingRebase(t, dir)
+
+	if err := runTier(t, "abort"); err != nil {
+		t.Fatalf("frond abort: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "rebase-merge")); !os.IsNotExist(err) {
+		t.Error("rebase-merge directory still present after abort")
+	}
+}
+
+func TestMutatingCommandsRefuseDuringRebase(t *testing.T) {
+	dir := setupTestEnv(t)
+	if err := runTier(t, "new", "tracked"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "checkout", "main")
+	startConflictingRebase(t, dir)
+
+	for _, args := range [][]string{
+		{"new", "blocked"}, {"untrack", "tracked"}, {"sync"}, {"undo"}, {"prune", "--merged"},
+		{"config", "stack_trailers", "true"}, {"adopt-pr", "tracked", "7"},
+	} {
+		resetCobraFlags()
+		err := runTier(t, args...)
+		if err == nil || !strings.Contains(err.Error(), "rebase is in progress") {
+			t.Errorf("frond %s error = %v, want rebase-in-progress refusal", strings.Join(args, " "), err)
+		}
+	}
+
+	// Status still works and only warns.
+	if err := runTier(t, "status"); err != nil {
+		t.Errorf("frond status during rebase: %v", err)
+	}
+}
+
+func TestExecuteJSONError(t *testing.T) {
+	setupTestEnv(t)
+
+	rootCmd.SetArgs([]string{"status", "--json"})
+	var err error
+	out := captureStdout(t, func() {
+		err = Execute()
+	})
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 1 {
+		t.Fatalf("Execute() error = %v, want *ExitError with code 1", err)
+	}
+	var res errorResult
+	if jsonErr := json.Unmarshal([]byte(out), &res); jsonErr != nil {
+		t.Fatalf("parsing output: %v\n%s", jsonErr, out)
+	}
+	if !strings.Contains(res.Error, "no frond state") || res.Code != 1 {
+		t.Errorf("error result = %+v, want no-state error with code 1", res)
+	}
+}
+
+func TestExecuteHumanErrorUnchanged(t *testing.T) {
+	setupTestEnv(t)
+
+	rootCmd.SetArgs([]string{"status"})
+	err := Execute()
+	var exitErr *ExitError
+	if err == nil || errors.As(err, &exitErr) {
+		t.Fatalf("Execute() error = %v, want the plain command error", err)
+	}
+}
+
+// setPR writes a PR number for branch directly into frond.json.
+func setPR(t *testing.T, dir, branch string, pr int) {
+	t.Helper()
+	s := readState(t, dir)
+	b := s.Branches[branch]
+	b.PR = &pr
+	s.Branches[branch] = b
+	writeState(t, dir, s)
+}
+
+// writeState replaces frond.json in the temp repo with s.
+func writeState(t *testing.T, dir string, s *state.State) {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "frond.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatusFetchJSONIncludesAuthor(t *testing.T) {
+	dir := setupTestEnv(t)
+	t.Setenv("FAKEGH_PR_AUTHOR", "alice")
+
+	if err := runTier(t, "new", "authored"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	setPR(t, dir, "authored", 42)
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--fetch", "--json"); err != nil {
+			t.Fatalf("frond status --fetch --json: %v", err)
+		}
+	})
+
+	var res statusFetchResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if len(res.Branches) != 1 || res.Branches[0].PRAuthor != "alice" {
+		t.Errorf("branches = %+v, want pr_author alice", res.Branches)
+	}
+}
+
+func TestStatusJSONListsAreArrays(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	// Every list must be [] rather than null or missing, even when empty.
+	assertArrays := func(t *testing.T, out string, wantBranches int) {
+		t.Helper()
+		var res struct {
+			Branches json.RawMessage `json:"branches"`
+		}
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("parsing output: %v\n%s", err, out)
+		}
+		var branches []map[string]json.RawMessage
+		if err := json.Unmarshal(res.Branches, &branches); err != nil || branches == nil {
+			t.Fatalf("branches = %s, want an array", res.Branches)
+		}
+		if len(branches) != wantBranches {
+			t.Fatalf("got %d branches, want %d", len(branches), wantBranches)
+		}
+		for _, b := range branches {
+			for _, key := range []string{"after", "blocked_by"} {
+				if raw := string(b[key]); !strings.HasPrefix(raw, "[") {
+					t.Errorf("branch %s: %s = %q, want an array", b["name"], key, raw)
+				}
+			}
+		}
+	}
+	status := func(args ...string) string {
+		t.Helper()
+		resetCobraFlags()
+		return captureStdout(t, func() {
+			if err := runTier(t, append([]string{"status", "--json"}, args...)...); err != nil {
+				t.Fatalf("frond status %v: %v", args, err)
+			}
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		writeState(t, dir, &state.State{Version: 1, Trunk: "main", Branches: map[string]state.Branch{}})
+		assertArrays(t, status(), 0)
+		assertArrays(t, status("--fetch"), 0)
+	})
+
+	if err := runTier(t, "new", "no-deps"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	setPR(t, dir, "no-deps", 5)
+
+	t.Run("plain", func(t *testing.T) {
+		assertArrays(t, status(), 1)
+	})
+	t.Run("fetch", func(t *testing.T) {
+		out := status("--fetch")
+		if !strings.Contains(out, `"pr_state"`) {
+			t.Fatalf("--fetch output lacks pr_state:\n%s", out)
+		}
+		assertArrays(t, out, 1)
+	})
+}
+
+func TestFetchPRStatesConcurrent(t *testing.T) {
+	dir := setupTestEnv(t)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+	t.Setenv("FAKEGH_MERGED_PRS", "3,17")
+
+	// More PRs than workers, plus two branches sharing PR 7.
+	prNumbers := make(map[string]*int)
+	for i := 1; i <= 3*prFetchWorkers; i++ {
+		n := i
+		prNumbers[fmt.Sprintf("br-%d", i)] = &n
+	}
+	shared := 7
+	prNumbers["br-7-again"] = &shared
+	prNumbers["no-pr"] = nil
+
+	states := fetchPRStates(context.Background(), prNumbers)
+	if len(states) != 3*prFetchWorkers+1 {
+		t.Fatalf("got %d states, want %d", len(states), 3*prFetchWorkers+1)
+	}
+	for name, pr := range prNumbers {
+		if pr == nil {
+			if _, ok := states[name]; ok {
+				t.Errorf("%s has no PR but got a state", name)
+			}
+			continue
+		}
+		want := gh.PRStateOpen
+		if *pr == 3 || *pr == 17 {
+			want = "MERGED"
+		}
+		if got := states[name]; got.Number != *pr || got.State != want {
+			t.Errorf("%s: state = %+v, want PR #%d %s", name, got, *pr, want)
+		}
+	}
+
+	data, err := os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "pr view 7 "); n != 1 {
+		t.Errorf("PR 7 viewed %d times, want once", n)
+	}
+}
+
+func TestStatusExplain(t *testing.T) {
+	dir := setupTestEnv(t)
+	t.Setenv("FAKEGH_MERGED_PRS", "13")
+
+	steps := [][]string{
+		{"new", "ex-base"},
+		{"new", "ex-mid"},
+		{"new", "ex-dep", "--on", "main"},
+		{"new", "ex-top", "--on", "ex-mid", "--after", "ex-dep"},
+	}
+	for _, args := range steps {
+		resetCobraFlags()
+		if err := runTier(t, args...); err != nil {
+			t.Fatalf("frond %v: %v", args, err)
+		}
+	}
+	setPR(t, dir, "ex-base", 11)
+	setPR(t, dir, "ex-mid", 12)
+	setPR(t, dir, "ex-dep", 13)
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--explain", "ex-top"); err != nil {
+			t.Fatalf("frond status --explain: %v", err)
+		}
+	})
+	for _, want := range []string{
+		"'ex-top' is blocked by: ex-dep",
+		"Parent: ex-mid, PR #12 open",
+		"1. ex-base, PR #11 open (parent of ex-mid)",
+		"2. ex-dep, PR #13 merged (after of ex-top)",
+		"3. ex-mid, PR #12 open (parent of ex-top)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--explain", "ex-base", "--json"); err != nil {
+			t.Fatalf("frond status --explain --json: %v", err)
+		}
+	})
+	var res explainResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if !res.Ready || !res.ParentIsTrunk || res.Parent != "main" || len(res.Chain) != 0 || res.BlockedBy == nil {
+		t.Errorf("explain ex-base = %+v, want ready on trunk with an empty chain", res)
+	}
+}
+
+func TestSyncMaxRebaseLimit(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	for _, name := range []string{"limit-a", "limit-b"} {
+		gitRun(t, dir, "checkout", "main")
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+
+	err := runTier(t, "sync", "--max-rebase", "1")
+	if err == nil || !strings.Contains(err.Error(), "exceeding --max-rebase 1") {
+		t.Fatalf("frond sync --max-rebase 1 error = %v, want limit error", err)
+	}
+
+	if err := runTier(t, "sync", "--max-rebase", "1", "--force"); err != nil {
+		t.Fatalf("frond sync --max-rebase 1 --force: %v", err)
+	}
+}
+
+func TestSyncBranchOrderCreated(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	for _, name := range []string{"order-b", "order-a"} {
+		gitRun(t, dir, "checkout", "main")
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	if s := readState(t, dir); s.Branches["order-b"].Seq != 1 || s.Branches["order-a"].Seq != 2 {
+		t.Fatalf("seqs = %d, %d, want 1, 2", s.Branches["order-b"].Seq, s.Branches["order-a"].Seq)
+	}
+
+	if err := runTier(t, "sync", "--branch-order", "bogus"); err == nil || !strings.Contains(err.Error(), "invalid --branch-order") {
+		t.Fatalf("frond sync --branch-order bogus error = %v, want invalid value error", err)
+	}
+
+	var res syncResult
+	out := captureStdout(t, func() {
+		if err := runTier(t, "sync", "--branch-order", "created", "--json"); err != nil {
+			t.Fatalf("frond sync --branch-order created: %v", err)
+		}
+	})
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if strings.Join(res.Rebased, " ") != "order-b order-a" {
+		t.Errorf("rebased = %v, want creation order [order-b order-a]", res.Rebased)
+	}
+}
+
+func TestResolveTracked(t *testing.T) {
+	branches := map[string]state.Branch{
+		"pay/stripe-client": {Parent: "main"},
+		"pay/api":           {Parent: "main"},
+		"auth/api":          {Parent: "main"},
+	}
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr string
+	}{
+		{"pay/api", "pay/api", ""},
+		{"stripe-client", "pay/stripe-client", ""},
+		{"api", "", "ambiguous"},
+		{"nope", "", "not tracked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := resolveTracked(branches, tt.arg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveTracked(%q) error = %v, want %q", tt.arg, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveTracked(%q) = %q, %v; want %q", tt.arg, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteTrackedBranches(t *testing.T) {
+	setupTestEnv(t)
+
+	for _, name := range []string{"pay/stripe-client", "pay/api", "auth/api"} {
+		if err := runTier(t, "new", name, "--on", "main"); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+
+	untrackCmd.SetContext(context.Background())
+	got, _ := completeTrackedBranches(untrackCmd, nil, "")
+	want := []string{"auth/api", "pay/api", "pay/stripe-client", "stripe-client"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("completions = %v, want %v", got, want)
+	}
+
+	got, _ = completeTrackedBranches(untrackCmd, nil, "str")
+	if len(got) != 1 || got[0] != "stripe-client" {
+		t.Errorf("completions for %q = %v, want [stripe-client]", "str", got)
+	}
+}
+
+func TestUntrackByShortName(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "pay/stripe-client"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "untrack", "stripe-client"); err != nil {
+		t.Fatalf("frond untrack stripe-client: %v", err)
+	}
+	if _, ok := readState(t, dir).Branches["pay/stripe-client"]; ok {
+		t.Error("pay/stripe-client still tracked")
+	}
+}
+
+func TestStatusHighlight(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "new", "feat/shiny"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--highlight", "shiny"); err != nil {
+			t.Fatalf("frond status --highlight: %v", err)
+		}
+	})
+	if !strings.Contains(out, "feat/shiny  (not pushed)  👈") {
+		t.Errorf("highlight marker missing:\n%s", out)
+	}
+
+	if err := runTier(t, "status", "--highlight", "missing"); err == nil {
+		t.Error("expected error highlighting an untracked branch")
+	}
+}
+
+func TestStatusHighlightCurrent(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "here"); err != nil {
+		t.Fatalf("frond new here: %v", err)
+	}
+	gitRun(t, dir, "checkout", "main")
+	resetCobraFlags()
+	if err := runTier(t, "new", "there"); err != nil {
+		t.Fatalf("frond new there: %v", err)
+	}
+	gitRun(t, dir, "checkout", "here")
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--highlight-current", "--no-pr"); err != nil {
+			t.Fatalf("frond status --highlight-current: %v", err)
+		}
+	})
+	if !strings.Contains(out, "── here  👈") || strings.Contains(out, "there  👈") {
+		t.Errorf("--highlight-current output:\n%s", out)
+	}
+
+	// An explicit --highlight wins.
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--highlight-current", "--highlight", "there", "--no-pr"); err != nil {
+			t.Fatalf("frond status --highlight-current --highlight: %v", err)
+		}
+	})
+	if !strings.Contains(out, "there  👈") || strings.Contains(out, "── here  👈") {
+		t.Errorf("explicit --highlight output:\n%s", out)
+	}
+}
+
+func TestSyncAuthorOnlySkipsOthersBranches(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	t.Setenv("FAKEGH_USER", "alice")
+	t.Setenv("FAKEGH_PR_AUTHOR", "bob")
+
+	if err := runTier(t, "new", "bobs-branch"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	setPR(t, dir, "bobs-branch", 42)
+	gitRun(t, dir, "checkout", "main")
+	if err := runTier(t, "new", "my-local"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "sync", "--author-only", "--json"); err != nil {
+			t.Fatalf("frond sync --author-only: %v", err)
+		}
+	})
+
+	var res syncResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if res.Skipped["bobs-branch"] != "authored by @bob" {
+		t.Errorf("skipped = %v, want bobs-branch authored by @bob", res.Skipped)
+	}
+	if len(res.Rebased) != 1 || res.Rebased[0] != "my-local" {
+		t.Errorf("rebased = %v, want [my-local]", res.Rebased)
+	}
+}
+
+func TestStatusMine(t *testing.T) {
+	dir := setupTestEnv(t)
+	t.Setenv("FAKEGH_USER", "alice")
+	t.Setenv("FAKEGH_PR_AUTHOR", "bob")
+
+	for _, name := range []string{"pushed", "local-only"} {
+		gitRun(t, dir, "checkout", "main")
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	setPR(t, dir, "pushed", 42)
+
+	names := func(args ...string) []string {
+		t.Helper()
+		resetCobraFlags()
+		out := captureStdout(t, func() {
+			if err := runTier(t, append([]string{"status", "--json"}, args...)...); err != nil {
+				t.Fatalf("frond status %v: %v", args, err)
+			}
+		})
+		var res statusFetchResult
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("parsing output: %v\n%s", err, out)
+		}
+		var got []string
+		for _, b := range res.Branches {
+			got = append(got, b.Name)
+		}
+		slices.Sort(got)
+		return got
+	}
+
+	if got := names("--mine"); len(got) != 0 {
+		t.Errorf("--mine with bob's PR = %v, want none", got)
+	}
+	if got := names("--mine", "--include-unpushed"); !slices.Equal(got, []string{"local-only"}) {
+		t.Errorf("--mine --include-unpushed = %v, want [local-only]", got)
+	}
+
+	t.Setenv("FAKEGH_PR_AUTHOR", "alice")
+	if got := names("--mine"); !slices.Equal(got, []string{"pushed"}) {
+		t.Errorf("--mine with alice's PR = %v, want [pushed]", got)
+	}
+}
+
+func TestHistoryAndUndo(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "oops"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "untrack", "oops"); err != nil {
+		t.Fatalf("frond untrack: %v", err)
+	}
+	if err := runTier(t, "undo"); err != nil {
+		t.Fatalf("frond undo: %v", err)
+	}
+	if _, ok := readState(t, dir).Branches["oops"]; !ok {
+		t.Error("oops not tracked again after undo")
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "history", "--json"); err != nil {
+			t.Fatalf("frond history: %v", err)
+		}
+	})
+	var res historyResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	var commands []string
+	for _, e := range res.Entries {
+		commands = append(commands, e.Command)
+	}
+	if want := []string{"new", "untrack", "undo"}; !slices.Equal(commands[len(commands)-3:], want) {
+		t.Errorf("commands = %v, want suffix %v", commands, want)
+	}
+}
+
+func TestUndoRevertsLatestMutation(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "feat"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "branch", "rel", "main")
+	if err := runTier(t, "set-base", "feat", "rel"); err != nil {
+		t.Fatalf("frond set-base: %v", err)
+	}
+	if err := runTier(t, "undo"); err != nil {
+		t.Fatalf("frond undo: %v", err)
+	}
+
+	b, ok := readState(t, dir).Branches["feat"]
+	if !ok {
+		t.Fatal("undo reverted 'new' instead of 'set-base'")
+	}
+	if b.BaseOverride != "" {
+		t.Errorf("BaseOverride = %q after undo, want empty", b.BaseOverride)
+	}
+}
+
+func TestUndoJSONAndNothingToUndo(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "undo"); err == nil {
+		t.Error("expected error with no history")
+	}
+
+	if err := runTier(t, "new", "undo-me"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	out := captureStdout(t, func() {
+		if err := runTier(t, "undo", "--json"); err != nil {
+			t.Fatalf("frond undo --json: %v", err)
+		}
+	})
+	var res undoResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if res.Undone.Command != "new" || !slices.Contains(res.Undone.Summary, "track undo-me (parent main)") {
+		t.Errorf("undone = %+v", res.Undone)
+	}
+}
+
+func TestSyncSnapshotAndRestore(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	if err := runTier(t, "new", "snap-branch"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "sync", "--json"); err != nil {
+			t.Fatalf("frond sync --json: %v", err)
+		}
+	})
+	var res syncResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if res.Snapshot == "" {
+		t.Fatalf("sync result has no snapshot id:\n%s", out)
+	}
+
+	if err := runTier(t, "untrack", "snap-branch"); err != nil {
+		t.Fatalf("frond untrack: %v", err)
+	}
+	resetCobraFlags()
+	if err := runTier(t, "undo", res.Snapshot); err != nil {
+		t.Fatalf("frond undo %s: %v", res.Snapshot, err)
+	}
+	if _, ok := readState(t, dir).Branches["snap-branch"]; !ok {
+		t.Error("snap-branch not restored from snapshot")
+	}
+
+	out = captureStdout(t, func() {
+		if err := runTier(t, "sync", "--json", "--no-snapshot"); err != nil {
+			t.Fatalf("frond sync --no-snapshot: %v", err)
+		}
+	})
+	res = syncResult{}
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if res.Snapshot != "" {
+		t.Errorf("snapshot = %q with --no-snapshot, want empty", res.Snapshot)
+	}
+}
+
+func TestStatusDiffBase(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	if err := runTier(t, "new", "diff-branch"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "sync"); err != nil {
+		t.Fatalf("frond sync: %v", err)
+	}
+	if readState(t, dir).LastSyncedTrunk == "" {
+		t.Fatal("sync did not record last_synced_trunk")
+	}
+
+	gitRun(t, dir, "checkout", "main")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "trunk moved")
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--diff-base", "--json"); err != nil {
+			t.Fatalf("frond status --diff-base: %v", err)
+		}
+	})
+	var res statusJSONResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if res.TrunkNewCommits == nil || *res.TrunkNewCommits != 1 {
+		t.Errorf("trunk_new_commits = %v, want 1", res.TrunkNewCommits)
+	}
+}
+
+func TestConfirmHonorsNoInteractive(t *testing.T) {
+	resetCobraFlags()
+	origTerm, origIn, origOut := isTerminal, promptIn, promptOut
+	t.Cleanup(func() {
+		isTerminal, promptIn, promptOut = origTerm, origIn, origOut
+		noInteractive = false
+	})
+	isTerminal = func() bool { return true }
+	promptOut = io.Discard
+
+	promptIn = strings.NewReader("y\n")
+	if ok, err := confirm("proceed?", false); err != nil || !ok {
+		t.Errorf("interactive confirm = %v, %v; want true", ok, err)
+	}
+
+	noInteractive = true
+	promptIn = strings.NewReader("y\n")
+	if ok, _ := confirm("proceed?", false); ok {
+		t.Error("--no-interactive confirm should return the default")
+	}
+	noInteractive = false
+
+	t.Setenv("FROND_NO_INTERACTIVE", "1")
+	if interactive() {
+		t.Error("FROND_NO_INTERACTIVE=1 should disable prompts")
+	}
+	t.Setenv("FROND_NO_INTERACTIVE", "0")
+	if !interactive() {
+		t.Error("FROND_NO_INTERACTIVE=0 should leave prompts enabled")
+	}
+}
+
+func TestPushBaseBranchFromGit(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	origTerm, origIn, origOut := isTerminal, promptIn, promptOut
+	t.Cleanup(func() { isTerminal, promptIn, promptOut = origTerm, origIn, origOut })
+	isTerminal = func() bool { return true }
+	promptOut = io.Discard
+
+	if err := runTier(t, "new", "drift-1"); err != nil {
+		t.Fatalf("frond new drift-1: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "one")
+	if err := runTier(t, "new", "drift-2"); err != nil {
+		t.Fatalf("frond new drift-2: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "two")
+
+	// Record the wrong parent, as if the branch was rebased outside frond.
+	s := readState(t, dir)
+	b := s.Branches["drift-2"]
+	b.Parent = "main"
+	s.Branches["drift-2"] = b
+	writeState(t, dir, s)
+
+	promptIn = strings.NewReader("n\n")
+	if err := runTier(t, "push", "--base-branch-from-git"); err != nil {
+		t.Fatalf("frond push --base-branch-from-git: %v", err)
+	}
+	if got := readState(t, dir).Branches["drift-2"].Parent; got != "main" {
+		t.Errorf("parent after declining = %q, want main", got)
+	}
+
+	resetCobraFlags()
+	promptIn = strings.NewReader("y\n")
+	if err := runTier(t, "push", "--base-branch-from-git"); err != nil {
+		t.Fatalf("frond push --base-branch-from-git: %v", err)
+	}
+	if got := readState(t, dir).Branches["drift-2"].Parent; got != "drift-1" {
+		t.Errorf("parent after accepting = %q, want drift-1", got)
+	}
+}
+
+func TestPushBaseBranchFromGitYes(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	setupPRCounter(t, dir)
+
+	if err := runTier(t, "new", "fix-1"); err != nil {
+		t.Fatalf("frond new fix-1: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "one")
+	if err := runTier(t, "new", "fix-2"); err != nil {
+		t.Fatalf("frond new fix-2: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "two")
+	if err := runTier(t, "push"); err != nil {
+		t.Fatalf("frond push: %v", err)
+	}
+
+	s := readState(t, dir)
+	b := s.Branches["fix-2"]
+	b.Parent = "main"
+	s.Branches["fix-2"] = b
+	writeState(t, dir, s)
+
+	// Non-interactive: --yes applies the fix, and --skip-unchanged must
+	// not lose it.
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "push", "--base-branch-from-git", "--yes", "--skip-unchanged", "--json"); err != nil {
+			t.Fatalf("frond push --base-branch-from-git --yes: %v", err)
+		}
+	})
+	var res pushResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if !res.Skipped || res.DetectedParent != "fix-1" {
+		t.Errorf("result = %+v, want skipped with detected_parent fix-1", res)
+	}
+	if got := readState(t, dir).Branches["fix-2"].Parent; got != "fix-1" {
+		t.Errorf("parent after --yes = %q, want fix-1", got)
+	}
+}
+
+func TestPushTrailers(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	if err := runTier(t, "config", "stack_trailers", "true"); err != nil {
+		t.Fatalf("frond config stack_trailers: %v", err)
+	}
+	if err := runTier(t, "new", "tagged"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "work")
+
+	if err := runTier(t, "push", "--trailer", "bad key=x"); err == nil || !strings.Contains(err.Error(), "invalid --trailer") {
+		t.Fatalf("frond push --trailer 'bad key=x' error = %v, want invalid trailer", err)
+	}
+
+	resetCobraFlags()
+	if err := runTier(t, "push", "-b", "Adds things.", "--trailer", "Stack-Id=pay"); err != nil {
+		t.Fatalf("frond push --trailer: %v", err)
+	}
+	data, err := os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Adds things.\n\nFrond-Parent: main\nStack-Id: pay") {
+		t.Errorf("PR body missing trailers; gh calls:\n%s", data)
+	}
+
+	// On an existing PR, a stale Frond-Parent is replaced and new trailers
+	// are added; other trailers stay.
+	os.Remove(recordFile)
+	t.Setenv("FAKEGH_PR_BODY", "Adds things.\r\n\r\nFrond-Parent: old\r\nStack-Id: pay")
+	resetCobraFlags()
+	if err := runTier(t, "push", "--trailer", "Ticket=PAY-12"); err != nil {
+		t.Fatalf("frond push --trailer on an existing PR: %v", err)
+	}
+	data, err = os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "pr edit 42 --body Adds things.\n\nStack-Id: pay\nFrond-Parent: main\nTicket: PAY-12") {
+		t.Errorf("PR body trailers not updated; gh calls:\n%s", data)
+	}
+
+	// A body that already carries the trailers is left alone.
+	os.Remove(recordFile)
+	t.Setenv("FAKEGH_PR_BODY", "Adds things.\n\nStack-Id: pay\nFrond-Parent: main\nTicket: PAY-12")
+	resetCobraFlags()
+	if err := runTier(t, "push", "--trailer", "Ticket=PAY-12"); err != nil {
+		t.Fatalf("frond push: %v", err)
+	}
+	data, err = os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "--body") {
+		t.Errorf("unchanged trailers edited the body; gh calls:\n%s", data)
+	}
+
+	// A last paragraph that only looks like trailers (a URL) is prose: the
+	// trailers go below it and the URL is left alone.
+	os.Remove(recordFile)
+	t.Setenv("FAKEGH_PR_BODY", "Adds things.\n\nhttps://example.com/x")
+	resetCobraFlags()
+	if err := runTier(t, "push"); err != nil {
+		t.Fatalf("frond push: %v", err)
+	}
+	data, err = os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "--body Adds things.\n\nhttps://example.com/x\n\nFrond-Parent: main") {
+		t.Errorf("URL paragraph not kept as prose; gh calls:\n%s", data)
+	}
+}
+
+func TestSetTrailers(t *testing.T) {
+	tests := []struct {
+		body, want string
+	}{
+		// Kept trailers are written back byte for byte.
+		{"Body.\n\nReviewed-by:  Bob <b@x>\nFrond-Parent: old", "Body.\n\nReviewed-by:  Bob <b@x>\nFrond-Parent: main"},
+		{"https://example.com/x", "https://example.com/x\n\nFrond-Parent: main"},
+		{"Body.\n\nNote:no space", "Body.\n\nNote:no space\n\nFrond-Parent: main"},
+		{"Body.\n\nsome key: value", "Body.\n\nsome key: value\n\nFrond-Parent: main"},
+		{"", "Frond-Parent: main"},
+	}
+	for _, tt := range tests {
+		if got := setTrailers(tt.body, []string{"Frond-Parent: main"}, []string{"Frond-Parent"}); got != tt.want {
+			t.Errorf("setTrailers(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestPushSkipUnchanged(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	if err := runTier(t, "new", "steady"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "work")
+	if err := runTier(t, "push"); err != nil {
+		t.Fatalf("frond push: %v", err)
+	}
+	if readState(t, dir).Branches["steady"].PushedSHA == "" {
+		t.Fatal("push did not record pushed_sha")
+	}
+
+	push := func() pushResult {
+		t.Helper()
+		resetCobraFlags()
+		out := captureStdout(t, func() {
+			if err := runTier(t, "push", "--skip-unchanged", "--json"); err != nil {
+				t.Fatalf("frond push --skip-unchanged: %v", err)
+			}
+		})
+		var res pushResult
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("parsing output: %v\n%s", err, out)
+		}
+		return res
+	}
+
+	if res := push(); !res.Skipped {
+		t.Errorf("unchanged branch was pushed: %+v", res)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "more work")
+	if res := push(); res.Skipped {
+		t.Errorf("changed branch was skipped: %+v", res)
+	}
+}
+
+func TestStatusUnpushed(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	for _, name := range []string{"shipped", "local"} {
+		gitRun(t, dir, "checkout", "main")
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	gitRun(t, dir, "checkout", "shipped")
+	if err := runTier(t, "push"); err != nil {
+		t.Fatalf("frond push: %v", err)
+	}
+
+	unpushed := func() []string {
+		t.Helper()
+		resetCobraFlags()
+		out := captureStdout(t, func() {
+			if err := runTier(t, "status", "--unpushed", "--json"); err != nil {
+				t.Fatalf("frond status --unpushed: %v", err)
+			}
+		})
+		var res statusJSONResult
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("parsing output: %v\n%s", err, out)
+		}
+		var names []string
+		for _, b := range res.Branches {
+			names = append(names, b.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	if got := unpushed(); !slices.Equal(got, []string{"local"}) {
+		t.Errorf("unpushed = %v, want [local]", got)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "new work")
+	if got := unpushed(); !slices.Equal(got, []string{"local", "shipped"}) {
+		t.Errorf("unpushed after commit = %v, want [local shipped]", got)
+	}
+}
+
+func TestTrackWithPR(t *testing.T) {
+	dir := setupTestEnv(t)
+	gitRun(t, dir, "branch", "adopted")
+
+	t.Setenv("FAKEGH_PR_HEAD", "someone-else")
+	if err := runTier(t, "track", "adopted", "--on", "main", "--pr", "57"); err == nil {
+		t.Fatal("expected error binding a PR whose head is another branch")
+	}
+
+	t.Setenv("FAKEGH_PR_HEAD", "adopted")
+	resetCobraFlags()
+	if err := runTier(t, "track", "adopted", "--on", "main", "--pr", "57"); err != nil {
+		t.Fatalf("frond track --pr: %v", err)
+	}
+	if pr := readState(t, dir).Branches["adopted"].PR; pr == nil || *pr != 57 {
+		t.Errorf("PR = %v, want 57", pr)
+	}
+
+	gitRun(t, dir, "branch", "twin")
+	t.Setenv("FAKEGH_PR_HEAD", "twin")
+	resetCobraFlags()
+	if err := runTier(t, "track", "twin", "--on", "main", "--pr", "57"); err == nil {
+		t.Error("expected error binding a PR already bound to another branch")
+	}
+}
+
+func TestAdoptPR(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "drifted"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+
+	t.Setenv("FAKEGH_PR_HEAD", "someone-else")
+	if err := runTier(t, "adopt-pr", "drifted", "61"); err == nil {
+		t.Fatal("expected error adopting a PR whose head is another branch")
+	}
+	if err := runTier(t, "adopt-pr", "drifted", "zero"); err == nil {
+		t.Fatal("expected error for a non-numeric PR")
+	}
+
+	t.Setenv("FAKEGH_PR_HEAD", "drifted")
+	setPR(t, dir, "drifted", 5)
+	out := captureStdout(t, func() {
+		if err := runTier(t, "adopt-pr", "drifted", "61", "--json"); err != nil {
+			t.Fatalf("frond adopt-pr: %v", err)
+		}
+	})
+	var result adoptPRResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if result.PR != 61 || result.Previous == nil || *result.Previous != 5 {
+		t.Errorf("adopt-pr result = %+v, want PR 61 replacing 5", result)
+	}
+	if pr := readState(t, dir).Branches["drifted"].PR; pr == nil || *pr != 61 {
+		t.Errorf("PR = %v, want 61", pr)
+	}
+}
+
+func TestStatusNoPR(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "new", "quiet"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--no-pr"); err != nil {
+			t.Fatalf("frond status --no-pr: %v", err)
+		}
+	})
+	if strings.Contains(out, "not pushed") || !strings.Contains(out, "quiet  [ready]") {
+		t.Errorf("--no-pr output:\n%s", out)
+	}
+}
+
+func TestStatusNoReadiness(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "new", "quiet"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--no-readiness"); err != nil {
+			t.Fatalf("frond status --no-readiness: %v", err)
+		}
+	})
+	if strings.Contains(out, "[ready]") || !strings.Contains(out, "quiet  (not pushed)") {
+		t.Errorf("--no-readiness output:\n%s", out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--no-readiness", "--json"); err != nil {
+			t.Fatalf("frond status --no-readiness --json: %v", err)
+		}
+	})
+	if strings.Contains(out, `"ready"`) || !strings.Contains(out, `"name": "quiet"`) {
+		t.Errorf("--no-readiness --json output:\n%s", out)
+	}
+
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--json", "--since-sync"); err != nil {
+			t.Fatalf("frond status --json --since-sync: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"ready": true`) {
+		t.Errorf("wrapped status JSON lost ready:\n%s", out)
+	}
+}
+
+func TestStatusTrunkOverride(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	for _, args := range [][]string{{"new", "epic"}, {"new", "part"}, {"new", "other", "--on", "main"}} {
+		resetCobraFlags()
+		if err := runTier(t, args...); err != nil {
+			t.Fatalf("frond %v: %v", args, err)
+		}
+	}
+	before, err := os.ReadFile(filepath.Join(dir, ".git", "frond.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--trunk", "epic", "--no-pr"); err != nil {
+			t.Fatalf("frond status --trunk: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "epic\n└── part") || strings.Contains(out, "other") || strings.Contains(out, "main") {
+		t.Errorf("--trunk epic output:\n%s", out)
+	}
+
+	after, err := os.ReadFile(filepath.Join(dir, ".git", "frond.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Error("status --trunk changed frond.json")
+	}
+
+	resetCobraFlags()
+	if err := runTier(t, "status", "--trunk", "nope"); err == nil {
+		t.Error("expected error for an unknown --trunk")
+	}
+}
+
+func TestStatusASCIIJSON(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "new", "both"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--ascii-json"); err != nil {
+			t.Fatalf("frond status --ascii-json: %v", err)
+		}
+	})
+	var result statusJSONResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if result.Tree != "main\n└── both  (not pushed)  [ready]\n" {
+		t.Errorf("tree = %q", result.Tree)
+	}
+	if len(result.Branches) != 1 || result.Branches[0].Name != "both" {
+		t.Errorf("branches = %+v, want both", result.Branches)
+	}
+}
+
+func TestStatusWatch(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "live"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	setPR(t, dir, "live", 42)
+
+	// Cancelling the context stands in for Ctrl-C.
+	watch := func(args ...string) string {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+		resetCobraFlags()
+		statusCmd.SetContext(ctx)
+		return captureStdout(t, func() {
+			if err := runTier(t, append([]string{"status", "--watch", "--interval", "50ms"}, args...)...); err != nil {
+				t.Fatalf("frond status --watch %v: %v", args, err)
+			}
+		})
+	}
+
+	for _, flag := range []string{"--json-stream", "--json"} {
+		out := watch(flag)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) < 2 {
+			t.Fatalf("%s: want several reports, got:\n%s", flag, out)
+		}
+		for _, line := range lines {
+			var report statusFetchResult
+			if err := json.Unmarshal([]byte(line), &report); err != nil {
+				t.Fatalf("%s: line is not JSON: %v\n%s", flag, err, line)
+			}
+			if len(report.Branches) != 1 || report.Branches[0].PRState != "OPEN" {
+				t.Errorf("%s: report = %s, want live with pr_state OPEN", flag, line)
+			}
+		}
+	}
+
+	// Redirected, frames are separated by blank lines, with no escapes.
+	out := watch()
+	if strings.Contains(out, "\033") {
+		t.Errorf("escape codes in redirected output:\n%q", out)
+	}
+	if n := strings.Count(out, "live"); n < 2 || !strings.Contains(out, "\n\n") {
+		t.Errorf("want several frames separated by blank lines:\n%s", out)
+	}
+
+	// On a terminal the tree is redrawn on a cleared screen.
+	origTerminal := stdoutIsTerminal
+	stdoutIsTerminal = func() bool { return true }
+	t.Cleanup(func() { stdoutIsTerminal = origTerminal })
+	out = watch()
+	if n := strings.Count(out, clearScreen); n < 2 {
+		t.Errorf("screen cleared %d times, want several frames:\n%q", n, out)
+	}
+	if !strings.Contains(out, "live") {
+		t.Errorf("frames missing the tree:\n%s", out)
+	}
+}
+
+func TestStatusBudget(t *testing.T) {
+	dir := setupTestEnv(t)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	steps := [][]string{
+		{"new", "bg-1"},
+		{"new", "bg-2"},
+		{"new", "bg-3"},
+		{"new", "bg-x", "--on", "main"},
+	}
+	for _, args := range steps {
+		resetCobraFlags()
+		if err := runTier(t, args...); err != nil {
+			t.Fatalf("frond %v: %v", args, err)
+		}
+	}
+	for i, name := range []string{"bg-1", "bg-2", "bg-3", "bg-x"} {
+		setPR(t, dir, name, i+1)
+	}
+	gitRun(t, dir, "checkout", "bg-2")
+
+	// bg-2 itself, then the nearer of its neighbors by name.
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--fetch", "--budget", "2", "--json"); err != nil {
+			t.Fatalf("frond status --budget: %v", err)
+		}
+	})
+	var result statusFetchResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	want := map[string]string{"bg-1": "OPEN", "bg-2": "OPEN", "bg-3": prStateNotFetched, "bg-x": prStateNotFetched}
+	for _, b := range result.Branches {
+		if b.PRState != want[b.Name] {
+			t.Errorf("%s pr_state = %q, want %q", b.Name, b.PRState, want[b.Name])
+		}
+	}
+	data, _ := os.ReadFile(recordFile)
+	if n := strings.Count(string(data), "pr view"); n != 2 {
+		t.Errorf("%d PRs viewed, want 2; gh calls:\n%s", n, data)
+	}
+
+	resetCobraFlags()
+	if err := runTier(t, "status", "--fetch", "--budget", "-1"); err == nil {
+		t.Error("expected a negative --budget to fail")
+	}
+}
+
+func TestStatusUntracked(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "ut-tracked"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "branch", "ut-loose", "main")
+	gitRun(t, dir, "checkout", "--detach", "main")
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--untracked"); err != nil {
+			t.Fatalf("frond status --untracked: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Untracked branches:\n  ut-loose\n") {
+		t.Errorf("output missing untracked list:\n%s", out)
+	}
+	if strings.Contains(out, "HEAD") || strings.Contains(out, "  main\n") {
+		t.Errorf("detached HEAD or trunk listed as untracked:\n%s", out)
+	}
+
+	gitRun(t, dir, "branch", "-D", "ut-loose")
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--untracked", "--json"); err != nil {
+			t.Fatalf("frond status --untracked --json: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"untracked": []`) {
+		t.Errorf("want an empty untracked array:\n%s", out)
+	}
+}
+
+func TestStatusBranchOrderCreated(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	for _, name := range []string{"zeta", "alpha"} {
+		gitRun(t, dir, "checkout", "main")
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--branch-order", "created", "--no-pr"); err != nil {
+			t.Fatalf("frond status --branch-order created: %v", err)
+		}
+	})
+	if !strings.Contains(out, "├── zeta  [ready]\n└── alpha  [ready]") {
+		t.Errorf("--branch-order created output:\n%s", out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--json"); err != nil {
+			t.Fatalf("frond status --json: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"seq": 2`) {
+		t.Errorf("status --json missing seq:\n%s", out)
+	}
+}
+
+func TestConfigSetAndUnset(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "config", "lock_stale", "30s"); err != nil {
+		t.Fatalf("frond config set: %v", err)
+	}
+	if got := readState(t, dir).Config["lock_stale"]; got != "30s" {
+		t.Errorf("lock_stale = %q, want 30s", got)
+	}
+
+	if err := runTier(t, "config", "lock_stale", "-5s"); err == nil {
+		t.Error("expected error for a non-positive lock_stale")
+	}
+	if err := runTier(t, "config", "bogus", "1"); err == nil {
+		t.Error("expected error for an unknown key")
+	}
+
+	if err := runTier(t, "config", "lock_stale", "--unset"); err != nil {
+		t.Fatalf("frond config --unset: %v", err)
+	}
+	if _, ok := readState(t, dir).Config["lock_stale"]; ok {
+		t.Error("lock_stale still set after --unset")
+	}
+}
+
+func TestLockStaleFlagValidated(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "new", "stale-check", "--lock-stale", "45s"); err != nil {
+		t.Fatalf("frond new --lock-stale 45s: %v", err)
+	}
+	resetCobraFlags()
+	if err := runTier(t, "status", "--lock-stale", "0s"); err == nil {
+		t.Error("expected error for --lock-stale 0s")
+	}
+	resetCobraFlags()
+	t.Setenv("FROND_LOCK_STALE", "later")
+	if err := runTier(t, "status"); err == nil {
+		t.Error("expected error for an invalid FROND_LOCK_STALE")
+	}
+}
+
+func TestPushUpdateCommentOnly(t *testing.T) {
+	dir := setupTestEnv(t)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	if err := runTier(t, "new", "commented"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "push", "--update-comment-only"); err == nil {
+		t.Fatal("expected error for a branch without a PR")
+	}
+
+	// Stack comments need at least two PRs. No origin remote exists, so
+	// any git push would fail.
+	if err := runTier(t, "new", "commented-child"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	setPR(t, dir, "commented", 42)
+	setPR(t, dir, "commented-child", 43)
+	gitRun(t, dir, "checkout", "commented")
+	resetCobraFlags()
+	if err := runTier(t, "push", "--update-comment-only"); err != nil {
+		t.Fatalf("frond push --update-comment-only: %v", err)
+	}
+
+	data, _ := os.ReadFile(recordFile)
+	calls := string(data)
+	if strings.Contains(calls, "pr create") || strings.Contains(calls, "pr edit") {
+		t.Errorf("unexpected PR create/edit:\n%s", calls)
+	}
+	if !strings.Contains(calls, "comments") {
+		t.Errorf("stack comment was not updated:\n%s", calls)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "validate-name", "feature/ok"); err != nil {
+		t.Errorf("valid name rejected: %v", err)
+	}
+	if err := runTier(t, "validate-name", "a..b"); err == nil || !strings.Contains(err.Error(), "'..'") {
+		t.Errorf("validate-name a..b error = %v, want '..' reason", err)
+	}
+
+	var runErr error
+	out := captureStdout(t, func() {
+		runErr = runTier(t, "validate-name", "--json", "a..b")
+	})
+	var exitErr *ExitError
+	if !errors.As(runErr, &exitErr) || exitErr.Code != 1 {
+		t.Errorf("error = %v, want ExitError code 1", runErr)
+	}
+	var res validateNameResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if res.Valid || !strings.Contains(res.Reason, "'..'") {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestImportFromTrailers(t *testing.T) {
+	dir := setupTestEnv(t)
+	t.Setenv("FAKEGH_PR_LIST", `[
+		{"number": 12, "headRefName": "pay/api", "baseRefName": "pay/base", "body": "API.\n\nFrond-Parent: pay/base\nFrond-After: pay/schema,pay/merged"},
+		{"number": 11, "headRefName": "pay/base", "baseRefName": "main", "body": "Frond-Parent: main"},
+		{"number": 13, "headRefName": "pay/schema", "baseRefName": "pay/base", "body": "Frond-Parent: pay/base"},
+		{"number": 14, "headRefName": "unrelated", "baseRefName": "main", "body": "no trailers here"}
+	]`)
+
+	var res importResult
+	out := captureStdout(t, func() {
+		if err := runTier(t, "import-from-trailers", "--json"); err != nil {
+			t.Fatalf("frond import-from-trailers: %v", err)
+		}
+	})
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if strings.Join(res.Imported, " ") != "pay/base pay/api pay/schema" {
+		t.Errorf("imported = %v, want PR order", res.Imported)
+	}
+
+	s := readState(t, dir)
+	api := s.Branches["pay/api"]
+	if api.Parent != "pay/base" || strings.Join(api.After, ",") != "pay/schema" || api.PR == nil || *api.PR != 12 {
+		t.Errorf("pay/api = %+v, want parent pay/base, after [pay/schema], PR 12", api)
+	}
+	if _, tracked := s.Branches["unrelated"]; tracked {
+		t.Error("branch without trailers was imported")
+	}
+
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "import-from-trailers", "--json"); err != nil {
+			t.Fatalf("frond import-from-trailers (again): %v", err)
+		}
+	})
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if len(res.Imported) != 0 || len(res.Skipped) != 3 {
+		t.Errorf("second import = %+v, want all skipped", res)
+	}
+}
+
+func TestImportFromTrailersDanglingParent(t *testing.T) {
+	dir := setupTestEnv(t)
+	t.Setenv("FAKEGH_PR_LIST", `[
+		{"number": 21, "headRefName": "dg-base", "baseRefName": "main", "body": "Frond-Parent: main"},
+		{"number": 22, "headRefName": "dg-top", "baseRefName": "dg-base", "body": "Frond-Parent: dg-gone"},
+		{"number": 23, "headRefName": "dg-orphan", "baseRefName": "elsewhere", "body": "Frond-Parent: dg-gone"},
+		{"number": 24, "headRefName": "dg-prose", "baseRefName": "main", "body": "Set Frond-Parent: main in the body.\n\nThanks!"}
+	]`)
+
+	if err := runTier(t, "import-from-trailers"); err != nil {
+		t.Fatalf("frond import-from-trailers: %v", err)
+	}
+	s := readState(t, dir)
+	if got := s.Branches["dg-top"].Parent; got != "dg-base" {
+		t.Errorf("dg-top parent = %q, want the PR base dg-base", got)
+	}
+	if got := s.Branches["dg-orphan"].Parent; got != "main" {
+		t.Errorf("dg-orphan parent = %q, want the trunk", got)
+	}
+	if _, tracked := s.Branches["dg-prose"]; tracked {
+		t.Error("a trailer mentioned in prose, not in the trailing block, was imported")
+	}
+}
+
+func TestImportFromTrailersRejectsCycle(t *testing.T) {
+	dir := setupTestEnv(t)
+	t.Setenv("FAKEGH_PR_LIST", `[
+		{"number": 1, "headRefName": "a", "body": "Frond-Parent: main\nFrond-After: b"},
+		{"number": 2, "headRefName": "b", "body": "Frond-Parent: main\nFrond-After: a"}
+	]`)
+
+	err := runTier(t, "import-from-trailers")
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("frond import-from-trailers error = %v, want cycle error", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, ".git", "frond.json")); statErr == nil {
+		if len(readState(t, dir).Branches) != 0 {
+			t.Error("cyclic import was written to state")
+		}
+	}
+}
+
+func TestStatusShowMergedAndPrune(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	if err := runTier(t, "new", "landed"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "checkout", "main")
+	if err := runTier(t, "new", "open-work"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	setPR(t, dir, "landed", 42)
+
+	t.Setenv("FAKEGH_PR_STATE", "MERGED")
+	if err := runTier(t, "sync"); err != nil {
+		t.Fatalf("frond sync: %v", err)
+	}
+	s := readState(t, dir)
+	if _, tracked := s.Branches["landed"]; tracked {
+		t.Fatal("merged branch still tracked")
+	}
+	if m, ok := s.Merged["landed"]; !ok || m.Parent != "main" || m.MergedAt.IsZero() {
+		t.Fatalf("merged record = %+v, %v", m, ok)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status"); err != nil {
+			t.Fatalf("frond status: %v", err)
+		}
+	})
+	if strings.Contains(out, "landed") {
+		t.Errorf("merged branch shown without --show-merged:\n%s", out)
+	}
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--show-merged"); err != nil {
+			t.Fatalf("frond status --show-merged: %v", err)
+		}
+	})
+	if !strings.Contains(out, "landed  #42  [merged]") {
+		t.Errorf("--show-merged output:\n%s", out)
+	}
+
+	if err := runTier(t, "prune"); err == nil {
+		t.Error("frond prune without --merged should fail")
+	}
+	if err := runTier(t, "prune", "--merged"); err != nil {
+		t.Fatalf("frond prune --merged: %v", err)
+	}
+	if len(readState(t, dir).Merged) != 0 {
+		t.Error("merged records left after prune")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "pay/diffed"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "feature.txt"), []byte("feature\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", "feature.txt")
+	gitRun(t, dir, "commit", "-m", "feature")
+
+	var res diffResult
+	out := captureStdout(t, func() {
+		if err := runTier(t, "diff", "diffed", "--stat", "--json"); err != nil {
+			t.Fatalf("frond diff --stat: %v", err)
+		}
+	})
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if res.Branch != "pay/diffed" || res.Parent != "main" || !strings.Contains(res.Diff, "feature.txt") {
+		t.Errorf("diff result = %+v", res)
+	}
+
+	gitRun(t, dir, "checkout", "main")
+	if err := runTier(t, "diff"); err == nil || !strings.Contains(err.Error(), "not tracked") {
+		t.Errorf("frond diff on untracked branch error = %v, want not tracked", err)
+	}
+}
+
+func TestStatusGroupByAuthor(t *testing.T) {
+	dir := setupTestEnv(t)
+	t.Setenv("FAKEGH_PR_AUTHOR", "alice")
+
+	for _, name := range []string{"owned", "loose"} {
+		gitRun(t, dir, "checkout", "main")
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	setPR(t, dir, "owned", 7)
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--group-by-author"); err != nil {
+			t.Fatalf("frond status --group-by-author: %v", err)
+		}
+	})
+	want := "@alice\n  owned  #7  [ready]\n\nunassigned\n  loose  (not pushed)  [ready]\n"
+	if out != want {
+		t.Errorf("--group-by-author output:\ngot:\n%s\nwant:\n%s", out, want)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--group-by-author", "--json"); err != nil {
+			t.Fatalf("frond status --group-by-author --json: %v", err)
+		}
+	})
+	var res statusFetchResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if len(res.Groups["alice"]) != 1 || len(res.Groups["unassigned"]) != 1 {
+		t.Errorf("groups = %v", res.Groups)
+	}
+}
+
+func TestPushWeb(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	bare := strings.TrimSpace(gitOutput(t, dir, "remote", "get-url", "origin"))
+	gitRun(t, dir, "remote", "set-url", "origin", "https://github.com/test/repo.git")
+	gitRun(t, dir, "remote", "set-url", "--push", "origin", bare)
+
+	var opened []string
+	origOpen := openBrowser
+	t.Cleanup(func() { openBrowser = origOpen })
+	openBrowser = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+
+	if err := runTier(t, "new", "browsed"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "work")
+	if err := runTier(t, "push", "--web"); err != nil {
+		t.Fatalf("frond push --web: %v", err)
+	}
+	if len(opened) != 1 || opened[0] != "https://github.com/test/repo/pull/42" {
+		t.Errorf("opened = %v, want the new PR's URL", opened)
+	}
+}
+
+func TestStatusCheckBaseDrift(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "drift-base"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "new", "drift-top", "--on", "drift-base"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	setPR(t, dir, "drift-base", 8)
+	setPR(t, dir, "drift-top", 9) // fakegh reports base "main"
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--check-base-drift"); err != nil {
+			t.Fatalf("frond status --check-base-drift: %v", err)
+		}
+	})
+	if !strings.Contains(out, "drift-top  #9  [ready]  (base drift: main)") || strings.Contains(out, "drift-base  #8  [ready]  (base drift") {
+		t.Errorf("--check-base-drift output:\n%s", out)
+	}
+}
+
+func TestPushNotAuthenticated(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	t.Setenv("FAKEGH_UNAUTHENTICATED", "1")
+
+	if err := runTier(t, "new", "no-auth"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "work")
+
+	err := runTier(t, "push")
+	if !errors.Is(err, gh.ErrNotAuthenticated) || !strings.Contains(err.Error(), "run 'gh auth login'") {
+		t.Fatalf("frond push error = %v, want a gh auth login hint", err)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	setupTestEnv(t)
+
+	run := func() (preflightResult, error) {
+		t.Helper()
+		resetCobraFlags()
+		var err error
+		out := captureStdout(t, func() {
+			err = runTier(t, "preflight", "--json")
+		})
+		var res preflightResult
+		if jsonErr := json.Unmarshal([]byte(out), &res); jsonErr != nil {
+			t.Fatalf("parsing output: %v\n%s", jsonErr, out)
+		}
+		return res, err
+	}
+
+	res, err := run()
+	if err != nil || !res.OK {
+		t.Fatalf("preflight = %+v, %v; want ok", res, err)
+	}
+	if last := res.Checks[len(res.Checks)-1]; last.Name != "state" || last.OK {
+		t.Errorf("state check = %+v, want a non-critical failure before any frond command", last)
+	}
+
+	t.Setenv("FAKEGH_UNAUTHENTICATED", "1")
+	res, err = run()
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || res.OK {
+		t.Fatalf("preflight unauthenticated = %+v, %v; want failure", res, err)
+	}
+}
+
+func TestDuplicatePRWarning(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	for _, name := range []string{"dup-a", "dup-b"} {
+		resetCobraFlags()
+		if err := runTier(t, "new", name, "--on", "main"); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	setPR(t, dir, "dup-a", 8)
+	setPR(t, dir, "dup-b", 8)
+
+	resetCobraFlags()
+	stderr := captureStderr(t, func() {
+		captureStdout(t, func() {
+			if err := runTier(t, "status"); err != nil {
+				t.Fatalf("frond status: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(stderr, "warning: PR #8 is claimed by dup-a, dup-b") {
+		t.Errorf("stderr = %q, want duplicate PR warning", stderr)
+	}
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "doctor", "--json"); err != nil {
+			t.Fatalf("frond doctor: %v", err)
+		}
+	})
+	var res preflightResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	i := slices.IndexFunc(res.Checks, func(c preflightCheck) bool { return c.Name == "pr_numbers" })
+	if i < 0 || res.Checks[i].OK || !strings.Contains(res.Checks[i].Detail, "dup-a, dup-b") {
+		t.Errorf("checks = %+v, want a failing pr_numbers check naming both branches", res.Checks)
+	}
+}
+
+func TestStatusOnly(t *testing.T) {
+	setupTestEnv(t)
+
+	if err := runTier(t, "new", "only-base"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "new", "only-top", "--on", "only-base"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "new", "only-other", "--on", "main"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--only", "only-top", "--json"); err != nil {
+			t.Fatalf("frond status --only: %v", err)
+		}
+	})
+	var res statusJSONResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if len(res.Branches) != 1 || res.Branches[0].Name != "only-top" {
+		t.Errorf("--only --json branches = %+v, want just only-top", res.Branches)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--only", "only-top", "--no-pr"); err != nil {
+			t.Fatalf("frond status --only: %v", err)
+		}
+	})
+	if !strings.Contains(out, "only-base") || strings.Contains(out, "only-other") {
+		t.Errorf("--only tree should keep ancestors and drop others:\n%s", out)
+	}
+
+	err := runTier(t, "status", "--only", "only-top,nope,nada")
+	if err == nil || !strings.Contains(err.Error(), "nope, nada") {
+		t.Errorf("--only with unknown names error = %v, want both named", err)
+	}
+}
+
+func TestSyncExclude(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	if err := runTier(t, "new", "exp"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "new", "exp-child", "--on", "exp"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "new", "regular", "--on", "main"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+
+	if err := runTier(t, "sync", "--exclude", "nope"); err == nil || !strings.Contains(err.Error(), "--exclude") {
+		t.Fatalf("frond sync --exclude nope error = %v, want unknown branch error", err)
+	}
+
+	resetCobraFlags()
+	var res syncResult
+	out := captureStdout(t, func() {
+		if err := runTier(t, "sync", "--exclude", "exp", "--json"); err != nil {
+			t.Fatalf("frond sync --exclude: %v", err)
+		}
+	})
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if res.Skipped["exp"] != "excluded" || res.Skipped["exp-child"] != "excluded" {
+		t.Errorf("skipped = %v, want exp and exp-child excluded", res.Skipped)
+	}
+	if strings.Join(res.Rebased, " ") != "regular" {
+		t.Errorf("rebased = %v, want [regular]", res.Rebased)
+	}
+}
+
+func TestTopAndBottom(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	for _, name := range []string{"nav-1", "nav-2", "nav-3"} {
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	current := func() string {
+		return strings.TrimSpace(gitOutput(t, dir, "branch", "--show-current"))
+	}
+
+	if err := runTier(t, "bottom"); err != nil {
+		t.Fatalf("frond bottom: %v", err)
+	}
+	if got := current(); got != "nav-1" {
+		t.Errorf("after bottom on %q, want nav-1", got)
+	}
+	if err := runTier(t, "top"); err != nil {
+		t.Fatalf("frond top: %v", err)
+	}
+	if got := current(); got != "nav-3" {
+		t.Errorf("after top on %q, want nav-3", got)
+	}
+
+	// A fork stops top with the choices named.
+	if err := runTier(t, "new", "nav-2b", "--on", "nav-1"); err != nil {
+		t.Fatalf("frond new nav-2b: %v", err)
+	}
+	gitRun(t, dir, "checkout", "nav-1")
+	if err := runTier(t, "top"); err == nil || !strings.Contains(err.Error(), "nav-2, nav-2b") {
+		t.Errorf("frond top at a fork error = %v, want the children listed", err)
+	}
+
+	gitRun(t, dir, "checkout", "main")
+	if err := runTier(t, "bottom"); err == nil {
+		t.Error("frond bottom on an untracked branch should fail")
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	for _, name := range []string{"ud-1", "ud-2"} {
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	current := func() string {
+		return strings.TrimSpace(gitOutput(t, dir, "branch", "--show-current"))
+	}
+
+	if err := runTier(t, "down"); err != nil {
+		t.Fatalf("frond down: %v", err)
+	}
+	if got := current(); got != "ud-1" {
+		t.Errorf("after down on %q, want ud-1", got)
+	}
+	if err := runTier(t, "down"); err != nil {
+		t.Fatalf("frond down: %v", err)
+	}
+	if got := current(); got != "main" {
+		t.Errorf("after down on %q, want main", got)
+	}
+	out := captureStdout(t, func() {
+		if err := runTier(t, "down"); err != nil {
+			t.Fatalf("frond down on trunk: %v", err)
+		}
+	})
+	if !strings.Contains(out, "already at trunk") {
+		t.Errorf("down on trunk output = %q, want already at trunk", out)
+	}
+
+	if err := runTier(t, "up"); err != nil {
+		t.Fatalf("frond up: %v", err)
+	}
+	if got := current(); got != "ud-1" {
+		t.Errorf("after up on %q, want ud-1", got)
+	}
+
+	// A fork needs the child named.
+	if err := runTier(t, "new", "ud-2b", "--on", "ud-1"); err != nil {
+		t.Fatalf("frond new ud-2b: %v", err)
+	}
+	gitRun(t, dir, "checkout", "ud-1")
+	if err := runTier(t, "up"); err == nil || !strings.Contains(err.Error(), "ud-2, ud-2b") {
+		t.Errorf("frond up at a fork error = %v, want the children listed", err)
+	}
+	if err := runTier(t, "up", "main"); err == nil {
+		t.Error("frond up to a non-child should fail")
+	}
+	out = captureStdout(t, func() {
+		if err := runTier(t, "up", "ud-2b", "--json"); err != nil {
+			t.Fatalf("frond up ud-2b: %v", err)
+		}
+	})
+	var res navigateResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing %q: %v", out, err)
+	}
+	if res.From != "ud-1" || res.To != "ud-2b" || current() != "ud-2b" {
+		t.Errorf("up ud-2b = %+v on %q, want ud-1 -> ud-2b", res, current())
+	}
+	resetCobraFlags()
+	if err := runTier(t, "up"); err == nil || !strings.Contains(err.Error(), "no children") {
+		t.Errorf("frond up at the tip error = %v, want no children", err)
+	}
+}
+
+func TestCheckoutShortName(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	for _, name := range []string{"pay/stripe-client", "pay/api", "auth/api"} {
+		resetCobraFlags()
+		if err := runTier(t, "new", name, "--on", "main"); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	current := func() string {
+		return strings.TrimSpace(gitOutput(t, dir, "branch", "--show-current"))
+	}
+
+	if err := runTier(t, "checkout", "stripe-client"); err != nil {
+		t.Fatalf("frond checkout stripe-client: %v", err)
+	}
+	if got := current(); got != "pay/stripe-client" {
+		t.Errorf("after checkout on %q, want pay/stripe-client", got)
+	}
+	if err := runTier(t, "checkout", "pay/api"); err != nil {
+		t.Fatalf("frond checkout pay/api: %v", err)
+	}
+	if got := current(); got != "pay/api" {
+		t.Errorf("after checkout on %q, want pay/api", got)
+	}
+
+	if err := runTier(t, "checkout", "api"); err == nil || !strings.Contains(err.Error(), "auth/api, pay/api") {
+		t.Errorf("ambiguous checkout error = %v, want both candidates", err)
+	}
+	if err := runTier(t, "checkout", "strip-client"); err == nil || !strings.Contains(err.Error(), "did you mean 'pay/stripe-client'") {
+		t.Errorf("typo checkout error = %v, want a suggestion", err)
+	}
+	if err := runTier(t, "checkout", "zzzzzzzz"); err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("unrelated checkout error = %v, want no suggestion", err)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"strip", "stripe", 1},
+		{"héllo", "hello", 1},
+	} {
+		if got := levenshtein(tc.a, tc.b); got != tc.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	// main <- rn-old <- rn-child; rn-dep waits on rn-old.
+	if err := runTier(t, "new", "rn-old"); err != nil {
+		t.Fatalf("frond new rn-old: %v", err)
+	}
+	if err := runTier(t, "new", "rn-child"); err != nil {
+		t.Fatalf("frond new rn-child: %v", err)
+	}
+	resetCobraFlags()
+	if err := runTier(t, "new", "rn-dep", "--on", "main", "--after", "rn-old"); err != nil {
+		t.Fatalf("frond new rn-dep: %v", err)
+	}
+	setPR(t, dir, "rn-old", 1)
+	setPR(t, dir, "rn-child", 2)
+
+	if err := runTier(t, "rename", "main", "trunk"); err == nil || !strings.Contains(err.Error(), "rename-trunk") {
+		t.Errorf("renaming the trunk error = %v, want rename-trunk hint", err)
+	}
+	if err := runTier(t, "rename", "rn-old", "rn-dep"); err == nil {
+		t.Error("renaming onto a tracked branch should fail")
+	}
+	gitRun(t, dir, "branch", "rn-taken", "main")
+	if err := runTier(t, "rename", "rn-old", "rn-taken"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("renaming onto a git branch error = %v, want already exists", err)
+	}
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "rename", "rn-old", "rn-new", "--json"); err != nil {
+			t.Fatalf("frond rename: %v", err)
+		}
+	})
+	var result renameResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing %q: %v", out, err)
+	}
+	if !slices.Equal(result.Reparented, []string{"rn-child"}) || result.PR == nil || *result.PR != 1 {
+		t.Errorf("rename result = %+v, want rn-child reparented and PR #1 reported", result)
+	}
+
+	st := readState(t, dir)
+	if _, ok := st.Branches["rn-old"]; ok {
+		t.Error("rn-old still tracked after rename")
+	}
+	if pr := st.Branches["rn-new"].PR; pr == nil || *pr != 1 {
+		t.Errorf("rn-new PR = %v, want 1", pr)
+	}
+	if got := st.Branches["rn-child"].Parent; got != "rn-new" {
+		t.Errorf("rn-child parent = %q, want rn-new", got)
+	}
+	if got := st.Branches["rn-dep"].After; !slices.Equal(got, []string{"rn-new"}) {
+		t.Errorf("rn-dep after = %v, want [rn-new]", got)
+	}
+	if got := strings.TrimSpace(gitOutput(t, dir, "rev-parse", "--verify", "--quiet", "refs/heads/rn-new")); got == "" {
+		t.Error("git branch rn-new missing after rename")
+	}
+	if data, _ := os.ReadFile(recordFile); strings.Contains(string(data), "pr edit") {
+		t.Errorf("rename retargeted a PR before rn-new is on origin; gh calls:\n%s", data)
+	}
+}
+
+func TestLog(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "log-a"); err != nil {
+		t.Fatalf("frond new log-a: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "first a")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "second a")
+	if err := runTier(t, "new", "log-b"); err != nil {
+		t.Fatalf("frond new log-b: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "log"); err != nil {
+			t.Fatalf("frond log: %v", err)
+		}
+	})
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 5 || lines[0] != "main" || lines[1] != "└── log-a" || lines[4] != "    └── log-b  (empty)" {
+		t.Fatalf("frond log =\n%s", out)
+	}
+	if !strings.HasPrefix(lines[2], "    │   ") || !strings.HasSuffix(lines[2], " second a") || !strings.HasSuffix(lines[3], " first a") {
+		t.Errorf("commit lines = %q, want newest first under log-a", lines[2:4])
+	}
+
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "log", "--json"); err != nil {
+			t.Fatalf("frond log --json: %v", err)
+		}
+	})
+	var result logResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing %q: %v", out, err)
+	}
+	if len(result.Branches) != 2 || result.Branches[0].Name != "log-a" || len(result.Branches[0].Commits) != 2 || len(result.Branches[1].Commits) != 0 {
+		t.Fatalf("log --json = %+v", result)
+	}
+	if c := result.Branches[0].Commits[0]; c.Subject != "second a" || len(c.SHA) != 40 {
+		t.Errorf("newest commit = %+v, want second a with a full sha", c)
+	}
+}
+
+func TestMove(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "mv-a"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "a work")
+	if err := runTier(t, "new", "mv-b"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "b work")
+
+	if err := runTier(t, "move", "mv-a", "--on", "mv-b"); err == nil || !strings.Contains(err.Error(), "own ancestor") {
+		t.Fatalf("moving onto a descendant error = %v, want cycle error", err)
+	}
+
+	resetCobraFlags()
+	if err := runTier(t, "move", "mv-b", "--on", "main"); err != nil {
+		t.Fatalf("frond move: %v", err)
+	}
+	if got := readState(t, dir).Branches["mv-b"].Parent; got != "main" {
+		t.Errorf("parent = %q, want main", got)
+	}
+	if log := gitOutput(t, dir, "log", "--format=%s", "main..mv-b"); strings.TrimSpace(log) != "b work" {
+		t.Errorf("mv-b commits after move = %q, want only its own", log)
+	}
+
+	// --keep-commits leaves the commits alone and only fixes bookkeeping.
+	resetCobraFlags()
+	before := gitOutput(t, dir, "rev-parse", "mv-b")
+	if err := runTier(t, "move", "mv-b", "--on", "mv-a", "--keep-commits"); err != nil {
+		t.Fatalf("frond move --keep-commits: %v", err)
+	}
+	if after := gitOutput(t, dir, "rev-parse", "mv-b"); after != before {
+		t.Error("--keep-commits rewrote the branch")
+	}
+	if got := readState(t, dir).Branches["mv-b"].Parent; got != "mv-a" {
+		t.Errorf("parent = %q, want mv-a", got)
+	}
+}
+
+func TestMoveRejectsAfterCycle(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "oc-a"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "new", "oc-b", "--on", "main", "--after", "oc-a"); err != nil {
+		t.Fatalf("frond new --after: %v", err)
+	}
+
+	// oc-b lands after oc-a, so oc-a cannot sit on top of oc-b.
+	resetCobraFlags()
+	err := runTier(t, "move", "oc-a", "--on", "oc-b", "--keep-commits")
+	if err == nil || !strings.Contains(err.Error(), "parent/after cycle") {
+		t.Fatalf("move error = %v, want parent/after cycle", err)
+	}
+	if got := readState(t, dir).Branches["oc-a"].Parent; got != "main" {
+		t.Errorf("parent = %q after refused move, want main", got)
+	}
+}
+
+func TestMoveAfterParentRewrite(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	write := func(file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "add", file)
+	}
+
+	mainTip := strings.TrimSpace(gitOutput(t, dir, "rev-parse", "main"))
+	if err := runTier(t, "new", "bs-parent"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if got := readState(t, dir).Branches["bs-parent"].BaseSHA; got != mainTip {
+		t.Errorf("base_sha = %q, want main's tip %q", got, mainTip)
+	}
+	write("parent.txt", "v1\n")
+	gitRun(t, dir, "commit", "-m", "parent work")
+	parentTip := strings.TrimSpace(gitOutput(t, dir, "rev-parse", "bs-parent"))
+
+	resetCobraFlags()
+	if err := runTier(t, "new", "bs-child"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if got := readState(t, dir).Branches["bs-child"].BaseSHA; got != parentTip {
+		t.Errorf("base_sha = %q, want bs-parent's tip %q", got, parentTip)
+	}
+	write("child.txt", "child\n")
+	gitRun(t, dir, "commit", "-m", "child work")
+
+	// Rewrite the parent so its tip is no longer in the child.
+	gitRun(t, dir, "checkout", "bs-parent")
+	write("parent.txt", "v2\n")
+	gitRun(t, dir, "commit", "--amend", "-m", "parent work v2")
+
+	resetCobraFlags()
+	if err := runTier(t, "move", "bs-child", "--on", "main"); err != nil {
+		t.Fatalf("frond move: %v", err)
+	}
+	if log := strings.TrimSpace(gitOutput(t, dir, "log", "--format=%s", "main..bs-child")); log != "child work" {
+		t.Errorf("bs-child commits after move = %q, want only its own", log)
+	}
+	if got := readState(t, dir).Branches["bs-child"].BaseSHA; got != mainTip {
+		t.Errorf("base_sha after move = %q, want main's tip %q", got, mainTip)
+	}
+}
+
+func TestReorder(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	write := func(file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(t, dir, "add", file)
+	}
+
+	// main <- ro-a <- ro-b <- ro-c <- ro-d; ro-d edits what ro-c wrote.
+	for _, name := range []string{"ro-a", "ro-b", "ro-c", "ro-d"} {
+		resetCobraFlags()
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+		file := name + ".txt"
+		if name == "ro-d" {
+			file = "ro-c.txt"
+		}
+		write(file, name+"\n")
+		gitRun(t, dir, "commit", "-m", name+" work")
+	}
+	setPR(t, dir, "ro-b", 2)
+	setPR(t, dir, "ro-c", 3)
+	gitRun(t, dir, "checkout", "main")
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	if err := runTier(t, "reorder", "ro-a", "--before", "ro-a"); err == nil {
+		t.Error("reordering a branch before itself should fail")
+	}
+
+	// ro-d cannot go below ro-c: its change needs ro-c's file.
+	resetCobraFlags()
+	tipC := strings.TrimSpace(gitOutput(t, dir, "rev-parse", "ro-c"))
+	if err := runTier(t, "reorder", "ro-d", "--before", "ro-c"); err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Fatalf("conflicting reorder error = %v, want conflicts", err)
+	}
+	if got := strings.TrimSpace(gitOutput(t, dir, "rev-parse", "ro-c")); got != tipC {
+		t.Error("ro-c was left rewritten after a failed reorder")
+	}
+	if got := readState(t, dir).Branches["ro-d"].Parent; got != "ro-c" {
+		t.Errorf("ro-d parent after failed reorder = %q, want ro-c", got)
+	}
+	if got := strings.TrimSpace(gitOutput(t, dir, "branch", "--show-current")); got != "main" {
+		t.Errorf("current branch after failed reorder = %q, want main", got)
+	}
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "reorder", "ro-c", "--before", "ro-b", "--json"); err != nil {
+			t.Fatalf("frond reorder: %v", err)
+		}
+	})
+	var result reorderResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing %q: %v", out, err)
+	}
+	if !slices.Equal(result.Order, []string{"ro-c", "ro-b"}) || !slices.Equal(result.Rebased, []string{"ro-c", "ro-b", "ro-d"}) {
+		t.Errorf("reorder result = %+v, want order [ro-c ro-b] and three rebases", result)
+	}
+
+	st := readState(t, dir)
+	for name, want := range map[string]string{"ro-a": "main", "ro-c": "ro-a", "ro-b": "ro-c", "ro-d": "ro-b"} {
+		if got := st.Branches[name].Parent; got != want {
+			t.Errorf("%s parent = %q, want %q", name, got, want)
+		}
+	}
+	for _, pair := range [][3]string{{"ro-a", "ro-c", "ro-c work"}, {"ro-c", "ro-b", "ro-b work"}, {"ro-b", "ro-d", "ro-d work"}} {
+		if log := strings.TrimSpace(gitOutput(t, dir, "log", "--format=%s", pair[0]+".."+pair[1])); log != pair[2] {
+			t.Errorf("%s..%s = %q, want %q", pair[0], pair[1], log, pair[2])
+		}
+	}
+	data, err := os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "pr edit 3 --base ro-a") || !strings.Contains(string(data), "pr edit 2 --base ro-c") {
+		t.Errorf("PRs not retargeted; gh calls:\n%s", data)
+	}
+
+	// A fork in the run is refused.
+	resetCobraFlags()
+	if err := runTier(t, "new", "ro-side", "--on", "ro-c"); err != nil {
+		t.Fatalf("frond new ro-side: %v", err)
+	}
+	resetCobraFlags()
+	if err := runTier(t, "reorder", "ro-b", "--before", "ro-a"); err == nil || !strings.Contains(err.Error(), "linear") {
+		t.Errorf("reorder across a fork error = %v, want linear stack error", err)
+	}
+}
+
+func TestReorderRejectsAfterCycle(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "rc-a"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "a work")
+	if err := runTier(t, "new", "rc-b", "--after", "rc-a"); err != nil {
+		t.Fatalf("frond new --after: %v", err)
+	}
+
+	// rc-b lands after rc-a, so it cannot move below it.
+	resetCobraFlags()
+	err := runTier(t, "reorder", "rc-b", "--before", "rc-a")
+	if err == nil || !strings.Contains(err.Error(), "parent/after cycle") {
+		t.Fatalf("reorder error = %v, want parent/after cycle", err)
+	}
+	if got := readState(t, dir).Branches["rc-b"].Parent; got != "rc-a" {
+		t.Errorf("rc-b parent after refused reorder = %q, want rc-a", got)
+	}
+}
+
+func TestUntrackAllMerged(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	// main <- gone-1 <- gone-2 <- keeper; gone-1 and gone-2 merged.
+	for _, name := range []string{"gone-1", "gone-2", "keeper"} {
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+	}
+	setPR(t, dir, "gone-1", 1)
+	setPR(t, dir, "gone-2", 2)
+	setPR(t, dir, "keeper", 3)
+	// PR 3 is missing from the list and is viewed on its own.
+	t.Setenv("FAKEGH_PR_LIST", `[
+		{"number": 1, "state": "MERGED", "author": {"login": "octocat"}, "baseRefName": "main", "headRefName": "gone-1"},
+		{"number": 2, "state": "MERGED", "author": {"login": "octocat"}, "baseRefName": "gone-1", "headRefName": "gone-2"}
+	]`)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	if err := runTier(t, "untrack", "keeper", "--all-merged"); err == nil {
+		t.Error("--all-merged with a branch should fail")
+	}
+
+	resetCobraFlags()
+	var res untrackAllResult
+	out := captureStdout(t, func() {
+		if err := runTier(t, "untrack", "--all-merged", "--json"); err != nil {
+			t.Fatalf("frond untrack --all-merged: %v", err)
+		}
+	})
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if strings.Join(res.Untracked, " ") != "gone-1 gone-2" || res.Reparented["keeper"] != "main" {
+		t.Errorf("result = %+v, want gone-1 and gone-2 untracked, keeper reparented to main", res)
+	}
+	s := readState(t, dir)
+	if len(s.Branches) != 1 || s.Branches["keeper"].Parent != "main" {
+		t.Errorf("branches = %+v", s.Branches)
+	}
+	if m := s.Merged["gone-2"]; m.Parent != "gone-1" || m.PR == nil || *m.PR != 2 || m.Author != "octocat" {
+		t.Errorf("merged[gone-2] = %+v, want parent gone-1, PR #2 by octocat", m)
+	}
+	if _, ok := s.Merged["gone-1"]; !ok || len(s.Merged) != 2 {
+		t.Errorf("merged = %+v, want gone-1 and gone-2", s.Merged)
+	}
+	data, err := os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := string(data); strings.Count(calls, "pr list") != 1 || strings.Count(calls, "pr view") != 1 || !strings.Contains(calls, "pr view 3") {
+		t.Errorf("want one pr list and a pr view of PR 3 only; gh calls:\n%s", calls)
+	}
+}
+
+func TestStatusLegendJSON(t *testing.T) {
+	setupTestEnv(t)
+
+	// No state is needed to describe the markers.
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--legend-json"); err != nil {
+			t.Fatalf("frond status --legend-json: %v", err)
+		}
+	})
+	var legend map[string]string
+	if err := json.Unmarshal([]byte(out), &legend); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if legend["ready"] != "[ready]" || legend["not_pushed"] != "(not pushed)" || legend["highlight"] != "👈" {
+		t.Errorf("legend = %v", legend)
+	}
+}
+
+func TestSetBase(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	if err := runTier(t, "new", "hotfix"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "fix")
+
+	if err := runTier(t, "set-base", "hotfix"); err == nil {
+		t.Fatal("expected error without <ref> or --clear")
+	}
+	resetCobraFlags()
+	if err := runTier(t, "set-base", "hotfix", "release"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("set-base to a missing ref error = %v, want does not exist", err)
+	}
+	// A branch that only exists on origin is a valid base.
+	gitRun(t, dir, "push", "origin", "main:release")
+	resetCobraFlags()
+	if err := runTier(t, "set-base", "hotfix", "release"); err != nil {
+		t.Fatalf("frond set-base: %v", err)
+	}
+	b := readState(t, dir).Branches["hotfix"]
+	if b.Parent != "main" || b.BaseOverride != "release" {
+		t.Errorf("branch = %+v, want parent main, base override release", b)
+	}
+
+	// Push opens the PR against the override, not the parent.
+	if err := runTier(t, "push"); err != nil {
+		t.Fatalf("frond push: %v", err)
+	}
+	data, err := os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "--base release") {
+		t.Errorf("PR not created against release; gh calls:\n%s", data)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status"); err != nil {
+			t.Fatalf("frond status: %v", err)
+		}
+	})
+	if !strings.Contains(out, "(base: release)") {
+		t.Errorf("status missing base note:\n%s", out)
+	}
+
+	resetCobraFlags()
+	if err := runTier(t, "set-base", "hotfix", "--clear"); err != nil {
+		t.Fatalf("frond set-base --clear: %v", err)
+	}
+	if got := readState(t, dir).Branches["hotfix"].BaseOverride; got != "" {
+		t.Errorf("base override = %q after --clear, want empty", got)
+	}
+}
+
+func TestStatusSelect(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "sel-a"); err != nil {
+		t.Fatalf("frond new sel-a: %v", err)
+	}
+	if err := runTier(t, "new", "sel-b"); err != nil {
+		t.Fatalf("frond new sel-b: %v", err)
+	}
+	if err := runTier(t, "new", "sel-c", "--on", "main", "--after", "sel-b"); err != nil {
+		t.Fatalf("frond new sel-c: %v", err)
+	}
+	setPR(t, dir, "sel-a", 7)
+
+	selectNames := func(expr string) []string {
+		t.Helper()
+		resetCobraFlags()
+		out := captureStdout(t, func() {
+			if err := runTier(t, "status", "--json", "--select", expr); err != nil {
+				t.Fatalf("frond status --select %q: %v", expr, err)
+			}
+		})
+		var result struct {
+			Branches []struct {
+				Name string `json:"name"`
+			} `json:"branches"`
+		}
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("parsing output: %v\n%s", err, out)
+		}
+		var names []string
+		for _, b := range result.Branches {
+			names = append(names, b.Name)
+		}
+		return names
+	}
+
+	for expr, want := range map[string][]string{
+		"ready==false":       {"sel-c"},
+		"pr==null":           {"sel-b", "sel-c"},
+		"pr!=null":           {"sel-a"},
+		"depth>1":            {"sel-b"},
+		"depth==1, after==0": {"sel-a"},
+		"parent==sel-a":      {"sel-b"},
+		"name=='sel-c'":      {"sel-c"},
+		"pr>=7,ready==true":  {"sel-a"},
+	} {
+		if got := selectNames(expr); !slices.Equal(got, want) {
+			t.Errorf("--select %q = %v, want %v", expr, got, want)
+		}
+	}
+
+	for _, bad := range []string{"ready", "nope==1", "ready>true", "depth==x", "name=x"} {
+		resetCobraFlags()
+		if err := runTier(t, "status", "--select", bad); err == nil {
+			t.Errorf("--select %q: expected error", bad)
+		}
+	}
+}
+
+func TestSyncRetargetOnly(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	if err := runTier(t, "new", "rt-base"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	if err := runTier(t, "new", "rt-child"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	setPR(t, dir, "rt-base", 1)
+	setPR(t, dir, "rt-child", 2)
+	t.Setenv("FAKEGH_MERGED_PRS", "1")
+
+	// Move trunk so a rebase would rewrite rt-child.
+	gitRun(t, dir, "checkout", "main")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "trunk moved")
+	gitRun(t, dir, "checkout", "rt-child")
+	before := gitOutput(t, dir, "rev-parse", "rt-child")
+
+	var res syncResult
+	out := captureStdout(t, func() {
+		if err := runTier(t, "sync", "--retarget-only", "--json"); err != nil {
+			t.Fatalf("frond sync --retarget-only: %v", err)
+		}
+	})
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if strings.Join(res.Merged, " ") != "rt-base" {
+		t.Errorf("merged = %v, want [rt-base]", res.Merged)
+	}
+	if res.Retargeted["rt-child"] != "main" {
+		t.Errorf("retargeted = %v, want rt-child -> main", res.Retargeted)
+	}
+	if len(res.Rebased) != 0 {
+		t.Errorf("rebased = %v, want none", res.Rebased)
+	}
+	if after := gitOutput(t, dir, "rev-parse", "rt-child"); after != before {
+		t.Error("--retarget-only rewrote rt-child")
+	}
+	if got := readState(t, dir).Branches["rt-child"].Parent; got != "main" {
+		t.Errorf("rt-child parent = %q, want main", got)
+	}
+}
+
+func TestStatusStale(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "old-work"); err != nil {
+		t.Fatalf("frond new old-work: %v", err)
+	}
+	t.Setenv("GIT_COMMITTER_DATE", "2020-01-01T00:00:00Z")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "long ago")
+	t.Setenv("GIT_COMMITTER_DATE", "")
+	if err := runTier(t, "new", "fresh-work", "--on", "main"); err != nil {
+		t.Fatalf("frond new fresh-work: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "today")
+
+	if err := runTier(t, "status", "--stale", "soon"); err == nil || !strings.Contains(err.Error(), "--stale") {
+		t.Fatalf("frond status --stale soon error = %v, want invalid --stale", err)
+	}
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--stale", "30d"); err != nil {
+			t.Fatalf("frond status --stale: %v", err)
+		}
+	})
+	for _, line := range strings.Split(out, "\n") {
+		stale := strings.Contains(line, "(stale: ")
+		if strings.Contains(line, "old-work") && !stale {
+			t.Errorf("old-work not marked stale: %q", line)
+		}
+		if strings.Contains(line, "fresh-work") && stale {
+			t.Errorf("fresh-work marked stale: %q", line)
+		}
+	}
+
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--stale", "2w", "--json"); err != nil {
+			t.Fatalf("frond status --stale --json: %v", err)
+		}
+	})
+	var result struct {
+		Branches []struct {
+			Name  string `json:"name"`
+			Stale *bool  `json:"stale"`
+		} `json:"branches"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	for _, b := range result.Branches {
+		if b.Stale == nil || *b.Stale != (b.Name == "old-work") {
+			t.Errorf("%s stale = %v", b.Name, b.Stale)
+		}
+	}
+}
+
+func TestRenameTrunk(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "rn-a"); err != nil {
+		t.Fatalf("frond new rn-a: %v", err)
+	}
+	if err := runTier(t, "new", "rn-b"); err != nil {
+		t.Fatalf("frond new rn-b: %v", err)
+	}
+
+	if err := runTier(t, "rename-trunk", "trunk"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("rename to missing branch error = %v, want does not exist", err)
+	}
+
+	gitRun(t, dir, "branch", "trunk", "main")
+	for range 2 { // idempotent
+		resetCobraFlags()
+		if err := runTier(t, "rename-trunk", "trunk"); err != nil {
+			t.Fatalf("frond rename-trunk: %v", err)
+		}
+	}
+
+	s := readState(t, dir)
+	if s.Trunk != "trunk" {
+		t.Errorf("trunk = %q, want trunk", s.Trunk)
+	}
+	if got := s.Branches["rn-a"].Parent; got != "trunk" {
+		t.Errorf("rn-a parent = %q, want trunk", got)
+	}
+	if got := s.Branches["rn-b"].Parent; got != "rn-a" {
+		t.Errorf("rn-b parent = %q, want rn-a", got)
+	}
+}
+
+func TestPushReadyDepsOnly(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	if err := runTier(t, "new", "dep-first"); err != nil {
+		t.Fatalf("frond new dep-first: %v", err)
+	}
+	gitRun(t, dir, "checkout", "main")
+	resetCobraFlags()
+	if err := runTier(t, "new", "dep-second", "--after", "dep-first"); err != nil {
+		t.Fatalf("frond new dep-second: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "work")
+
+	resetCobraFlags()
+	if err := runTier(t, "push", "--ready-deps-only"); err == nil || !strings.Contains(err.Error(), "unmet dependencies: dep-first") {
+		t.Fatalf("push --ready-deps-only error = %v, want unmet dep-first", err)
+	}
+	if data, _ := os.ReadFile(recordFile); strings.Contains(string(data), "pr create") {
+		t.Errorf("PR created despite --ready-deps-only; gh calls:\n%s", data)
+	}
+
+	// Without the flag the push goes ahead with a warning.
+	resetCobraFlags()
+	stderr := captureStderr(t, func() {
+		if err := runTier(t, "push"); err != nil {
+			t.Fatalf("frond push: %v", err)
+		}
+	})
+	if !strings.Contains(stderr, "warning: unmet dependencies: dep-first") {
+		t.Errorf("stderr = %q, want unmet dependency warning", stderr)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	setupPRCounter(t, dir)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	steps := [][]string{
+		{"new", "sub-a"},
+		{"new", "sub-b"},
+		{"new", "sub-c", "--on", "main"},
+	}
+	for _, args := range steps {
+		resetCobraFlags()
+		if err := runTier(t, args...); err != nil {
+			t.Fatalf("frond %v: %v", args, err)
+		}
+		gitRun(t, dir, "commit", "--allow-empty", "-m", "work on "+args[1])
+	}
+
+	// Only the sub-a subtree, parents first.
+	reflog := gitOutput(t, dir, "reflog")
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "submit", "--from", "sub-a", "--draft", "--json"); err != nil {
+			t.Fatalf("frond submit --from: %v", err)
+		}
+	})
+	var res submitResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	want := []submitBranch{{Branch: "sub-a", PR: 42, Created: true}, {Branch: "sub-b", PR: 43, Created: true}}
+	if !slices.Equal(res.Branches, want) {
+		t.Errorf("submitted = %+v, want %+v", res.Branches, want)
+	}
+	data, _ := os.ReadFile(recordFile)
+	if !strings.Contains(string(data), "--base sub-a --head sub-b") || !strings.Contains(string(data), "--draft") {
+		t.Errorf("sub-b PR not created as a draft on sub-a; gh calls:\n%s", data)
+	}
+	if got := gitOutput(t, dir, "reflog"); got != reflog {
+		t.Errorf("submit moved HEAD; reflog:\n%s", got)
+	}
+
+	// Everything: existing PRs are updated, sub-c gets its own.
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "submit"); err != nil {
+			t.Fatalf("frond submit: %v", err)
+		}
+	})
+	for _, line := range []string{"Pushed sub-a. PR #42 [updated]", "Pushed sub-b. PR #43 [updated]", "Pushed sub-c. PR #44 [created]"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("output missing %q:\n%s", line, out)
+		}
+	}
+	if pr := readState(t, dir).Branches["sub-c"].PR; pr == nil || *pr != 44 {
+		t.Errorf("sub-c PR = %v, want 44", pr)
+	}
+
+	// --skip-unchanged only pushes what moved; --label reaches its PR.
+	gitRun(t, dir, "checkout", "sub-b")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "more work on sub-b")
+	os.Remove(recordFile)
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "submit", "--skip-unchanged", "--label", "needs-review", "--json"); err != nil {
+			t.Fatalf("frond submit --skip-unchanged: %v", err)
+		}
+	})
+	res = submitResult{}
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	slices.SortFunc(res.Branches, func(a, b submitBranch) int { return strings.Compare(a.Branch, b.Branch) })
+	want = []submitBranch{
+		{Branch: "sub-a", PR: 42, Skipped: true},
+		{Branch: "sub-b", PR: 43},
+		{Branch: "sub-c", PR: 44, Skipped: true},
+	}
+	if !slices.Equal(res.Branches, want) {
+		t.Errorf("submitted = %+v, want %+v", res.Branches, want)
+	}
+	data, _ = os.ReadFile(recordFile)
+	if calls := string(data); !strings.Contains(calls, "pr edit 43 --add-label needs-review") || strings.Contains(calls, "pr edit 42") {
+		t.Errorf("want only sub-b's PR labeled; gh calls:\n%s", calls)
+	}
+
+	// A failure names what already went through.
+	resetCobraFlags()
+	gitRun(t, dir, "checkout", "main")
+	gitRun(t, dir, "branch", "-D", "sub-b")
+	gitRun(t, dir, "update-ref", "-d", "refs/remotes/origin/sub-b")
+	err := runTier(t, "submit", "--from", "sub-a")
+	if err == nil || !strings.Contains(err.Error(), "submitting sub-b") || !strings.Contains(err.Error(), "already submitted: sub-a") {
+		t.Fatalf("submit error = %v, want failure on sub-b after sub-a", err)
+	}
+}
+
+func TestMoveDeps(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	steps := [][]string{
+		{"new", "md-c"},
+		{"new", "md-a", "--on", "main", "--after", "md-c"},
+		{"new", "md-b", "--on", "main"},
+		{"new", "md-d", "--on", "main", "--after", "md-b"},
+	}
+	for _, args := range steps {
+		resetCobraFlags()
+		if err := runTier(t, args...); err != nil {
+			t.Fatalf("frond %v: %v", args, err)
+		}
+	}
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "move-deps", "--from", "md-a", "--to", "md-b", "md-c", "--json"); err != nil {
+			t.Fatalf("frond move-deps: %v", err)
+		}
+	})
+	var res moveDepsResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if len(res.FromAfter) != 0 || !slices.Equal(res.ToAfter, []string{"md-c"}) {
+		t.Errorf("result = %+v, want md-c moved from md-a to md-b", res)
+	}
+	s := readState(t, dir)
+	if len(s.Branches["md-a"].After) != 0 || !slices.Equal(s.Branches["md-b"].After, []string{"md-c"}) {
+		t.Errorf("after lists: md-a=%v md-b=%v", s.Branches["md-a"].After, s.Branches["md-b"].After)
+	}
+
+	// md-b already waits on md-c, so md-c waiting on md-b is a cycle.
+	resetCobraFlags()
+	err := runTier(t, "move-deps", "--from", "md-d", "--to", "md-c", "md-b")
+	if err == nil || !strings.Contains(err.Error(), "after cycle") {
+		t.Fatalf("move-deps error = %v, want after cycle", err)
+	}
+	if s := readState(t, dir); !slices.Equal(s.Branches["md-d"].After, []string{"md-b"}) {
+		t.Errorf("md-d after = %v, want unchanged after a refused move", s.Branches["md-d"].After)
+	}
+
+	resetCobraFlags()
+	if err := runTier(t, "move-deps", "--from", "md-a", "--to", "md-d", "md-c"); err == nil || !strings.Contains(err.Error(), "does not depend on") {
+		t.Errorf("move-deps of a missing dep = %v, want does not depend on", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	for _, name := range []string{"del-a", "del-b"} {
+		resetCobraFlags()
+		if err := runTier(t, "new", name); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+		gitRun(t, dir, "commit", "--allow-empty", "-m", "work on "+name)
+	}
+
+	// del-a's commits are in the checked-out del-b, so git deletes it.
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "delete", "del-a", "--json"); err != nil {
+			t.Fatalf("frond delete del-a: %v", err)
+		}
+	})
+	var res deleteResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if !res.Deleted || res.Name != "del-a" || !slices.Equal(res.Reparented, []string{"del-b"}) {
+		t.Errorf("result = %+v, want del-a deleted and del-b reparented", res)
+	}
+	if !strings.Contains(out, `"deleted": true`) || !strings.Contains(out, `"unblocked": []`) {
+		t.Errorf("JSON should carry untrack fields plus deleted:\n%s", out)
+	}
+	if s := readState(t, dir); s.Branches["del-b"].Parent != "main" {
+		t.Errorf("del-b parent = %q, want main", s.Branches["del-b"].Parent)
+	}
+
+	// The checked-out branch needs --force.
+	resetCobraFlags()
+	if err := runTier(t, "delete", "del-b"); err == nil || !strings.Contains(err.Error(), "checked out") {
+		t.Fatalf("delete of current branch error = %v, want checked out", err)
+	}
+	resetCobraFlags()
+	if err := runTier(t, "delete", "del-b", "--force"); err != nil {
+		t.Fatalf("frond delete del-b --force: %v", err)
+	}
+	if cur := strings.TrimSpace(gitOutput(t, dir, "branch", "--show-current")); cur != "main" {
+		t.Errorf("current branch = %q, want main", cur)
+	}
+	if out := gitOutput(t, dir, "branch", "--list", "del-b"); strings.TrimSpace(out) != "" {
+		t.Errorf("del-b still exists in git")
+	}
+
+	// An open PR keeps the git branch but still untracks it.
+	resetCobraFlags()
+	if err := runTier(t, "new", "del-c"); err != nil {
+		t.Fatalf("frond new del-c: %v", err)
+	}
+	setPR(t, dir, "del-c", 7)
+	gitRun(t, dir, "checkout", "main")
+	resetCobraFlags()
+	stderr := captureStderr(t, func() {
+		if err := runTier(t, "delete", "del-c"); err != nil {
+			t.Fatalf("frond delete del-c: %v", err)
+		}
+	})
+	if !strings.Contains(stderr, "PR #7 for del-c is still open") {
+		t.Errorf("stderr = %q, want open PR warning", stderr)
+	}
+	if out := gitOutput(t, dir, "branch", "--list", "del-c"); strings.TrimSpace(out) == "" {
+		t.Error("del-c was deleted despite its open PR")
+	}
+	if _, tracked := readState(t, dir).Branches["del-c"]; tracked {
+		t.Error("del-c is still tracked")
+	}
+}
+
+func TestPushLabelFromPath(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	if err := runTier(t, "config", "label_rules", "pay/=area/pay,auth/=area/auth"); err != nil {
+		t.Fatalf("frond config label_rules: %v", err)
+	}
+	resetCobraFlags()
+	if err := runTier(t, "config", "label_rules", "pay/"); err == nil {
+		t.Error("expected error for a rule without a label")
+	}
+
+	resetCobraFlags()
+	if err := runTier(t, "new", "pay/api"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "work")
+
+	resetCobraFlags()
+	out := captureStdout(t, func() {
+		if err := runTier(t, "push", "--label-from-path", "--label", "urgent", "--label", "area/pay", "--json"); err != nil {
+			t.Fatalf("frond push --label-from-path: %v", err)
+		}
+	})
+	var result pushResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if !slices.Equal(result.Labels, []string{"urgent", "area/pay"}) {
+		t.Errorf("labels = %v, want [urgent area/pay]", result.Labels)
+	}
+	data, err := os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "--label urgent --label area/pay") {
+		t.Errorf("labels missing from pr create; gh calls:\n%s", data)
+	}
+
+	// An existing PR gets the labels added.
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "more")
+	resetCobraFlags()
+	if err := runTier(t, "push", "--label-from-path"); err != nil {
+		t.Fatalf("frond push: %v", err)
+	}
+	data, _ = os.ReadFile(recordFile)
+	if !strings.Contains(string(data), "--add-label area/pay") {
+		t.Errorf("labels not added to the existing PR; gh calls:\n%s", data)
+	}
+}
+
+func TestPushFork(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+
+	if err := runTier(t, "new", "forked"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "work")
+
+	if err := runTier(t, "push", "--head-repo", "alice", "--repo", "acme/widgets"); err != nil {
+		t.Fatalf("frond push --head-repo: %v", err)
+	}
+	data, err := os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "--head alice:forked") || !strings.Contains(string(data), "-R acme/widgets") {
+		t.Errorf("fork args missing; gh calls:\n%s", data)
+	}
+	st := readState(t, dir)
+	if pr := st.Branches["forked"].PR; pr == nil {
+		t.Error("PR number not recorded")
+	}
+	if got := st.Config[state.ConfigRepo]; got != "acme/widgets" {
+		t.Errorf("repo config = %q, want acme/widgets", got)
+	}
+
+	// Later calls keep targeting the upstream repo without --repo.
+	os.Remove(recordFile)
+	resetCobraFlags()
+	if err := runTier(t, "push"); err != nil {
+		t.Fatalf("second frond push: %v", err)
+	}
+	data, _ = os.ReadFile(recordFile)
+	if !strings.Contains(string(data), "pr view 42 --json") || !strings.Contains(string(data), "-R acme/widgets") {
+		t.Errorf("pr view does not target the upstream repo; gh calls:\n%s", data)
+	}
+}
+
+func TestPushAdoptsExistingPR(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+	t.Setenv("FAKEGH_PR_EXISTS", "17")
+
+	if err := runTier(t, "new", "manual"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "work")
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "push", "--json"); err != nil {
+			t.Fatalf("frond push: %v", err)
+		}
+	})
+	var result pushResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if result.PR != 17 || result.Created {
+		t.Errorf("push result = %+v, want PR 17 adopted, not created", result)
+	}
+	if pr := readState(t, dir).Branches["manual"].PR; pr == nil || *pr != 17 {
+		t.Errorf("state PR = %v, want 17", pr)
+	}
+}
+
+func TestUpdateCheck(t *testing.T) {
+	dir := setupTestEnv(t)
+	origVersion, origWait := version, updateCheckWait
+	version, updateCheckWait = "1.0.0", 10*time.Second
+	t.Cleanup(func() { version, updateCheckWait = origVersion, origWait })
+	t.Setenv("FAKEGH_LATEST_RELEASE", "v1.1.0")
+
+	if err := runTier(t, "new", "uc"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+
+	// Off by default.
+	errOut := captureStderr(t, func() {
+		if err := runTier(t, "status"); err != nil {
+			t.Fatalf("frond status: %v", err)
+		}
+	})
+	if strings.Contains(errOut, "notice:") {
+		t.Errorf("update notice without update_check:\n%s", errOut)
+	}
+
+	if err := runTier(t, "config", "update_check", "true"); err != nil {
+		t.Fatalf("frond config update_check: %v", err)
+	}
+	errOut = captureStderr(t, func() {
+		if err := runTier(t, "status"); err != nil {
+			t.Fatalf("frond status: %v", err)
+		}
+	})
+	if !strings.Contains(errOut, "notice: frond v1.1.0 is available (you have 1.0.0)") {
+		t.Errorf("missing update notice:\n%s", errOut)
+	}
+
+	// The result is cached, so gh is not needed again within a day.
+	t.Setenv("FAKEGH_FAIL_API", "1")
+	errOut = captureStderr(t, func() {
+		if err := runTier(t, "status"); err != nil {
+			t.Fatalf("frond status: %v", err)
+		}
+	})
+	if !strings.Contains(errOut, "notice:") {
+		t.Errorf("cached update notice missing:\n%s", errOut)
+	}
+
+	// A lookup cut short by a fast command still counts as today's check.
+	cachePath := filepath.Join(dir, ".git", updateCacheFile)
+	stale, _ := json.Marshal(updateCache{CheckedAt: time.Now().Add(-48 * time.Hour), Latest: "v1.1.0"})
+	if err := os.WriteFile(cachePath, stale, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAKEGH_FAIL_API", "")
+	t.Setenv("FAKEGH_RELEASE_DELAY", "500ms")
+	updateCheckWait = time.Millisecond
+	errOut = captureStderr(t, func() {
+		if err := runTier(t, "status"); err != nil {
+			t.Fatalf("frond status: %v", err)
+		}
+	})
+	var cache updateCache
+	if data, err := os.ReadFile(cachePath); err != nil || json.Unmarshal(data, &cache) != nil || time.Since(cache.CheckedAt) > time.Hour || cache.Latest != "v1.1.0" {
+		t.Errorf("cache = %+v (%v), want a fresh claim keeping v1.1.0", cache, err)
+	}
+	// Let the lookup finish, rewriting the cache, before the repo is removed.
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		var done updateCache
+		if data, err := os.ReadFile(cachePath); err == nil && json.Unmarshal(data, &done) == nil && !done.CheckedAt.Equal(cache.CheckedAt) {
+			break
+		}
+	}
+	updateCheckWait = 10 * time.Second
+
+	// Never under --json.
+	errOut = captureStderr(t, func() {
+		captureStdout(t, func() {
+			if err := runTier(t, "status", "--json"); err != nil {
+				t.Fatalf("frond status --json: %v", err)
+			}
+		})
+	})
+	if strings.Contains(errOut, "notice:") {
+		t.Errorf("update notice under --json:\n%s", errOut)
+	}
+
+	if newerVersion("v1.0.0", "1.0.0") || newerVersion("garbage", "1.0.0") || !newerVersion("v2.0.0-rc.1", "v1.9.9") {
+		t.Error("newerVersion comparisons wrong")
+	}
+}
+
+func TestStatusCountCommits(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "cc-a"); err != nil {
+		t.Fatalf("frond new cc-a: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "a1")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "a2")
+	if err := runTier(t, "new", "cc-b"); err != nil {
+		t.Fatalf("frond new cc-b: %v", err)
+	}
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "b1")
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--count-commits", "--json"); err != nil {
+			t.Fatalf("frond status --count-commits: %v", err)
+		}
+	})
+	var result struct {
+		Branches []struct {
+			Name           string `json:"name"`
+			SubtreeCommits *int   `json:"subtree_commits"`
+		} `json:"branches"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	want := map[string]int{"cc-a": 3, "cc-b": 1}
+	for _, b := range result.Branches {
+		if b.SubtreeCommits == nil || *b.SubtreeCommits != want[b.Name] {
+			t.Errorf("%s subtree_commits = %v, want %d", b.Name, b.SubtreeCommits, want[b.Name])
+		}
+	}
+
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--count-commits"); err != nil {
+			t.Fatalf("frond status --count-commits: %v", err)
+		}
+	})
+	if !strings.Contains(out, "(3 commits)") || !strings.Contains(out, "(1 commit)") {
+		t.Errorf("missing commit annotations:\n%s", out)
+	}
+}
+
+func TestSyncFetchScopeStack(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	if err := runTier(t, "new", "fs-pushed"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "push", "-u", "origin", "fs-pushed")
+	if err := runTier(t, "new", "fs-local", "--on", "main"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	// An untracked remote branch is not fetched.
+	gitRun(t, dir, "push", "origin", "main:other")
+	gitRun(t, dir, "update-ref", "-d", "refs/remotes/origin/other")
+
+	if err := runTier(t, "sync", "--fetch-scope", "some"); err == nil || !strings.Contains(err.Error(), "--fetch-scope") {
+		t.Fatalf("frond sync --fetch-scope some error = %v, want invalid scope", err)
+	}
+
+	resetCobraFlags()
+	if err := runTier(t, "sync", "--fetch-scope", "stack"); err != nil {
+		t.Fatalf("frond sync --fetch-scope stack: %v", err)
+	}
+	if out := gitOutput(t, dir, "branch", "-r", "--list", "origin/other"); out != "" {
+		t.Errorf("origin/other fetched with --fetch-scope stack: %q", out)
+	}
+}
+
+func TestStatusPRChecks(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	for i, name := range []string{"ck-a", "ck-b", "ck-c", "ck-d"} {
+		if err := runTier(t, "new", name, "--on", "main"); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+		setPR(t, dir, name, i+1)
+	}
+	if err := runTier(t, "new", "ck-unpushed", "--on", "main"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	t.Setenv("FAKEGH_CHECKS", "1=SUCCESS,2=PENDING,3=FAILURE")
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "status", "--pr-checks"); err != nil {
+			t.Fatalf("frond status --pr-checks: %v", err)
+		}
+	})
+	if !strings.Contains(out, "4 PRs: 1 green, 1 pending, 1 failing, 1 without checks") {
+		t.Errorf("missing rollup line:\n%s", out)
+	}
+
+	resetCobraFlags()
+	out = captureStdout(t, func() {
+		if err := runTier(t, "status", "--pr-checks", "--json"); err != nil {
+			t.Fatalf("frond status --pr-checks --json: %v", err)
+		}
+	})
+	var result struct {
+		Checks   checksSummary `json:"checks"`
+		Branches []struct {
+			Name     string `json:"name"`
+			PRChecks string `json:"pr_checks"`
+		} `json:"branches"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	if result.Checks != (checksSummary{Total: 4, Green: 1, Pending: 1, Failing: 1, None: 1}) {
+		t.Errorf("checks = %+v", result.Checks)
+	}
+	for _, b := range result.Branches {
+		if b.Name == "ck-c" && b.PRChecks != "FAILING" {
+			t.Errorf("ck-c pr_checks = %q, want FAILING", b.PRChecks)
+		}
+	}
+}
+
+func TestLand(t *testing.T) {
+	dir := setupTestEnv(t)
+	recordFile := filepath.Join(dir, "gh_calls.log")
+	t.Setenv("FAKEGH_RECORD", recordFile)
+	origInterval := landPollInterval
+	landPollInterval = time.Millisecond
+	t.Cleanup(func() { landPollInterval = origInterval })
+
+	if err := runTier(t, "new", "land-a"); err != nil {
+		t.Fatalf("frond new land-a: %v", err)
+	}
+	if err := runTier(t, "new", "land-b"); err != nil {
+		t.Fatalf("frond new land-b: %v", err)
+	}
+	setPR(t, dir, "land-a", 5)
+	setPR(t, dir, "land-b", 6)
+
+	// Without --wait, pending checks fail fast.
+	t.Setenv("FAKEGH_VIEW_COUNTER", filepath.Join(dir, "views"))
+	t.Setenv("FAKEGH_PENDING_VIEWS", "1")
+	resetCobraFlags()
+	if err := runTier(t, "land", "land-a"); err == nil || !strings.Contains(err.Error(), "checks pending") {
+		t.Fatalf("frond land error = %v, want checks pending", err)
+	}
+
+	// Failing checks are final, even with --wait.
+	t.Setenv("FAKEGH_PENDING_VIEWS", "")
+	t.Setenv("FAKEGH_CHECKS", "5=FAILURE")
+	resetCobraFlags()
+	if err := runTier(t, "land", "land-a", "--wait"); err == nil || !strings.Contains(err.Error(), "failing checks") {
+		t.Fatalf("frond land --wait error = %v, want failing checks", err)
+	}
+
+	// --timeout bounds the wait.
+	t.Setenv("FAKEGH_CHECKS", "5=PENDING")
+	resetCobraFlags()
+	if err := runTier(t, "land", "land-a", "--wait", "--timeout", "20ms"); err == nil || !strings.Contains(err.Error(), "gave up waiting") {
+		t.Fatalf("frond land --timeout error = %v, want gave up waiting", err)
+	}
+	if data, _ := os.ReadFile(recordFile); strings.Contains(string(data), "pr merge") {
+		t.Fatalf("a refused land merged something; gh calls:\n%s", data)
+	}
+
+	// Landing land-b lands land-a first. With --wait, each PR is polled
+	// until its checks finish, then merged.
+	t.Setenv("FAKEGH_CHECKS", "")
+	t.Setenv("FAKEGH_VIEW_COUNTER", filepath.Join(dir, "views2"))
+	t.Setenv("FAKEGH_PENDING_VIEWS", "2")
+	resetCobraFlags()
+	var out string
+	errOut := captureStderr(t, func() {
+		out = captureStdout(t, func() {
+			if err := runTier(t, "land", "land-b", "--wait", "--method", "squash"); err != nil {
+				t.Fatalf("frond land --wait: %v", err)
+			}
+		})
+	})
+	if strings.Count(errOut, "waiting on PR #5 (land-a)") != 2 {
+		t.Errorf("want two waiting notices, got:\n%s", errOut)
+	}
+	if !strings.Contains(out, "Landed land-a (PR #5)\nLanded land-b (PR #6)") {
+		t.Errorf("want land-a then land-b landed, got:\n%s", out)
+	}
+	data, err := os.ReadFile(recordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merge5 := strings.Index(string(data), "pr merge 5 --squash")
+	merge6 := strings.Index(string(data), "pr merge 6 --squash")
+	if merge5 < 0 || merge6 < merge5 {
+		t.Errorf("want PR 5 then PR 6 merged; gh calls:\n%s", data)
+	}
+
+	st := readState(t, dir)
+	if len(st.Branches) != 0 {
+		t.Errorf("branches still tracked after land: %v", st.Branches)
+	}
+	for name, pr := range map[string]int{"land-a": 5, "land-b": 6} {
+		m, ok := st.Merged[name]
+		if !ok || m.PR == nil || *m.PR != pr || m.Author != "octocat" {
+			t.Errorf("merged[%s] = %+v, want PR #%d by octocat", name, m, pr)
+		}
+	}
+}
+
+func TestStatusSinceSync(t *testing.T) {
+	dir := setupTestEnv(t)
+	setupRemote(t, dir)
+
+	for _, name := range []string{"ss-a", "ss-b"} {
+		if err := runTier(t, "new", name, "--on", "main"); err != nil {
+			t.Fatalf("frond new %s: %v", name, err)
+		}
+		gitRun(t, dir, "commit", "--allow-empty", "-m", name)
+		resetCobraFlags()
+		if err := runTier(t, "push"); err != nil {
+			t.Fatalf("frond push %s: %v", name, err)
+		}
+	}
+	if err := runTier(t, "sync"); err != nil {
+		t.Fatalf("frond sync: %v", err)
+	}
+
+	changed := func() map[string]bool {
+		t.Helper()
+		resetCobraFlags()
+		out := captureStdout(t, func() {
+			if err := runTier(t, "status", "--since-sync", "--json"); err != nil {
+				t.Fatalf("frond status --since-sync: %v", err)
+			}
+		})
+		var result struct {
+			Branches []struct {
+				Name    string `json:"name"`
+				Changed *bool  `json:"changed_since_sync"`
+			} `json:"branches"`
+		}
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("parsing output: %v\n%s", err, out)
+		}
+		got := make(map[string]bool)
+		for _, b := range result.Branches {
+			if b.Changed == nil {
+				t.Fatalf("%s has no changed_since_sync", b.Name)
+			}
+			got[b.Name] = *b.Changed
+		}
+		return got
+	}
+
+	if got := changed(); got["ss-a"] || got["ss-b"] {
+		t.Errorf("right after sync: %v, want nothing changed", got)
+	}
+
+	gitRun(t, dir, "checkout", "ss-b")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "more")
+	if got := changed(); got["ss-a"] || !got["ss-b"] {
+		t.Errorf("after committing to ss-b: %v, want only ss-b", got)
+	}
+
+	gitRun(t, dir, "checkout", "main")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "trunk moved")
+	if got := changed(); !got["ss-a"] || !got["ss-b"] {
+		t.Errorf("after trunk moved: %v, want both", got)
+	}
+}
+
+func TestDoctorStateVsGit(t *testing.T) {
+	dir := setupTestEnv(t)
+
+	if err := runTier(t, "new", "doc-a"); err != nil {
+		t.Fatalf("frond new: %v", err)
+	}
+	gitRun(t, dir, "checkout", "-b", "loose", "main")
+	gitRun(t, dir, "branch", "-D", "doc-a")
+
+	out := captureStdout(t, func() {
+		if err := runTier(t, "doctor", "--json"); err != nil {
+			t.Fatalf("frond doctor: %v", err)
+		}
+	})
+	var res preflightResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	checks := make(map[string]preflightCheck)
+	for _, c := range res.Checks {
+		checks[c.Name] = c
+	}
+	if c := checks["current_branch"]; c.OK || !strings.Contains(c.Detail, "frond track loose") {
+		t.Errorf("current_branch = %+v, want a suggestion to track loose", c)
+	}
+	if c := checks["tracked_branches"]; c.OK || !strings.Contains(c.Detail, "doc-a") {
+		t.Errorf("tracked_branches = %+v, want doc-a missing", c)
+	}
+	if c := checks["graph"]; !c.OK {
+		t.Errorf("graph = %+v, want ok", c)
+	}
+	if !res.OK {
+		t.Error("state mismatches should not be critical")
+	}
+
+	// A dangling after dependency is a graph problem.
+	s := readState(t, dir)
+	b := s.Branches["doc-a"]
+	b.After = []string{"ghost"}
+	s.Branches["doc-a"] = b
+	writeState(t, dir, s)
+	out = captureStdout(t, func() {
+		if err := runTier(t, "doctor", "--json"); err != nil {
+			t.Fatalf("frond doctor: %v", err)
+		}
+	})
+	res = preflightResult{}
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("parsing output: %v\n%s", err, out)
+	}
+	for _, c := range res.Checks {
+		if c.Name == "graph" && (c.OK || !strings.Contains(c.Detail, "'ghost', which is not tracked")) {
+			t.Errorf("graph = %+v, want ghost reported", c)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var configUnsetFlag bool
+
+var configCmd = &cobra.Command{
+	Use:   "config [<key> [<value>]]",
+	Short: "Show or change repo-level frond settings",
+	Long: "Settings live in frond.json and are shared by every worktree. With no arguments, list all set values; with a key, print its value; with a key and value, set it.\n\nKnown keys:\n" +
+		"  lock_stale     lockfile age after which it is treated as stale (e.g. 30s; default 5m)\n" +
+		"  max_snapshots  number of state snapshots to retain (default 20; 0 keeps all)\n" +
+		"  max_history    number of history log entries to retain for 'frond history' and 'frond undo' (default 200; 0 keeps all)\n" +
+		"  stack_trailers keep Frond-Parent/Frond-After trailers in PR bodies on push (default false)\n" +
+		"  update_check   print a one-line notice when a newer frond release exists, checked at most daily (default false)\n" +
+		"  label_rules    prefix=label pairs for 'push --label-from-path', comma-separated (e.g. pay/=area/pay)\n" +
+		"  repo           upstream owner/name that every gh call targets, for fork workflows (set by 'push --repo')",
+	Example: `  # List settings
+  frond config
+
+  # Shorter stale-lock timeout for CI
+  frond config lock_stale 30s
+
+  # Back to the default
+  frond config lock_stale --unset`,
+	Args:      cobra.MaximumNArgs(2),
+	ValidArgs: state.ConfigKeys(),
+	RunE:      runConfig,
+}
+
+func init() {
+	configCmd.Flags().BoolVar(&configUnsetFlag, "unset", false, "Remove the given key")
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfig(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if len(args) == 0 || (len(args) == 1 && !configUnsetFlag) {
+		s, err := state.Read(ctx)
+		if err != nil {
+			return fmt.Errorf("reading state: %w", err)
+		}
+		if len(args) == 1 {
+			if err := checkConfigKey(args[0]); err != nil {
+				return err
+			}
+			return printConfig(map[string]string{args[0]: s.Config[args[0]]})
+		}
+		return printConfig(s.Config)
+	}
+
+	key := args[0]
+	if configUnsetFlag && len(args) == 2 {
+		return fmt.Errorf("--unset takes a key but no value")
+	}
+	if !configUnsetFlag {
+		if err := state.ValidateConfig(key, args[1]); err != nil {
+			return err
+		}
+	} else if err := checkConfigKey(key); err != nil {
+		return err
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.ReadOrInit(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+	if configUnsetFlag {
+		delete(s.Config, key)
+	} else {
+		if s.Config == nil {
+			s.Config = make(map[string]string)
+		}
+		s.Config[key] = args[1]
+	}
+	if err := state.Write(ctx, s); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	return printConfig(s.Config)
+}
+
+// checkConfigKey returns an error naming the known keys if key is unknown.
+func checkConfigKey(key string) error {
+	if !slices.Contains(state.ConfigKeys(), key) {
+		return fmt.Errorf("unknown config key %q (known: %s)", key, strings.Join(state.ConfigKeys(), ", "))
+	}
+	return nil
+}
+
+// printConfig writes settings as "key = value" lines or a JSON object.
+func printConfig(cfg map[string]string) error {
+	if jsonOut {
+		if cfg == nil {
+			cfg = map[string]string{}
+		}
+		return printJSON(configResult{Config: cfg})
+	}
+	keys := make([]string, 0, len(cfg))
+	for k := range cfg {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		fmt.Printf("%s = %s\n", k, cfg[k])
+	}
+	return nil
+}
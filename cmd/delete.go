@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <branch>",
+	Short: "Untrack a branch and delete it from git",
+	Long: "Untrack a branch like 'frond untrack' (reparenting its children and dropping it from after lists), then delete the local git branch. " +
+		"The checked-out branch, a branch with unmerged commits, and a branch whose PR is still open are kept unless --force is given; " +
+		"with --force, the parent is checked out first if needed.",
+	Example: `  # Drop an abandoned experiment
+  frond delete spike/cache
+
+  # Delete the checked-out branch, moving to its parent
+  frond delete pay/spike --force`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runDelete,
+}
+
+func init() {
+	deleteCmd.Flags().Bool("force", false, "Delete even if checked out, unmerged, or the PR is still open")
+	rootCmd.AddCommand(deleteCmd)
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	force, _ := cmd.Flags().GetBool("force")
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	name, err := resolveTracked(s.Branches, args[0])
+	if err != nil {
+		return err
+	}
+	b := s.Branches[name]
+
+	current, err := git.CurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("getting current branch: %w", err)
+	}
+	if current == name && !force {
+		return fmt.Errorf("'%s' is checked out. Switch branches first or pass --force to check out '%s'", name, b.Parent)
+	}
+
+	// An open PR keeps the branch: deleting it would strand the PR's head.
+	// A PR whose state cannot be read counts as open.
+	deleteGit := true
+	if b.PR != nil && !force {
+		info, err := gh.PRView(ctx, *b.PR)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "warning: could not check PR #%d for %s: %v; keeping the git branch (use --force to delete it)\n", *b.PR, name, err)
+			deleteGit = false
+		case info.State == gh.PRStateOpen:
+			fmt.Fprintf(os.Stderr, "warning: PR #%d for %s is still open; keeping the git branch (use --force to delete it)\n", *b.PR, name)
+			deleteGit = false
+		}
+	}
+
+	// Delete from git before touching state, so a refused deletion (e.g.
+	// unmerged commits) leaves the branch tracked.
+	if deleteGit {
+		if current == name {
+			if err := git.Checkout(ctx, b.Parent); err != nil {
+				return fmt.Errorf("checking out parent: %w", err)
+			}
+		}
+		if err := git.DeleteBranch(ctx, name, force); err != nil {
+			return fmt.Errorf("deleting branch: %w", err)
+		}
+	}
+
+	outcome := removeTracked(s, name)
+	if err := state.Write(ctx, s); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+
+	reparented := slices.Sorted(maps.Keys(outcome.reparented))
+	unblocked := slices.Sorted(maps.Keys(outcome.unblocked))
+	if jsonOut {
+		if reparented == nil {
+			reparented = []string{}
+		}
+		if unblocked == nil {
+			unblocked = []string{}
+		}
+		return printJSON(deleteResult{
+			untrackResult: untrackResult{
+				Name:       name,
+				Reparented: reparented,
+				Unblocked:  unblocked,
+			},
+			Deleted: deleteGit,
+		})
+	}
+	if deleteGit {
+		fmt.Printf("Deleted branch '%s'\n", name)
+	} else {
+		fmt.Printf("Untracked branch '%s' (git branch kept)\n", name)
+	}
+	for _, child := range reparented {
+		fmt.Printf("  Reparented '%s' to '%s'\n", child, outcome.reparented[child])
+	}
+	for _, dep := range unblocked {
+		fmt.Printf("  Removed '%s' from '%s' dependencies\n", name, dep)
+	}
+	return nil
+}
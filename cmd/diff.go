@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [<branch>]",
+	Short: "Show a branch's changes against its recorded parent",
+	Long:  "Run 'git diff <parent>...<branch>' for a tracked branch, showing only the changes the branch itself introduces.",
+	Example: `  # Diff the current branch against its parent
+  frond diff
+
+  # Summary of a specific branch's changes
+  frond diff pay/stripe-client --stat`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runDiff,
+}
+
+func init() {
+	diffCmd.Flags().Bool("stat", false, "Show a diffstat instead of the full diff")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	// Resolve branch: arg (full or unambiguous short name) or current branch.
+	var name string
+	if len(args) > 0 {
+		name, err = resolveTracked(s.Branches, args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		name, err = git.CurrentBranch(ctx)
+		if err != nil {
+			return fmt.Errorf("getting current branch: %w", err)
+		}
+		if _, tracked := s.Branches[name]; !tracked {
+			return fmt.Errorf("current branch '%s' is not tracked", name)
+		}
+	}
+	parent := s.Branches[name].Parent
+	stat, _ := cmd.Flags().GetBool("stat")
+
+	if jsonOut {
+		var out strings.Builder
+		if err := git.Diff(ctx, &out, parent, name, stat); err != nil {
+			return fmt.Errorf("diffing %s: %w", name, err)
+		}
+		return printJSON(diffResult{Branch: name, Parent: parent, Diff: out.String()})
+	}
+	if err := git.Diff(ctx, os.Stdout, parent, name, stat); err != nil {
+		return fmt.Errorf("diffing %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/frond/internal/dag"
+	"github.com/nvandessel/frond/internal/state"
+)
+
+// explainStatus prints why name is (or is not) ready: its direct --after
+// blockers, its parent's PR, and every tracked branch that has to land
+// before it, dependencies first. PR states are fetched for those branches.
+func explainStatus(ctx context.Context, s *state.State, name string) error {
+	branches := stateToDag(s.Branches)
+	readiness := make(map[string]dag.ReadinessInfo, len(branches))
+	for _, ri := range dag.ComputeReadiness(branches) {
+		readiness[ri.Name] = ri
+	}
+
+	// Walk parents and --after deps transitively. Each branch remembers why
+	// it was reached first, for the narrative.
+	reason := make(map[string]string)
+	queue := []string{name}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		b := branches[cur]
+		if _, tracked := branches[b.Parent]; tracked {
+			if _, seen := reason[b.Parent]; !seen && b.Parent != name {
+				reason[b.Parent] = "parent of " + cur
+				queue = append(queue, b.Parent)
+			}
+		}
+		for _, dep := range readiness[cur].BlockedBy {
+			if _, seen := reason[dep]; !seen && dep != name {
+				reason[dep] = "after of " + cur
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	// Order the chain so parents and deps come before what waits on them.
+	sub := make(map[string]dag.BranchInfo, len(reason))
+	for dep := range reason {
+		sub[dep] = branches[dep]
+	}
+	order, err := dag.TopoSort(withParentEdges(sub))
+	if err != nil {
+		return fmt.Errorf("ordering blockers of '%s': %w", name, err)
+	}
+
+	parent := s.Branches[name].Parent
+	prNumbers := map[string]*int{name: s.Branches[name].PR}
+	if pb, tracked := s.Branches[parent]; tracked {
+		prNumbers[parent] = pb.PR
+	}
+	for _, dep := range order {
+		prNumbers[dep] = s.Branches[dep].PR
+	}
+	prStates := fetchPRStates(ctx, prNumbers)
+
+	result := explainResult{
+		Branch:        name,
+		Ready:         readiness[name].Ready,
+		BlockedBy:     append([]string{}, readiness[name].BlockedBy...),
+		Parent:        parent,
+		ParentIsTrunk: s.IsTrunk(parent),
+		Chain:         []explainEntry{},
+	}
+	if !result.ParentIsTrunk {
+		result.ParentPR = s.Branches[parent].PR
+		result.ParentPRState = prStates[parent].State
+	}
+	for _, dep := range order {
+		result.Chain = append(result.Chain, explainEntry{
+			Name:      dep,
+			PR:        s.Branches[dep].PR,
+			PRState:   prStates[dep].State,
+			Reason:    reason[dep],
+			Ready:     readiness[dep].Ready,
+			BlockedBy: append([]string{}, readiness[dep].BlockedBy...),
+		})
+	}
+
+	if jsonOut {
+		return printJSON(result)
+	}
+
+	if result.Ready {
+		fmt.Printf("'%s' is ready: no --after dependencies are pending\n", name)
+	} else {
+		fmt.Printf("'%s' is blocked by: %s\n", name, strings.Join(result.BlockedBy, ", "))
+	}
+	if result.ParentIsTrunk {
+		fmt.Printf("Parent: %s (trunk)\n", parent)
+	} else {
+		fmt.Printf("Parent: %s, %s\n", parent, describePR(result.ParentPR, result.ParentPRState))
+	}
+	if len(result.Chain) == 0 {
+		fmt.Println("Nothing has to land first")
+		return nil
+	}
+	fmt.Println("Must land first, in order:")
+	for i, e := range result.Chain {
+		line := fmt.Sprintf("  %d. %s, %s (%s)", i+1, e.Name, describePR(e.PR, e.PRState), e.Reason)
+		if !e.Ready {
+			line += " [blocked: " + strings.Join(e.BlockedBy, ", ") + "]"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// describePR renders a PR number and its fetched state for explainStatus.
+func describePR(pr *int, prState string) string {
+	switch {
+	case pr == nil:
+		return "not pushed"
+	case prState == "":
+		return fmt.Sprintf("PR #%d", *pr)
+	default:
+		return fmt.Sprintf("PR #%d %s", *pr, strings.ToLower(prState))
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/frond/internal/dag"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var (
+	jsonEdgesFlag  bool
+	rootsFlag      bool
+	dotFlag        bool
+	mermaidFlag    bool
+	depthLimitFlag int
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the dependency graph as raw edges, DOT or Mermaid",
+	Example: `  # List parent and after edges
+  frond graph
+
+  # Edge-list JSON for custom visualizers
+  frond graph --json-edges
+
+  # One line per independent stack (direct trunk children)
+  frond graph --roots
+
+  # Graphviz, folding everything below the second level
+  frond graph --dot --depth-limit 2 | dot -Tsvg > stack.svg
+
+  # Mermaid flowchart for a PR description
+  frond graph --mermaid`,
+	Args: cobra.NoArgs,
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().BoolVar(&jsonEdgesFlag, "json-edges", false, "Output parent and after edge lists as JSON")
+	graphCmd.Flags().BoolVar(&rootsFlag, "roots", false, "List only the direct children of the trunk, one per line")
+	graphCmd.Flags().BoolVar(&dotFlag, "dot", false, "Output the graph in Graphviz DOT")
+	graphCmd.Flags().BoolVar(&mermaidFlag, "mermaid", false, "Output the graph as a Mermaid flowchart")
+	graphCmd.Flags().IntVar(&depthLimitFlag, "depth-limit", 0, "With --dot or --mermaid, fold branches deeper than n levels below trunk into a \"+N hidden\" node (0 = no limit)")
+	graphCmd.MarkFlagsMutuallyExclusive("json-edges", "roots", "dot", "mermaid")
+	rootCmd.AddCommand(graphCmd)
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	if depthLimitFlag < 0 {
+		return fmt.Errorf("--depth-limit must be non-negative, got %d", depthLimitFlag)
+	}
+	if depthLimitFlag > 0 && !dotFlag && !mermaidFlag {
+		return fmt.Errorf("--depth-limit needs --dot or --mermaid")
+	}
+
+	s, err := state.Read(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	if dotFlag {
+		fmt.Print(dag.RenderDOT(stateToDag(s.Branches), depthLimitFlag))
+		return nil
+	}
+	if mermaidFlag {
+		fmt.Print(dag.RenderMermaid(stateToDag(s.Branches), depthLimitFlag))
+		return nil
+	}
+
+	if rootsFlag {
+		roots := dag.Roots(stateToDag(s.Branches))
+		if jsonOut {
+			return printJSON(rootsResult{Roots: roots})
+		}
+		for _, name := range roots {
+			fmt.Println(name)
+		}
+		return nil
+	}
+
+	edges := dag.RenderEdges(stateToDag(s.Branches))
+
+	if jsonOut || jsonEdgesFlag {
+		return printJSON(edges)
+	}
+	for _, e := range edges.ParentEdges {
+		fmt.Printf("%s → %s\n", e[0], e[1])
+	}
+	for _, e := range edges.AfterEdges {
+		fmt.Printf("%s after %s\n", e[0], e[1])
+	}
+	return nil
+}
@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
 	"unicode"
 
 	"github.com/nvandessel/frond/internal/dag"
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/git"
 	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
 )
 
 // validateBranchName checks that a branch name is safe to use with git commands.
@@ -28,21 +35,54 @@ func validateBranchName(name string) error {
 	return nil
 }
 
-// validateAfterDeps checks that all --after dependencies exist in state and that
-// adding the branch would not create a dependency cycle.
-func validateAfterDeps(branches map[string]state.Branch, name string, after []string) error {
-	for _, dep := range after {
-		if _, tracked := branches[dep]; !tracked {
-			return fmt.Errorf("'%s' is not tracked. Track it first with 'frond track'", dep)
-		}
+// ensureNoRebase refuses to run a mutating command while a rebase is stopped
+// mid-way, since branch tips and the checked-out branch are in flux.
+func ensureNoRebase(ctx context.Context) error {
+	inProgress, err := git.RebaseInProgress(ctx)
+	if err != nil {
+		return fmt.Errorf("checking for rebase in progress: %w", err)
 	}
-	dagBranches := stateToDag(branches)
-	if cyclePath, hasCycle := dag.DetectCycle(dagBranches, name, after); hasCycle {
-		return fmt.Errorf("dependency cycle: %s", strings.Join(cyclePath, " → "))
+	if inProgress {
+		return fmt.Errorf("a rebase is in progress. Finish it with 'git rebase --continue' or run 'frond abort'")
 	}
 	return nil
 }
 
+// repoWebURL returns the web URL of the repo PRs live in: the configured
+// upstream repo (see gh.SetRepo) on origin's host, or origin itself.
+func repoWebURL(ctx context.Context) (string, error) {
+	originURL, err := git.RepoWebURL(ctx)
+	repo := gh.Repo()
+	if repo == "" {
+		return originURL, err
+	}
+	if err != nil {
+		return "https://github.com/" + repo, nil
+	}
+	u, err := url.Parse(originURL)
+	if err != nil {
+		return "https://github.com/" + repo, nil
+	}
+	u.Path = "/" + repo
+	return u.String(), nil
+}
+
+// graphValid reports the problems state.Validate finds in the branch graph:
+// unknown parents or after entries, and cycles through parent links, after
+// dependencies, or both. Every command that edits parents or after lists
+// runs it before state.Write.
+func graphValid(s *state.State) error {
+	problems := s.Validate()
+	if len(problems) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(problems))
+	for i, p := range problems {
+		msgs[i] = p.String()
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
 // stateToDag converts state.Branch map to dag.BranchInfo map for use with dag functions.
 func stateToDag(branches map[string]state.Branch) map[string]dag.BranchInfo {
 	result := make(map[string]dag.BranchInfo, len(branches))
@@ -50,7 +90,164 @@ func stateToDag(branches map[string]state.Branch) map[string]dag.BranchInfo {
 		result[name] = dag.BranchInfo{
 			Parent: b.Parent,
 			After:  b.After,
+			Seq:    b.Seq,
 		}
 	}
 	return result
 }
+
+// forkPoint returns the old base to give 'git rebase --onto' when moving
+// branch name off its parent: the parent's tip while the branch still
+// contains it, otherwise the recorded BaseSHA if the branch contains that,
+// so commits the parent has since rewritten are not replayed.
+func forkPoint(ctx context.Context, name string, b state.Branch) (string, error) {
+	tip, err := git.RevParse(ctx, b.Parent)
+	if err != nil {
+		return "", err
+	}
+	if b.BaseSHA == "" || b.BaseSHA == tip {
+		return tip, nil
+	}
+	if contained, err := git.IsAncestor(ctx, tip, name); err != nil || contained {
+		return tip, err
+	}
+	if contained, err := git.IsAncestor(ctx, b.BaseSHA, name); err == nil && contained {
+		return b.BaseSHA, nil
+	}
+	return tip, nil
+}
+
+// withParentEdges returns a copy of branches in which each branch's parent
+// is also an after dependency, so TopoSort puts parents before children.
+func withParentEdges(branches map[string]dag.BranchInfo) map[string]dag.BranchInfo {
+	result := make(map[string]dag.BranchInfo, len(branches))
+	for name, info := range branches {
+		info.After = append(slices.Clone(info.After), info.Parent)
+		result[name] = info
+	}
+	return result
+}
+
+// branchOrder maps a --branch-order value to the comparison used among
+// sibling or independent branches.
+func branchOrder(order string, branches map[string]dag.BranchInfo) (func(a, b string) int, error) {
+	switch order {
+	case "alpha":
+		return strings.Compare, nil
+	case "created":
+		return dag.ByCreation(branches), nil
+	default:
+		return nil, fmt.Errorf("invalid --branch-order %q: must be alpha or created", order)
+	}
+}
+
+// onFlagValue returns the parent given by --on or its alias --parent, rejecting
+// conflicting values.
+func onFlagValue(cmd *cobra.Command) (string, error) {
+	on, _ := cmd.Flags().GetString("on")
+	parent, _ := cmd.Flags().GetString("parent")
+	if on != "" && parent != "" && on != parent {
+		return "", fmt.Errorf("--on %s and --parent %s disagree; give only one", on, parent)
+	}
+	if on == "" {
+		on = parent
+	}
+	return on, nil
+}
+
+// descendsFrom reports whether name sits below ancestor via parent links.
+func descendsFrom(branches map[string]state.Branch, name, ancestor string) bool {
+	seen := make(map[string]bool)
+	for cur := branches[name].Parent; !seen[cur]; cur = branches[cur].Parent {
+		if cur == ancestor {
+			return true
+		}
+		if _, tracked := branches[cur]; !tracked {
+			return false
+		}
+		seen[cur] = true
+	}
+	return false
+}
+
+// resolveBranchList resolves a comma-separated list given to flag into
+// tracked branch names, reporting every name that does not resolve in a
+// single error.
+func resolveBranchList(branches map[string]state.Branch, flag, list string) (map[string]bool, error) {
+	out := make(map[string]bool)
+	var bad []string
+	for arg := range strings.SplitSeq(list, ",") {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+		name, err := resolveTracked(branches, arg)
+		if err != nil {
+			bad = append(bad, arg)
+			continue
+		}
+		out[name] = true
+	}
+	if len(bad) > 0 {
+		return nil, fmt.Errorf("%s: not tracked or ambiguous: %s", flag, strings.Join(bad, ", "))
+	}
+	return out, nil
+}
+
+// shortNameMatches returns the tracked branches whose short name (the segment
+// after the last '/') equals short, sorted.
+func shortNameMatches(branches map[string]state.Branch, short string) []string {
+	var matches []string
+	for name := range branches {
+		if dag.ShortName(name) == short {
+			matches = append(matches, name)
+		}
+	}
+	slices.Sort(matches)
+	return matches
+}
+
+// resolveTracked maps a command argument to a tracked branch name. An exact
+// name wins; otherwise an unambiguous short name resolves to its full name.
+func resolveTracked(branches map[string]state.Branch, arg string) (string, error) {
+	if _, tracked := branches[arg]; tracked {
+		return arg, nil
+	}
+	matches := shortNameMatches(branches, arg)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("branch '%s' is not tracked", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("'%s' is ambiguous: %s", arg, strings.Join(matches, ", "))
+	}
+}
+
+// completeTrackedBranches is a cobra ValidArgsFunction offering tracked
+// branch names and their unambiguous short names. Ambiguous short names are
+// left out because resolveTracked would reject them.
+func completeTrackedBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	s, err := state.Read(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var out []string
+	for name := range s.Branches {
+		if strings.HasPrefix(name, toComplete) {
+			out = append(out, name)
+		}
+		short := dag.ShortName(name)
+		if short != name && strings.HasPrefix(short, toComplete) && len(shortNameMatches(s.Branches, short)) == 1 {
+			if _, tracked := s.Branches[short]; !tracked {
+				out = append(out, short)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the log of changes frond made to its state",
+	Long:  "List every recorded mutation of frond.json, oldest first, with the command that made it and a summary of what changed.",
+	Example: `  # What did frond do?
+  frond history
+
+  # Machine-readable log
+  frond history --json`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	entries, err := state.History(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("reading history: %w", err)
+	}
+
+	if jsonOut {
+		res := historyResult{Entries: make([]historyEntry, len(entries))}
+		for i, e := range entries {
+			res.Entries[i] = historyEntry{Time: e.Time, Command: e.Command, Summary: e.Summary}
+		}
+		return printJSON(res)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("no history recorded")
+		return nil
+	}
+	for _, e := range entries {
+		fmt.Printf("%s  %s\n", e.Time.Local().Format(time.DateTime), e.Command)
+		for _, line := range e.Summary {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	return nil
+}
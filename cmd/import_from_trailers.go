@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var importFromTrailersCmd = &cobra.Command{
+	Use:   "import-from-trailers",
+	Short: "Rebuild tracked branches from Frond-Parent/Frond-After trailers in open PRs",
+	Long: "Read the repository's open PRs and track every branch whose PR body carries a Frond-Parent trailer " +
+		"(written by 'frond push' when stack_trailers is on). Branches that are already tracked are left alone. " +
+		"Use it to pick up a stack a teammate pushed, or to recover lost state.",
+	Example: `  # Bootstrap frond state from a teammate's stack
+  frond import-from-trailers
+
+  # See what was imported
+  frond import-from-trailers --json`,
+	Args: cobra.NoArgs,
+	RunE: runImportFromTrailers,
+}
+
+func init() {
+	rootCmd.AddCommand(importFromTrailersCmd)
+}
+
+func runImportFromTrailers(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := gh.Available(); err != nil {
+		return fmt.Errorf("gh CLI is required. Install: https://cli.github.com")
+	}
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.ReadOrInit(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	prs, err := gh.PRList(ctx)
+	if err != nil {
+		return fmt.Errorf("listing PRs: %w", err)
+	}
+	// Oldest PRs first, so creation sequence numbers follow PR order.
+	slices.SortFunc(prs, func(a, b gh.PRSummary) int {
+		return cmp.Compare(a.Number, b.Number)
+	})
+
+	result := importResult{Imported: []string{}, Skipped: []string{}}
+	imported := make(map[string]bool)
+	prBase := make(map[string]string)
+	for _, pr := range prs {
+		parent, after, ok := parseStackTrailers(pr.Body)
+		if !ok {
+			continue
+		}
+		name := pr.HeadRefName
+		if _, tracked := s.Branches[name]; tracked || s.IsTrunk(name) {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+		if err := validateBranchName(name); err != nil {
+			return fmt.Errorf("PR #%d: %w", pr.Number, err)
+		}
+		number := pr.Number
+		s.Branches[name] = state.Branch{
+			Parent: parent,
+			After:  after,
+			PR:     &number,
+			Seq:    s.NextSeq(),
+		}
+		imported[name] = true
+		prBase[name] = pr.BaseRefName
+		result.Imported = append(result.Imported, name)
+	}
+
+	// A parent that is neither tracked nor a trunk (e.g. merged and
+	// deleted) falls back to the PR's base, then to the trunk. Dependencies
+	// on branches that are no longer open are already satisfied; drop them
+	// rather than tracking dangling names.
+	for name := range imported {
+		b := s.Branches[name]
+		if _, tracked := s.Branches[b.Parent]; !tracked && !s.IsTrunk(b.Parent) {
+			fallback := s.Trunk
+			if _, tracked := s.Branches[prBase[name]]; (tracked || s.IsTrunk(prBase[name])) && prBase[name] != name {
+				fallback = prBase[name]
+			}
+			fmt.Fprintf(os.Stderr, "warning: %s: Frond-Parent '%s' is not tracked; using '%s'\n", name, b.Parent, fallback)
+			b.Parent = fallback
+		}
+		kept := []string{}
+		for _, dep := range b.After {
+			if _, tracked := s.Branches[dep]; tracked {
+				kept = append(kept, dep)
+			}
+		}
+		b.After = kept
+		s.Branches[name] = b
+	}
+
+	if err := graphValid(s); err != nil {
+		return fmt.Errorf("imported stack is invalid: %w", err)
+	}
+
+	if len(result.Imported) > 0 {
+		if err := state.Write(ctx, s); err != nil {
+			return fmt.Errorf("writing state: %w", err)
+		}
+	}
+
+	if jsonOut {
+		return printJSON(result)
+	}
+	if len(result.Imported) == 0 {
+		fmt.Println("no new branches found in PR trailers")
+	}
+	for _, name := range result.Imported {
+		b := s.Branches[name]
+		fmt.Printf("Tracking '%s' (parent: %s, PR #%d)\n", name, b.Parent, *b.PR)
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Printf("Already tracked: %s\n", strings.Join(result.Skipped, ", "))
+	}
+	return nil
+}
+
+// parseStackTrailers reads the Frond-Parent and Frond-After trailers written
+// by stackTrailers. Like git, it only looks at the body's last paragraph,
+// and only when every line there is a "Key: value" trailer, so prose that
+// mentions a trailer is ignored. ok is false when that block has no
+// Frond-Parent trailer.
+func parseStackTrailers(body string) (parent string, after []string, ok bool) {
+	after = []string{}
+	_, trailers := splitTrailers(body)
+	for _, t := range trailers {
+		switch t.key {
+		case "Frond-Parent":
+			parent = t.value
+		case "Frond-After":
+			for dep := range strings.SplitSeq(t.value, ",") {
+				if dep = strings.TrimSpace(dep); dep != "" {
+					after = append(after, dep)
+				}
+			}
+		}
+	}
+	return parent, after, parent != ""
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/frond/internal/dag"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create frond state for this repository, optionally importing a stack",
+	Long: "Create frond.json with the detected trunk. Other commands do this on first use; init makes it explicit. " +
+		"With --import, load the branches from a 'frond status --json' export as well. " +
+		"The imported graph is validated before anything is written, and importing into a repo that already tracks branches is refused.",
+	Example: `  # On one machine
+  frond status --json > stack.json
+
+  # On a fresh clone or CI runner
+  frond init --import stack.json`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().String("import", "", "Load tracked branches from a 'frond status --json' export")
+	rootCmd.AddCommand(initCmd)
+}
+
+// stackExport is the part of the status JSON that init --import reads.
+type stackExport struct {
+	Trunk    string           `json:"trunk"`
+	Trunks   []string         `json:"trunks"`
+	Branches []dag.JSONBranch `json:"branches"`
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	importPath, _ := cmd.Flags().GetString("import")
+	var export stackExport
+	if importPath != "" {
+		data, err := os.ReadFile(importPath) //nolint:gosec // path is given by the user
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", importPath, err)
+		}
+		if err := json.Unmarshal(data, &export); err != nil {
+			return fmt.Errorf("parsing %s: %w", importPath, err)
+		}
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	// A new state is built in memory and written once, after any import
+	// has been validated, so a bad export leaves nothing behind.
+	s, err := state.Read(ctx)
+	created := errors.Is(err, state.ErrNotInitialized)
+	if created {
+		s, err = state.New(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	result := initResult{Created: created, Imported: []string{}}
+	if importPath != "" {
+		if len(s.Branches) > 0 {
+			return fmt.Errorf("frond already tracks %d branches here; --import only works on empty state", len(s.Branches))
+		}
+		if err := importStack(s, export); err != nil {
+			return fmt.Errorf("importing %s: %w", importPath, err)
+		}
+		for _, b := range export.Branches {
+			result.Imported = append(result.Imported, b.Name)
+		}
+	}
+	if created || importPath != "" {
+		if err := state.Write(ctx, s); err != nil {
+			return fmt.Errorf("writing state: %w", err)
+		}
+	}
+	result.Trunk = s.Trunk
+
+	if jsonOut {
+		return printJSON(result)
+	}
+	if created {
+		fmt.Printf("Initialized frond (trunk: %s)\n", s.Trunk)
+	} else {
+		fmt.Printf("frond already initialized (trunk: %s)\n", s.Trunk)
+	}
+	if len(result.Imported) > 0 {
+		fmt.Printf("Imported %d branches: %s\n", len(result.Imported), strings.Join(result.Imported, ", "))
+	}
+	return nil
+}
+
+// importStack loads the trunks and branches of export into the empty state s.
+// s is left unchanged when the export is invalid.
+func importStack(s *state.State, export stackExport) error {
+	next := *s
+	next.Branches = make(map[string]state.Branch, len(export.Branches))
+	if export.Trunk != "" {
+		next.Trunk = export.Trunk
+	}
+	next.Trunks = slices.Clone(export.Trunks)
+
+	for _, b := range export.Branches {
+		if err := validateBranchName(b.Name); err != nil {
+			return err
+		}
+		if _, dup := next.Branches[b.Name]; dup {
+			return fmt.Errorf("branch '%s' is listed twice", b.Name)
+		}
+		if next.IsTrunk(b.Name) {
+			return fmt.Errorf("branch '%s' is also a trunk", b.Name)
+		}
+		after := b.After
+		if after == nil {
+			after = []string{}
+		}
+		next.Branches[b.Name] = state.Branch{Parent: b.Parent, After: after, PR: b.PR, Seq: b.Seq}
+		next.LastSeq = max(next.LastSeq, b.Seq)
+	}
+	if err := graphValid(&next); err != nil {
+		return fmt.Errorf("invalid graph: %w", err)
+	}
+	*s = next
+	return nil
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// Polling backoff for land --wait: start at landPollInterval and double up
+// to landPollMax. Variables so tests can shorten them.
+var (
+	landPollInterval = 5 * time.Second
+	landPollMax      = time.Minute
+)
+
+var landCmd = &cobra.Command{
+	Use:   "land [<branch>]",
+	Short: "Merge the PRs of a branch and every branch below it, bottom first",
+	Long: "Merge the PR of a branch and of each tracked branch it is stacked on, starting from the bottom of the stack. " +
+		"Every branch on the way needs a PR, and --after dependencies must be inside that stack. " +
+		"After each merge the branch is recorded as merged and untracked, and the next PR is retargeted at the trunk before frond waits on it. " +
+		"Without --wait, frond refuses if a PR is not mergeable right now. With --wait it polls, backing off, until checks pass and GitHub reports the PR mergeable, then merges. " +
+		"Afterwards run 'frond sync' to rebase whatever is still stacked on top.",
+	Example: `  # Merge the current branch's PR now
+  frond land
+
+  # In CI: wait up to 20 minutes per PR for checks, then squash-merge
+  frond land pay/db-schema --wait --timeout 20m --method squash
+
+  # Land a whole stack, bottom first
+  frond land pay/api --wait`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runLand,
+}
+
+func init() {
+	landCmd.Flags().Bool("wait", false, "Poll until checks pass and the PR is mergeable instead of failing")
+	landCmd.Flags().Duration("timeout", 30*time.Minute, "Give up waiting on a PR after this long (with --wait)")
+	landCmd.Flags().String("method", gh.MergeMethodMerge, "Merge method: merge, squash or rebase")
+	rootCmd.AddCommand(landCmd)
+}
+
+func runLand(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	method, _ := cmd.Flags().GetString("method")
+	if !slices.Contains([]string{gh.MergeMethodMerge, gh.MergeMethodSquash, gh.MergeMethodRebase}, method) {
+		return fmt.Errorf("invalid --method %q: want merge, squash or rebase", method)
+	}
+	wait, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if wait && timeout <= 0 {
+		return fmt.Errorf("--timeout must be positive, got %s", timeout)
+	}
+
+	if err := gh.Available(); err != nil {
+		return fmt.Errorf("gh CLI is required. Install: https://cli.github.com")
+	}
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	// The lock is not held while waiting; each merge is recorded under it.
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	var name string
+	if len(args) > 0 {
+		if name, err = resolveTracked(s.Branches, args[0]); err != nil {
+			return err
+		}
+	} else {
+		if name, err = git.CurrentBranch(ctx); err != nil {
+			return fmt.Errorf("getting current branch: %w", err)
+		}
+		if _, tracked := s.Branches[name]; !tracked {
+			return fmt.Errorf("current branch '%s' is not tracked", name)
+		}
+	}
+
+	// The stack from its bottom up to name.
+	chain := []string{name}
+	for cur := s.Branches[name].Parent; !s.IsTrunk(cur); cur = s.Branches[cur].Parent {
+		if _, tracked := s.Branches[cur]; !tracked || slices.Contains(chain, cur) {
+			return fmt.Errorf("'%s' is not rooted on a trunk", name)
+		}
+		chain = append(chain, cur)
+	}
+	slices.Reverse(chain)
+	for i, n := range chain {
+		b := s.Branches[n]
+		if b.PR == nil {
+			return fmt.Errorf("branch '%s' has no PR yet. Run 'frond push' first", n)
+		}
+		var blocked []string
+		for _, dep := range b.After {
+			if !slices.Contains(chain[:i], dep) {
+				blocked = append(blocked, dep)
+			}
+		}
+		if len(blocked) > 0 {
+			return fmt.Errorf("'%s' is blocked by: %s", n, strings.Join(blocked, ", "))
+		}
+	}
+
+	result := landResult{Branch: name, PR: *s.Branches[name].PR, Method: method, Landed: []string{}}
+	// fail reports err together with what already merged.
+	fail := func(err error) error {
+		if len(result.Landed) == 0 {
+			return err
+		}
+		return fmt.Errorf("%w (already landed: %s; run 'frond sync')", err, strings.Join(result.Landed, ", "))
+	}
+
+	for i, n := range chain {
+		pr := *s.Branches[n].PR
+		if i > 0 {
+			// The branch below merged and n now sits on the trunk.
+			if err := retargetPR(ctx, pr, s.Branches[n].Base()); err != nil {
+				return fail(err)
+			}
+		}
+
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if wait {
+			waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		info, err := waitMergeable(waitCtx, pr, n, wait)
+		cancel()
+		if err != nil {
+			return fail(err)
+		}
+
+		// Merge under the command's context: the wait's deadline must not
+		// cut a merge short.
+		if err := gh.PRMerge(ctx, pr, method); err != nil {
+			return fail(fmt.Errorf("merging PR #%d: %w", pr, err))
+		}
+		result.Landed = append(result.Landed, n)
+		if !jsonOut {
+			fmt.Printf("Landed %s (PR #%d)\n", n, pr)
+		}
+
+		if s, err = recordLanded(ctx, n, info.Author); err != nil {
+			return fail(err)
+		}
+	}
+
+	updateStackComments(ctx, s)
+
+	if jsonOut {
+		return printJSON(result)
+	}
+	fmt.Println("Run 'frond sync' to rebase what is still stacked on top")
+	return nil
+}
+
+// recordLanded untracks branch name, whose PR just merged, reparenting its
+// children, and records it as merged. It returns the updated state.
+func recordLanded(ctx context.Context, name, author string) (*state.State, error) {
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading state: %w", err)
+	}
+	if b, tracked := s.Branches[name]; tracked {
+		removeTracked(s, name)
+		s.RecordMerged(name, b, author)
+		if err := state.Write(ctx, s); err != nil {
+			return nil, fmt.Errorf("writing state: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// waitMergeable returns the PR once pr has no pending or failing checks and
+// GitHub reports it mergeable. Failing checks and conflicts are final. Other
+// states are an error unless wait is set, in which case it polls with
+// exponential backoff until ctx is done.
+func waitMergeable(ctx context.Context, pr int, name string, wait bool) (*gh.PRInfo, error) {
+	interval := landPollInterval
+	for {
+		info, err := gh.PRView(ctx, pr)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("gave up waiting on PR #%d (%s): %w", pr, name, ctx.Err())
+			}
+			return nil, fmt.Errorf("viewing PR #%d: %w", pr, err)
+		}
+
+		var reason string
+		switch {
+		case info.State != gh.PRStateOpen:
+			return nil, fmt.Errorf("PR #%d (%s) is %s", pr, name, strings.ToLower(info.State))
+		case info.Checks == gh.ChecksFailing:
+			return nil, fmt.Errorf("PR #%d (%s) has failing checks", pr, name)
+		case info.Mergeable == "CONFLICTING":
+			return nil, fmt.Errorf("PR #%d (%s) has conflicts with its base", pr, name)
+		case info.Checks == gh.ChecksPending:
+			reason = "checks pending"
+		case info.Mergeable != "MERGEABLE":
+			reason = "mergeability unknown"
+		default:
+			return info, nil
+		}
+
+		if !wait {
+			return nil, fmt.Errorf("PR #%d (%s) is not ready to merge: %s. Use --wait to poll", pr, name, reason)
+		}
+		if !jsonOut {
+			fmt.Fprintf(os.Stderr, "waiting on PR #%d (%s): %s; next check in %s\n", pr, name, reason, interval)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up waiting on PR #%d (%s): %w", pr, name, ctx.Err())
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, landPollMax)
+	}
+}
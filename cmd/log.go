@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/frond/internal/dag"
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the commits each tracked branch adds on top of its parent",
+	Long: "Draw the stack tree with each branch's own commits ('git log <parent>..<branch>') listed under it. " +
+		"Branches with no commits of their own are marked (empty).",
+	Example: `  # Reviewer-facing summary of the whole stack
+  frond log
+
+  # Commits per branch for tooling
+  frond log --json`,
+	Args: cobra.NoArgs,
+	RunE: runLog,
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	dagBranches := stateToDag(s.Branches)
+	order, err := dag.TopoSort(dagBranches)
+	if err != nil {
+		return fmt.Errorf("computing topological order: %w", err)
+	}
+
+	result := logResult{Branches: []logBranch{}}
+	details := make(map[string][]string, len(order))
+	notes := make(map[string]string)
+	for _, name := range order {
+		parent := s.Branches[name].Parent
+		commits, err := git.LogRange(ctx, parent, name)
+		if err != nil {
+			return fmt.Errorf("listing commits of %s: %w", name, err)
+		}
+		lb := logBranch{Name: name, Parent: parent, Commits: []logCommit{}}
+		for _, c := range commits {
+			lb.Commits = append(lb.Commits, logCommit(c))
+			details[name] = append(details[name], fmt.Sprintf("%.7s %s", c.SHA, c.Subject))
+		}
+		if len(commits) == 0 {
+			notes[name] = "(empty)"
+		}
+		result.Branches = append(result.Branches, lb)
+	}
+
+	if jsonOut {
+		return printJSON(result)
+	}
+	fmt.Print(dag.RenderTreesWith(s.AllTrunks(), dagBranches, nil, nil, dag.RenderOptions{
+		Notes:   notes,
+		Details: details,
+	}))
+	return nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move [<branch>] --on <parent>",
+	Short: "Reparent a tracked branch, rebasing its commits onto the new parent",
+	Long: "Change a branch's parent: replay its own commits onto the new parent, record the new parent, and retarget its PR. " +
+		"With --keep-commits the commits are left where they are and only the recorded parent and PR base change.",
+	Example: `  # Move the current branch onto trunk
+  frond move --on main
+
+  # Restack a branch onto a sibling
+  frond move pay/api --on pay/db-schema
+
+  # The branch already sits on the new parent; just fix the bookkeeping
+  frond move pay/api --on pay/db-schema --keep-commits`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runMove,
+}
+
+func init() {
+	moveCmd.Flags().String("on", "", "New parent branch (trunk or tracked)")
+	moveCmd.Flags().Bool("keep-commits", false, "Only update the recorded parent and PR base; do not rebase")
+	moveCmd.Flags().Bool("no-rebase", false, "Alias for --keep-commits")
+	_ = moveCmd.MarkFlagRequired("on")
+	rootCmd.AddCommand(moveCmd)
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	current, err := git.CurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("getting current branch: %w", err)
+	}
+	name := current
+	if len(args) > 0 {
+		if name, err = resolveTracked(s.Branches, args[0]); err != nil {
+			return err
+		}
+	} else if _, tracked := s.Branches[name]; !tracked {
+		return fmt.Errorf("current branch '%s' is not tracked", name)
+	}
+
+	onFlag, _ := cmd.Flags().GetString("on")
+	parent := onFlag
+	if !s.IsTrunk(onFlag) {
+		if parent, err = resolveTracked(s.Branches, onFlag); err != nil {
+			return err
+		}
+	}
+	if parent == name || descendsFrom(s.Branches, parent, name) {
+		return fmt.Errorf("cannot move '%s' onto '%s': it would become its own ancestor", name, parent)
+	}
+
+	b := s.Branches[name]
+	oldParent := b.Parent
+	if oldParent == parent {
+		return fmt.Errorf("'%s' is already on '%s'", name, parent)
+	}
+
+	trial := *s
+	trial.Branches = maps.Clone(s.Branches)
+	trial.Branches[name] = state.Branch{Parent: parent, After: b.After}
+	if err := graphValid(&trial); err != nil {
+		return fmt.Errorf("cannot move '%s' onto '%s': %w", name, parent, err)
+	}
+
+	keep, _ := cmd.Flags().GetBool("keep-commits")
+	if noRebase, _ := cmd.Flags().GetBool("no-rebase"); noRebase {
+		keep = true
+	}
+
+	if keep {
+		based, err := git.IsAncestor(ctx, parent, name)
+		if err != nil {
+			return fmt.Errorf("checking %s against %s: %w", name, parent, err)
+		}
+		if !based {
+			fmt.Fprintf(os.Stderr, "warning: %s is not based on %s; its PR will include unrelated changes until it is rebased\n", name, parent)
+		}
+		// The commits stay where they are, so the recorded start is moot.
+		b.BaseSHA = ""
+	} else {
+		oldBase, err := forkPoint(ctx, name, b)
+		if err != nil {
+			return fmt.Errorf("resolving %s: %w", oldParent, err)
+		}
+		if err := git.RebaseOnto(ctx, name, oldBase, parent); err != nil {
+			var conflictErr *git.RebaseConflictError
+			if errors.As(err, &conflictErr) {
+				return fmt.Errorf("rebasing %s onto %s hit conflicts; nothing was changed", name, parent)
+			}
+			return fmt.Errorf("rebasing %s onto %s: %w", name, parent, err)
+		}
+		if current != name {
+			if err := git.Checkout(ctx, current); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: could not restore branch %s: %v\n", current, err)
+			}
+		}
+		b.BaseSHA, _ = git.RevParse(ctx, parent)
+	}
+
+	b.Parent = parent
+	s.Branches[name] = b
+	if err := state.Write(ctx, s); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+
+	if b.PR != nil {
+		if b.BaseOverride == "" {
+			if err := gh.PREdit(ctx, *b.PR, parent); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: could not retarget PR #%d for %s: %v\n", *b.PR, name, err)
+			}
+		}
+		updateStackComments(ctx, s)
+	}
+
+	if jsonOut {
+		return printJSON(moveResult{
+			Name:      name,
+			Parent:    parent,
+			OldParent: oldParent,
+			Rebased:   !keep,
+		})
+	}
+	if keep {
+		fmt.Printf("Moved '%s' onto '%s' (was: %s); commits left in place\n", name, parent, oldParent)
+	} else {
+		fmt.Printf("Moved '%s' onto '%s' (was: %s)\n", name, parent, oldParent)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var moveDepsCmd = &cobra.Command{
+	Use:   "move-deps --from <branch> --to <branch> <dep>",
+	Short: "Move an --after dependency from one branch to another",
+	Long: "Remove <dep> from the after list of --from and add it to the after list of --to. " +
+		"The move is refused if it would create a dependency cycle.",
+	Example: `  # pay/api no longer waits on auth/session; pay/e2e does
+  frond move-deps --from pay/api --to pay/e2e auth/session`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runMoveDeps,
+}
+
+func init() {
+	moveDepsCmd.Flags().String("from", "", "Branch whose after list loses the dependency")
+	moveDepsCmd.Flags().String("to", "", "Branch whose after list gains the dependency")
+	rootCmd.AddCommand(moveDepsCmd)
+}
+
+func runMoveDeps(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	fromFlag, _ := cmd.Flags().GetString("from")
+	toFlag, _ := cmd.Flags().GetString("to")
+	if fromFlag == "" || toFlag == "" {
+		return fmt.Errorf("both --from and --to are required")
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	from, err := resolveTracked(s.Branches, fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := resolveTracked(s.Branches, toFlag)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return fmt.Errorf("--from and --to are both '%s'", from)
+	}
+
+	dep, err := resolveTracked(s.Branches, args[0])
+	if err != nil {
+		return err
+	}
+	fromBranch := s.Branches[from]
+	if !slices.Contains(fromBranch.After, dep) {
+		return fmt.Errorf("'%s' does not depend on '%s'", from, dep)
+	}
+	if dep == to {
+		return fmt.Errorf("'%s' cannot depend on itself", to)
+	}
+
+	fromBranch.After = slices.DeleteFunc(slices.Clone(fromBranch.After), func(d string) bool { return d == dep })
+	s.Branches[from] = fromBranch
+
+	toBranch := s.Branches[to]
+	if !slices.Contains(toBranch.After, dep) {
+		toBranch.After = append(slices.Clone(toBranch.After), dep)
+	}
+	s.Branches[to] = toBranch
+	if err := graphValid(s); err != nil {
+		return fmt.Errorf("cannot move '%s' to '%s': %w", dep, to, err)
+	}
+
+	if err := state.Write(ctx, s); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+
+	if jsonOut {
+		return printJSON(moveDepsResult{
+			Dep:       dep,
+			From:      from,
+			To:        to,
+			FromAfter: fromBranch.After,
+			ToAfter:   toBranch.After,
+		})
+	}
+	fmt.Printf("Moved dependency '%s' from '%s' to '%s'\n", dep, from, to)
+	return nil
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var bottomCmd = &cobra.Command{
+	Use:   "bottom",
+	Short: "Check out the trunk-most tracked ancestor of the current branch",
+	Example: `  # Jump to the first branch of the current stack
+  frond bottom`,
+	Args: cobra.NoArgs,
+	RunE: runBottom,
+}
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Check out the tip of the current stack",
+	Long:  "Follow single children down from the current branch and check out the last one. Fails at a fork, naming the branches to choose from.",
+	Example: `  # Jump to the last branch of a linear stack
+  frond top`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+var upCmd = &cobra.Command{
+	Use:   "up [<child>]",
+	Short: "Check out the child of the current branch",
+	Long:  "Move one branch away from the trunk. When the current branch has several children, name the one to check out.",
+	Example: `  # Move to the next branch of a linear stack
+  frond up
+
+  # At a fork, pick the child
+  frond up pay/api`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUp,
+}
+
+var downCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Check out the parent of the current branch",
+	Example: `  # Move one branch towards the trunk
+  frond down`,
+	Args: cobra.NoArgs,
+	RunE: runDown,
+}
+
+func init() {
+	rootCmd.AddCommand(bottomCmd)
+	rootCmd.AddCommand(topCmd)
+	rootCmd.AddCommand(upCmd)
+	rootCmd.AddCommand(downCmd)
+}
+
+func runBottom(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, current, err := navigationStart(ctx)
+	if err != nil {
+		return err
+	}
+
+	target := current
+	seen := map[string]bool{current: true}
+	for {
+		parent := s.Branches[target].Parent
+		if _, tracked := s.Branches[parent]; !tracked || seen[parent] {
+			break
+		}
+		seen[parent] = true
+		target = parent
+	}
+	return navigateTo(ctx, current, target)
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, current, err := navigationStart(ctx)
+	if err != nil {
+		return err
+	}
+
+	children := make(map[string][]string)
+	for name, b := range s.Branches {
+		children[b.Parent] = append(children[b.Parent], name)
+	}
+
+	target := current
+	seen := map[string]bool{current: true}
+	for {
+		kids := children[target]
+		if len(kids) == 0 || seen[kids[0]] {
+			break
+		}
+		if len(kids) > 1 {
+			slices.Sort(kids)
+			return fmt.Errorf("'%s' has several children (%s); check one out and run 'frond top' again", target, strings.Join(kids, ", "))
+		}
+		seen[kids[0]] = true
+		target = kids[0]
+	}
+	return navigateTo(ctx, current, target)
+}
+
+func runUp(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, current, err := navigationStartAllowTrunk(ctx)
+	if err != nil {
+		return err
+	}
+
+	var kids []string
+	for name, b := range s.Branches {
+		if b.Parent == current {
+			kids = append(kids, name)
+		}
+	}
+	slices.Sort(kids)
+
+	if len(args) > 0 {
+		if !slices.Contains(kids, args[0]) {
+			if len(kids) == 0 {
+				return fmt.Errorf("'%s' is not a child of '%s'; it has no children", args[0], current)
+			}
+			return fmt.Errorf("'%s' is not a child of '%s' (children: %s)", args[0], current, strings.Join(kids, ", "))
+		}
+		return navigateTo(ctx, current, args[0])
+	}
+	switch len(kids) {
+	case 0:
+		return fmt.Errorf("'%s' has no children", current)
+	case 1:
+		return navigateTo(ctx, current, kids[0])
+	default:
+		return fmt.Errorf("'%s' has several children (%s); pick one with 'frond up <child>'", current, strings.Join(kids, ", "))
+	}
+}
+
+func runDown(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	s, current, err := navigationStartAllowTrunk(ctx)
+	if err != nil {
+		return err
+	}
+	if s.IsTrunk(current) {
+		if jsonOut {
+			return printJSON(navigateResult{From: current, To: current})
+		}
+		fmt.Println("already at trunk")
+		return nil
+	}
+	return navigateTo(ctx, current, s.Branches[current].Parent)
+}
+
+// navigationStartAllowTrunk is navigationStart, but also accepts a current
+// branch that is one of the trunks.
+func navigationStartAllowTrunk(ctx context.Context) (*state.State, string, error) {
+	if err := ensureNoRebase(ctx); err != nil {
+		return nil, "", err
+	}
+	s, err := state.Read(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading state: %w", err)
+	}
+	current, err := git.CurrentBranch(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("getting current branch: %w", err)
+	}
+	if _, tracked := s.Branches[current]; !tracked && !s.IsTrunk(current) {
+		return nil, "", fmt.Errorf("current branch '%s' is not tracked", current)
+	}
+	return s, current, nil
+}
+
+// navigationStart reads state and returns it with the current branch, which
+// must be tracked.
+func navigationStart(ctx context.Context) (*state.State, string, error) {
+	s, current, err := navigationStartAllowTrunk(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, tracked := s.Branches[current]; !tracked {
+		return nil, "", fmt.Errorf("current branch '%s' is not tracked", current)
+	}
+	return s, current, nil
+}
+
+// navigateTo checks out target (unless it is already current) and reports
+// the move.
+func navigateTo(ctx context.Context, current, target string) error {
+	if target != current {
+		if err := git.Checkout(ctx, target); err != nil {
+			return fmt.Errorf("checking out %s: %w", target, err)
+		}
+	}
+	if jsonOut {
+		return printJSON(navigateResult{From: current, To: target})
+	}
+	if target == current {
+		fmt.Printf("Already on '%s'\n", target)
+		return nil
+	}
+	fmt.Printf("Switched to '%s'\n", target)
+	return nil
+}
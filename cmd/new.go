@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/nvandessel/frond/internal/git"
@@ -19,14 +20,19 @@ var newCmd = &cobra.Command{
   frond new step-2 --on step-1
 
   # Create with a dependency (must merge after prereq)
-  frond new my-feature --on main --after prereq-branch`,
+  frond new my-feature --on main --after prereq-branch
+
+  # Depend on the branch you are on (not just stack on it)
+  frond new follow-up --on main --after-current`,
 	Args: cobra.ExactArgs(1),
 	RunE: runNew,
 }
 
 func init() {
 	newCmd.Flags().String("on", "", "Git parent branch (PR base)")
+	newCmd.Flags().String("parent", "", "Alias for --on")
 	newCmd.Flags().String("after", "", "Comma-separated logical dependencies")
+	newCmd.Flags().Bool("after-current", false, "Add the current branch to the logical dependencies")
 	rootCmd.AddCommand(newCmd)
 }
 
@@ -38,6 +44,10 @@ func runNew(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
 	// 1. Lock state, defer unlock
 	unlock, err := state.Lock(ctx)
 	if err != nil {
@@ -60,15 +70,18 @@ func runNew(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("branch '%s' already exists. Use 'frond track' to add it", name)
 	}
 
-	// 3. Resolve parent: --on flag -> current branch if tracked -> trunk
-	onFlag, _ := cmd.Flags().GetString("on")
+	// 3. Resolve parent: --on flag -> current branch if tracked or a trunk -> trunk
+	on, err := onFlagValue(cmd)
+	if err != nil {
+		return err
+	}
 	parent := s.Trunk
-	if onFlag != "" {
-		parent = onFlag
+	if on != "" {
+		parent = on
 	} else {
 		current, err := git.CurrentBranch(ctx)
 		if err == nil {
-			if _, tracked := s.Branches[current]; tracked {
+			if _, tracked := s.Branches[current]; tracked || s.IsTrunk(current) {
 				parent = current
 			}
 		}
@@ -80,6 +93,18 @@ func runNew(cmd *cobra.Command, args []string) error {
 	if afterFlag != "" {
 		after = strings.Split(afterFlag, ",")
 	}
+	if afterCurrent, _ := cmd.Flags().GetBool("after-current"); afterCurrent {
+		current, err := git.CurrentBranch(ctx)
+		if err != nil {
+			return fmt.Errorf("--after-current: %w", err)
+		}
+		if _, tracked := s.Branches[current]; !tracked {
+			return fmt.Errorf("--after-current: current branch '%s' is not tracked", current)
+		}
+		if !slices.Contains(after, current) {
+			after = append(after, current)
+		}
+	}
 
 	// 5. Validate parent branch exists in git
 	parentExists, err := git.BranchExists(ctx, parent)
@@ -91,8 +116,18 @@ func runNew(cmd *cobra.Command, args []string) error {
 	}
 
 	// 6. Validate --after deps and check for cycles
-	if err := validateAfterDeps(s.Branches, name, after); err != nil {
-		return err
+	if after == nil {
+		after = []string{}
+	}
+	s.Branches[name] = state.Branch{Parent: parent, After: after}
+	if err := graphValid(s); err != nil {
+		return fmt.Errorf("cannot create '%s': %w", name, err)
+	}
+
+	// Remember where the branch starts, for precise rebases later.
+	baseSHA, err := git.RevParse(ctx, parent)
+	if err != nil {
+		return fmt.Errorf("resolving parent: %w", err)
 	}
 
 	// 7. git.CreateBranch (also checks it out)
@@ -101,12 +136,11 @@ func runNew(cmd *cobra.Command, args []string) error {
 	}
 
 	// 7. Write branch to state.Branches
-	if after == nil {
-		after = []string{}
-	}
 	s.Branches[name] = state.Branch{
-		Parent: parent,
-		After:  after,
+		Parent:  parent,
+		After:   after,
+		Seq:     s.NextSeq(),
+		BaseSHA: baseSHA,
 	}
 
 	// 8. Write state
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var preflightCmd = &cobra.Command{
+	Use:     "preflight",
+	Aliases: []string{"doctor"},
+	Short:   "Check that the environment is ready for frond",
+	Long: "Verify the git CLI, the current repository, gh installation and login, and frond state in one go. " +
+		"With state present, also check that it agrees with git: the current branch should be tracked (or a trunk), " +
+		"every tracked branch should exist, the graph should be sound (no unknown parents or after deps, no cycles), " +
+		"and no two branches should record the same PR. " +
+		"Exits non-zero if any critical check fails; state problems are reported but not critical.",
+	Example: `  # Check before starting a session
+  frond preflight
+
+  # Machine-readable report for agents
+  frond preflight --json`,
+	Args: cobra.NoArgs,
+	RunE: runPreflight,
+}
+
+func init() {
+	rootCmd.AddCommand(preflightCmd)
+}
+
+func runPreflight(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	res := preflightResult{OK: true}
+	check := func(name string, critical bool, err error, okDetail string) {
+		c := preflightCheck{Name: name, OK: err == nil, Critical: critical, Detail: okDetail}
+		if err != nil {
+			c.Detail = err.Error()
+			if critical {
+				res.OK = false
+			}
+		}
+		res.Checks = append(res.Checks, c)
+	}
+
+	gitPath, err := exec.LookPath("git")
+	check("git", true, err, gitPath)
+
+	dir, err := git.CommonDir(ctx)
+	check("repo", true, err, dir)
+
+	ghErr := gh.Available()
+	check("gh", true, ghErr, "installed")
+	if ghErr == nil {
+		check("gh_auth", true, gh.AuthStatus(ctx), "logged in")
+	} else {
+		check("gh_auth", true, errors.New("skipped: gh is not installed"), "")
+	}
+
+	s, stateErr := state.Read(ctx)
+	check("state", false, stateErr, "frond.json found")
+	if stateErr == nil {
+		if current, err := git.CurrentBranch(ctx); err == nil && current != "HEAD" {
+			check("current_branch", false, currentBranchTracked(s, current), current+" is tracked")
+		}
+		check("tracked_branches", false, trackedBranchesExist(ctx, s), "all exist in git")
+		check("graph", false, graphValid(s), "parents and after deps resolve, no cycles")
+		check("pr_numbers", false, uniquePRs(s), "no PR claimed by two branches")
+	}
+
+	if jsonOut {
+		if err := printJSON(res); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range res.Checks {
+			mark := "✓"
+			if !c.OK {
+				mark = "✗"
+				if !c.Critical {
+					mark = "!"
+				}
+			}
+			fmt.Printf("%s %s: %s\n", mark, c.Name, c.Detail)
+		}
+	}
+	if !res.OK {
+		// The failing checks are already reported; only the exit code remains.
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+// currentBranchTracked reports a current branch that git has but frond does
+// not track.
+func currentBranchTracked(s *state.State, current string) error {
+	if _, tracked := s.Branches[current]; tracked || s.IsTrunk(current) {
+		return nil
+	}
+	return fmt.Errorf("'%s' is not tracked; run 'frond track %s --on <parent>'", current, current)
+}
+
+// trackedBranchesExist reports tracked branches that no longer exist in git.
+func trackedBranchesExist(ctx context.Context, s *state.State) error {
+	var missing []string
+	for name := range s.Branches {
+		exists, err := git.BranchExists(ctx, name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	slices.Sort(missing)
+	return fmt.Errorf("missing from git: %s; run 'frond untrack <branch>' for each", strings.Join(missing, ", "))
+}
+
+// uniquePRs reports PR numbers that more than one branch records, which
+// makes sync and push retarget the same PR back and forth.
+func uniquePRs(s *state.State) error {
+	dups := s.DuplicatePRs()
+	if len(dups) == 0 {
+		return nil
+	}
+	var msgs []string
+	for _, pr := range slices.Sorted(maps.Keys(dups)) {
+		msgs = append(msgs, fmt.Sprintf("PR #%d is claimed by %s", pr, strings.Join(dups[pr], ", ")))
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
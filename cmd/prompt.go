@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// noInteractive is set by the --no-interactive persistent flag.
+var noInteractive bool
+
+// promptIn and promptOut are where prompts read answers and write questions.
+// They are variables so tests can drive prompts without a terminal.
+var (
+	promptIn  io.Reader = os.Stdin
+	promptOut io.Writer = os.Stderr
+)
+
+// isTerminal reports whether stdin is a terminal; tests override it.
+var isTerminal = func() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// interactive reports whether frond may block waiting for user input. It is
+// the single gate every prompt goes through: --no-interactive,
+// FROND_NO_INTERACTIVE, --json, or a non-terminal stdin all disable it.
+func interactive() bool {
+	if noInteractive || jsonOut {
+		return false
+	}
+	if v, ok := os.LookupEnv("FROND_NO_INTERACTIVE"); ok && v != "" {
+		if off, err := strconv.ParseBool(v); err != nil || off {
+			return false
+		}
+	}
+	return isTerminal()
+}
+
+// confirm asks a yes/no question and returns the answer. When frond is not
+// interactive it returns def without reading input, so def must be the safe
+// choice.
+func confirm(question string, def bool) (bool, error) {
+	if !interactive() {
+		return def, nil
+	}
+
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	fmt.Fprintf(promptOut, "%s %s ", question, hint)
+
+	line, err := bufio.NewReader(promptIn).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("reading answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	default:
+		return def, nil
+	}
+}
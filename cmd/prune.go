@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune --merged",
+	Short: "Forget branches whose PRs have merged",
+	Long:  "Sync, land and 'untrack --all-merged' keep a record of merged branches so 'frond status --show-merged' can still show them. Prune drops those records once the stack no longer needs the context.",
+	Example: `  # Drop every merged-branch record
+  frond prune --merged`,
+	Args: cobra.NoArgs,
+	RunE: runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().Bool("merged", false, "Remove records of merged branches")
+	rootCmd.AddCommand(pruneCmd)
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if merged, _ := cmd.Flags().GetBool("merged"); !merged {
+		return fmt.Errorf("nothing to prune; pass --merged")
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	pruned := mergedResults(s.Merged)
+	if len(pruned) > 0 {
+		s.Merged = nil
+		if err := state.Write(ctx, s); err != nil {
+			return fmt.Errorf("writing state: %w", err)
+		}
+	}
+
+	if jsonOut {
+		if pruned == nil {
+			pruned = []mergedResult{}
+		}
+		return printJSON(pruneResult{Pruned: pruned})
+	}
+	if len(pruned) == 0 {
+		fmt.Println("no merged branches to prune")
+		return nil
+	}
+	for _, m := range pruned {
+		fmt.Printf("Pruned %s\n", m.Name)
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -21,6 +25,33 @@ var pushCmd = &cobra.Command{
   # Push with a custom title and as draft
   frond push -t "Add user auth" --draft
 
+  # Do nothing if the branch has not changed since the last push
+  frond push --skip-unchanged
+
+  # Tag the PR body for external tooling
+  frond push --trailer Stack-Id=payments --trailer Ticket=PAY-12
+
+  # Jump to the PR in the browser afterwards
+  frond push --web
+
+  # Check the recorded parent against git history before pushing
+  frond push --base-branch-from-git
+
+  # Same, applying a detected parent without asking (CI, agents)
+  frond push --base-branch-from-git --yes
+
+  # Label by area using label_rules (e.g. pay/=area/pay)
+  frond push --label-from-path --label needs-review
+
+  # Do not open a PR that cannot merge yet
+  frond push --ready-deps-only
+
+  # Open the PR upstream from a fork
+  frond push --head-repo alice --repo acme/widgets
+
+  # Refresh the stack comment after editing a PR by hand
+  frond push --update-comment-only
+
   # Push with JSON output for scripting
   frond push --json`,
 	RunE: runPush,
@@ -30,6 +61,17 @@ func init() {
 	pushCmd.Flags().StringP("title", "t", "", "PR title (default: branch name humanized)")
 	pushCmd.Flags().StringP("body", "b", "", "PR body")
 	pushCmd.Flags().Bool("draft", false, "Create as draft PR")
+	pushCmd.Flags().Bool("update-comment-only", false, "Only refresh the stack comment; skip git push and PR create/retarget")
+	pushCmd.Flags().Bool("skip-unchanged", false, "Skip pushing when the branch tip matches the last recorded push")
+	pushCmd.Flags().StringArray("trailer", nil, "Set a 'Key: value' trailer in the PR body, given as key=value (repeatable)")
+	pushCmd.Flags().Bool("web", false, "Open the PR in the browser after creating or updating it")
+	pushCmd.Flags().Bool("base-branch-from-git", false, "Detect the branch's real parent from git history and offer to fix a mismatched recorded parent")
+	pushCmd.Flags().Bool("yes", false, "With --base-branch-from-git, apply a detected parent without asking")
+	pushCmd.Flags().String("head-repo", "", "Fork that holds the branch (owner or owner/name); a new PR's head becomes owner:branch")
+	pushCmd.Flags().StringArray("label", nil, "Add a label to the PR (repeatable)")
+	pushCmd.Flags().Bool("label-from-path", false, "Also add labels from the label_rules config whose prefix matches the branch name")
+	pushCmd.Flags().Bool("ready-deps-only", false, "Refuse to push while any --after dependency is still unmerged, instead of warning")
+	pushCmd.Flags().String("repo", "", "Upstream repo (owner/name) for PRs, for fork workflows; saved as the repo config for later commands")
 	rootCmd.AddCommand(pushCmd)
 }
 
@@ -48,9 +90,177 @@ func humanizeTitle(branch string) string {
 	return strings.Join(words, " ")
 }
 
+// parseTrailers turns key=value flag values into "Key: value" trailer lines.
+func parseTrailers(raw []string) ([]string, error) {
+	var out []string
+	for _, r := range raw {
+		key, value, ok := strings.Cut(r, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || !trailerKey(key) {
+			return nil, fmt.Errorf("invalid --trailer %q: want key=value with a key of letters, digits and dashes", r)
+		}
+		out = append(out, key+": "+value)
+	}
+	return out, nil
+}
+
+// pushLabels returns the explicit labels followed by, with fromPath, the
+// label of every rule whose prefix matches branch, without duplicates.
+func pushLabels(st *state.State, branch string, explicit []string, fromPath bool) ([]string, error) {
+	labels := slices.Clone(explicit)
+	if fromPath {
+		if v := st.Config[state.ConfigLabelRules]; v != "" {
+			rules, err := state.ParseLabelRules(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s config: %w", state.ConfigLabelRules, err)
+			}
+			for _, r := range rules {
+				if strings.HasPrefix(branch, r.Prefix) {
+					labels = append(labels, r.Label)
+				}
+			}
+		}
+	}
+	var out []string
+	for _, l := range labels {
+		if !slices.Contains(out, l) {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+// unmetDeps returns the --after dependencies of br that are still tracked,
+// i.e. not merged yet.
+func unmetDeps(st *state.State, br state.Branch) []string {
+	var unmet []string
+	for _, dep := range br.After {
+		if _, tracked := st.Branches[dep]; tracked {
+			unmet = append(unmet, dep)
+		}
+	}
+	return unmet
+}
+
+// stackTrailers returns the Frond-Parent and, if any, Frond-After trailers
+// that let external tools rebuild the stack from PR bodies alone.
+func stackTrailers(br state.Branch) []string {
+	out := []string{"Frond-Parent: " + br.Parent}
+	if len(br.After) > 0 {
+		out = append(out, "Frond-After: "+strings.Join(br.After, ","))
+	}
+	return out
+}
+
+// appendTrailers adds trailer lines to body as a final paragraph.
+func appendTrailers(body string, trailers []string) string {
+	if len(trailers) == 0 {
+		return body
+	}
+	block := strings.Join(trailers, "\n")
+	if body == "" {
+		return block
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + block
+}
+
+// trailerKey reports whether key is a git-style trailer key: letters,
+// digits and dashes, not starting with a dash.
+func trailerKey(key string) bool {
+	if key == "" || key[0] == '-' {
+		return false
+	}
+	for _, r := range key {
+		if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// trailer is one "Key: value" line of a trailer block. line is the line as
+// written, so a trailer that is kept is written back unchanged.
+type trailer struct {
+	key, value, line string
+}
+
+// splitTrailers splits body into the text before its final paragraph and
+// that paragraph's trailers. trailers is nil unless every line of the final
+// paragraph is a "Key: value" trailer with a git-style key, so prose, URLs
+// and the like are never taken for one.
+func splitTrailers(body string) (head string, trailers []trailer) {
+	block := strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+	if i := strings.LastIndex(block, "\n\n"); i >= 0 {
+		head, block = block[:i], block[i+2:]
+	}
+	for line := range strings.Lines(block) {
+		line = strings.TrimSuffix(line, "\n")
+		key, value, found := strings.Cut(line, ": ")
+		if !found || !trailerKey(key) {
+			return "", nil
+		}
+		trailers = append(trailers, trailer{key: key, value: strings.TrimSpace(value), line: line})
+	}
+	return head, trailers
+}
+
+// setTrailers returns body with trailers in its final paragraph. If that
+// paragraph already is a trailer block, its lines keyed by one of replace
+// are dropped and the rest kept as is, ahead of trailers.
+func setTrailers(body string, trailers, replace []string) string {
+	head, existing := splitTrailers(body)
+	if existing == nil {
+		return appendTrailers(body, trailers)
+	}
+	var kept []string
+	for _, t := range existing {
+		if !slices.Contains(replace, t.key) {
+			kept = append(kept, t.line)
+		}
+	}
+	return appendTrailers(head, append(kept, trailers...))
+}
+
+// trailerKeys returns the keys of "Key: value" trailer lines.
+func trailerKeys(trailers []string) []string {
+	keys := make([]string, 0, len(trailers))
+	for _, t := range trailers {
+		key, _, _ := strings.Cut(t, ":")
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// syncTrailers brings the trailer block of PR n's body up to date, so
+// trailers set after the PR was opened (or a changed Frond-Parent) reach
+// it. The body is only edited when it changes.
+func syncTrailers(ctx context.Context, n int, trailers, replace []string) error {
+	if len(trailers) == 0 {
+		return nil
+	}
+	body, err := gh.PRBody(ctx, n)
+	if err != nil {
+		return fmt.Errorf("reading PR #%d: %w", n, err)
+	}
+	updated := setTrailers(body, trailers, replace)
+	if updated == strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n")) {
+		return nil
+	}
+	if err := gh.PREditBody(ctx, n, updated); err != nil {
+		return fmt.Errorf("updating trailers of PR #%d: %w", n, err)
+	}
+	return nil
+}
+
 func runPush(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
+	trailerFlags, _ := cmd.Flags().GetStringArray("trailer")
+	trailers, err := parseTrailers(trailerFlags)
+	if err != nil {
+		return err
+	}
+
 	// 1. Check gh is available.
 	if err := gh.Available(); err != nil {
 		return fmt.Errorf("gh CLI is required. Install: https://cli.github.com")
@@ -62,6 +272,10 @@ func runPush(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting current branch: %w", err)
 	}
 
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
 	// 3. Lock state, defer unlock.
 	unlock, err := state.Lock(ctx)
 	if err != nil {
@@ -81,78 +295,134 @@ func runPush(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("current branch '%s' is not tracked", branch)
 	}
 
-	// 6. Push to origin.
-	if err := git.Push(ctx, branch); err != nil {
-		return fmt.Errorf("pushing to origin: %w", err)
+	if err := applyRepoFlag(cmd, st); err != nil {
+		return err
 	}
 
-	created := false
-	var prNumber int
+	labelFlags, _ := cmd.Flags().GetStringArray("label")
+	fromPath, _ := cmd.Flags().GetBool("label-from-path")
+	labels, err := pushLabels(st, branch, labelFlags, fromPath)
+	if err != nil {
+		return err
+	}
 
-	// 7. If no PR exists, create one.
-	if br.PR == nil {
-		title, _ := cmd.Flags().GetString("title")
-		if title == "" {
-			title = humanizeTitle(branch)
+	// Refresh the stack comment only: no git push, no PR create/retarget.
+	if commentOnly, _ := cmd.Flags().GetBool("update-comment-only"); commentOnly {
+		if br.PR == nil {
+			return fmt.Errorf("branch '%s' has no PR yet. Run 'frond push' first", branch)
 		}
-		body, _ := cmd.Flags().GetString("body")
-		draft, _ := cmd.Flags().GetBool("draft")
-
-		prNumber, err = gh.PRCreate(ctx, gh.PRCreateOpts{
-			Base:  br.Parent,
-			Head:  branch,
-			Title: title,
-			Body:  body,
-			Draft: draft,
-		})
-		if err != nil {
-			return fmt.Errorf("creating PR: %w", err)
+		updateStackComments(ctx, st)
+		if jsonOut {
+			return printJSON(pushResult{
+				Branch:      branch,
+				PR:          *br.PR,
+				CommentOnly: true,
+			})
 		}
+		fmt.Printf("Updated stack comment on PR #%d\n", *br.PR)
+		return nil
+	}
 
-		br.PR = &prNumber
-		st.Branches[branch] = br
-		if err := state.Write(ctx, st); err != nil {
-			return fmt.Errorf("writing state: %w", err)
+	if readyOnly, _ := cmd.Flags().GetBool("ready-deps-only"); readyOnly {
+		if unmet := unmetDeps(st, br); len(unmet) > 0 {
+			return fmt.Errorf("'%s' has unmet dependencies: %s; wait for them to merge or drop --ready-deps-only", branch, strings.Join(unmet, ", "))
 		}
-		created = true
-	} else {
-		// 8. PR exists — check if base needs retargeting.
-		prNumber = *br.PR
+	}
 
-		info, err := gh.PRView(ctx, prNumber)
-		if err != nil {
-			return fmt.Errorf("viewing PR #%d: %w", prNumber, err)
+	// Check the recorded parent against git; a mismatch means the PR
+	// would target the wrong base. An accepted fix is written right away so
+	// it survives --skip-unchanged.
+	var detected string
+	parentFixed := false
+	if fromGit, _ := cmd.Flags().GetBool("base-branch-from-git"); fromGit {
+		if detected, err = gitParent(ctx, st, branch); err != nil {
+			return err
 		}
-
-		if info.BaseRefName != br.Parent {
-			if err := gh.PREdit(ctx, prNumber, br.Parent); err != nil {
-				return fmt.Errorf("retargeting PR #%d: %w", prNumber, err)
+		if detected != "" && detected != br.Parent {
+			fmt.Fprintf(os.Stderr, "warning: %s is based on %s in git, but frond records %s\n", branch, detected, br.Parent)
+			update, _ := cmd.Flags().GetBool("yes")
+			if !update {
+				if update, err = confirm(fmt.Sprintf("Use %s as the parent of %s?", detected, branch), false); err != nil {
+					return err
+				}
+			}
+			if update {
+				br.Parent = detected
+				st.Branches[branch] = br
+				if err := graphValid(st); err != nil {
+					return fmt.Errorf("cannot use %s as the parent of %s: %w", detected, branch, err)
+				}
+				if err := state.Write(ctx, st); err != nil {
+					return fmt.Errorf("writing state: %w", err)
+				}
+				parentFixed = true
 			}
 		}
 	}
 
+	// 6-8. Push to origin, unless --skip-unchanged and nothing moved
+	// locally, then create the PR or bring the existing one up to date.
+	title, _ := cmd.Flags().GetString("title")
+	body, _ := cmd.Flags().GetString("body")
+	draft, _ := cmd.Flags().GetBool("draft")
+	headRepo, _ := cmd.Flags().GetString("head-repo")
+	skip, _ := cmd.Flags().GetBool("skip-unchanged")
+	out, err := pushBranch(ctx, st, branch, pushOpts{
+		Title:         title,
+		Body:          body,
+		Draft:         draft,
+		HeadRepo:      headRepo,
+		Labels:        labels,
+		Trailers:      trailers,
+		SkipUnchanged: skip,
+		ParentChanged: parentFixed,
+	})
+	if err != nil {
+		return err
+	}
+	br = st.Branches[branch]
+	prNumber, created := out.PR, out.Created
+
+	if out.Skipped {
+		if parentFixed {
+			updateStackComments(ctx, st)
+		}
+		if jsonOut {
+			return printJSON(pushResult{
+				Branch:         branch,
+				PR:             prNumber,
+				Skipped:        true,
+				DetectedParent: detected,
+			})
+		}
+		fmt.Printf("Skipped %s: unchanged since last push. PR #%d\n", branch, prNumber)
+		return nil
+	}
+
 	// 9. Update stack comments on all PRs.
 	updateStackComments(ctx, st)
 
 	// 10. Check for unmet --after deps: warn if any are still tracked.
-	if len(br.After) > 0 {
-		var unmet []string
-		for _, dep := range br.After {
-			if _, tracked := st.Branches[dep]; tracked {
-				unmet = append(unmet, dep)
-			}
-		}
-		if len(unmet) > 0 {
-			fmt.Fprintf(os.Stderr, "warning: unmet dependencies: %s\n", strings.Join(unmet, ", "))
-		}
+	if unmet := unmetDeps(st, br); len(unmet) > 0 {
+		fmt.Fprintf(os.Stderr, "warning: unmet dependencies: %s\n", strings.Join(unmet, ", "))
+	}
+
+	// Open the PR in the browser. Failing to do so only warns: the push
+	// itself succeeded.
+	var prURL string
+	if web, _ := cmd.Flags().GetBool("web"); web {
+		prURL = openPR(ctx, prNumber)
 	}
 
 	// 11. Output.
 	if jsonOut {
 		return printJSON(pushResult{
-			Branch:  branch,
-			PR:      prNumber,
-			Created: created,
+			Branch:         branch,
+			PR:             prNumber,
+			Created:        created,
+			URL:            prURL,
+			Labels:         labels,
+			DetectedParent: detected,
 		})
 	}
 	action := "updated"
@@ -163,3 +433,205 @@ func runPush(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// applyRepoFlag saves --repo as the repo config and points gh at it: later
+// pushes, syncs and stack comments must talk to the same upstream repo. The
+// config is written right away, so it sticks even if nothing gets pushed.
+func applyRepoFlag(cmd *cobra.Command, st *state.State) error {
+	repo, _ := cmd.Flags().GetString("repo")
+	if repo == "" || repo == st.Config[state.ConfigRepo] {
+		return nil
+	}
+	if err := state.ValidateConfig(state.ConfigRepo, repo); err != nil {
+		return fmt.Errorf("--repo: %w", err)
+	}
+	if st.Config == nil {
+		st.Config = map[string]string{}
+	}
+	st.Config[state.ConfigRepo] = repo
+	if err := state.Write(cmd.Context(), st); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	gh.SetRepo(repo)
+	return nil
+}
+
+// pushOpts configures pushBranch. Title, Body, Draft and HeadRepo only
+// matter when a PR is created.
+type pushOpts struct {
+	Title         string // default: the branch name humanized
+	Body          string
+	Draft         bool
+	HeadRepo      string   // fork that holds the branch
+	Labels        []string // added to the PR
+	Trailers      []string // "Key: value" lines from --trailer
+	SkipUnchanged bool     // skip when the tip matches the last recorded push
+	ParentChanged bool     // the parent was just changed, so even a skipped push retargets
+}
+
+// pushOutcome is what pushBranch did.
+type pushOutcome struct {
+	PR      int
+	Created bool
+	Skipped bool
+}
+
+// pushBranch pushes tracked branch name to origin, then creates its PR or
+// retargets the existing one, keeping its trailers and labels current. The
+// pushed tip and PR number are written to state; stack comments are left to
+// the caller. Nothing is checked out.
+func pushBranch(ctx context.Context, st *state.State, name string, opts pushOpts) (pushOutcome, error) {
+	br := st.Branches[name]
+
+	// Trailers go into a new PR's body and are kept current on an
+	// existing one.
+	trailers, replace := opts.Trailers, trailerKeys(opts.Trailers)
+	if on, _ := strconv.ParseBool(st.Config[state.ConfigStackTrailers]); on {
+		trailers = append(stackTrailers(br), trailers...)
+		replace = append(replace, "Frond-Parent", "Frond-After")
+	}
+
+	tip, err := git.CurrentCommit(ctx, name)
+	if err != nil {
+		return pushOutcome{}, fmt.Errorf("resolving %s: %w", name, err)
+	}
+	if opts.SkipUnchanged && br.PR != nil && br.PushedSHA == tip {
+		// Nothing to push, but a changed parent still moves the PR base and
+		// explicit trailers still reach the body.
+		if opts.ParentChanged {
+			if err := retargetPR(ctx, *br.PR, br.Base()); err != nil {
+				return pushOutcome{}, err
+			}
+		}
+		if opts.ParentChanged || len(opts.Trailers) > 0 {
+			if err := syncTrailers(ctx, *br.PR, trailers, replace); err != nil {
+				return pushOutcome{}, err
+			}
+		}
+		return pushOutcome{PR: *br.PR, Skipped: true}, nil
+	}
+
+	if err := git.Push(ctx, name); err != nil {
+		return pushOutcome{}, fmt.Errorf("pushing to origin: %w", err)
+	}
+	br.PushedSHA = tip
+
+	var out pushOutcome
+	if br.PR == nil {
+		title := opts.Title
+		if title == "" {
+			title = humanizeTitle(name)
+		}
+		number, err := gh.PRCreate(ctx, gh.PRCreateOpts{
+			Base:     br.Base(),
+			Head:     name,
+			Title:    title,
+			Body:     appendTrailers(opts.Body, trailers),
+			Draft:    opts.Draft,
+			HeadRepo: opts.HeadRepo,
+			Labels:   opts.Labels,
+		})
+		switch {
+		case errors.Is(err, gh.ErrPRExists):
+			// Opened outside frond: adopt it rather than fail.
+			fmt.Fprintf(os.Stderr, "PR #%d already exists for %s; tracking it\n", number, name)
+			if err := retargetPR(ctx, number, br.Base()); err != nil {
+				return pushOutcome{}, err
+			}
+			if err := syncTrailers(ctx, number, trailers, replace); err != nil {
+				return pushOutcome{}, err
+			}
+		case err != nil:
+			return pushOutcome{}, fmt.Errorf("creating PR: %w", err)
+		default:
+			out.Created = true
+		}
+		br.PR = &number
+	} else {
+		if err := retargetPR(ctx, *br.PR, br.Base()); err != nil {
+			return pushOutcome{}, err
+		}
+		if err := syncTrailers(ctx, *br.PR, trailers, replace); err != nil {
+			return pushOutcome{}, err
+		}
+	}
+	out.PR = *br.PR
+
+	if !out.Created && len(opts.Labels) > 0 {
+		if err := gh.PRAddLabels(ctx, out.PR, opts.Labels); err != nil {
+			return pushOutcome{}, fmt.Errorf("labeling PR #%d: %w", out.PR, err)
+		}
+	}
+
+	// Persist the PR number (if new) and the pushed tip.
+	st.Branches[name] = br
+	if err := state.Write(ctx, st); err != nil {
+		return pushOutcome{}, fmt.Errorf("writing state: %w", err)
+	}
+	return out, nil
+}
+
+// openPR opens PR number n in the browser and returns its URL, or "" with
+// a warning if the URL cannot be determined or opened.
+func openPR(ctx context.Context, n int) string {
+	repoURL, err := repoWebURL(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not determine PR URL: %v\n", err)
+		return ""
+	}
+	url := fmt.Sprintf("%s/pull/%d", repoURL, n)
+	if err := openBrowser(url); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not open %s: %v\n", url, err)
+	}
+	return url
+}
+
+// gitParent returns the trunk or tracked branch that branch actually grew
+// from in git: among candidates whose tip is an ancestor of branch, the one
+// with the fewest commits between it and branch. Ties go to the recorded
+// parent, then alphabetically. Descendants of branch are never candidates.
+// It returns "" when no candidate is an ancestor.
+func gitParent(ctx context.Context, st *state.State, branch string) (string, error) {
+	candidates := st.AllTrunks()
+	for name := range st.Branches {
+		if name != branch && !descendsFrom(st.Branches, name, branch) {
+			candidates = append(candidates, name)
+		}
+	}
+	slices.Sort(candidates)
+
+	recorded := st.Branches[branch].Parent
+	best, bestDist := "", -1
+	for _, cand := range candidates {
+		tip, err := git.RevParse(ctx, cand)
+		if err != nil {
+			continue // e.g. a trunk with no local branch
+		}
+		base, err := git.MergeBase(ctx, cand, branch)
+		if err != nil || base != tip {
+			continue
+		}
+		dist, err := git.CountCommits(ctx, cand, branch)
+		if err != nil {
+			return "", fmt.Errorf("measuring %s from %s: %w", branch, cand, err)
+		}
+		if bestDist < 0 || dist < bestDist || (dist == bestDist && cand == recorded) {
+			best, bestDist = cand, dist
+		}
+	}
+	return best, nil
+}
+
+// retargetPR points PR number at base unless it already targets it.
+func retargetPR(ctx context.Context, number int, base string) error {
+	info, err := gh.PRView(ctx, number)
+	if err != nil {
+		return fmt.Errorf("viewing PR #%d: %w", number, err)
+	}
+	if info.BaseRefName != base {
+		if err := gh.PREdit(ctx, number, base); err != nil {
+			return fmt.Errorf("retargeting PR #%d: %w", number, err)
+		}
+	}
+	return nil
+}
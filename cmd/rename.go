@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tracked branch, keeping its place in the stack",
+	Long: "Rename the git branch <old> to <new> and rewrite frond state to match: children are reparented, " +
+		"--after dependencies and PR base overrides follow the new name, and the branch keeps its PR number. " +
+		"Nothing is pushed: children's PRs move to <new> on their next push or sync, once <new> is on origin. " +
+		"A PR cannot change its head branch, so the renamed branch's own PR stays on <old>; rename the branch on GitHub too, or open a new PR. " +
+		"Use 'frond rename-trunk' for the trunk.",
+	Example: `  # Fix a typo in a branch name
+  frond rename pay/stirpe pay/stripe`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runRename,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	newName := args[1]
+
+	if err := validateBranchName(newName); err != nil {
+		return err
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	if s.IsTrunk(args[0]) {
+		return fmt.Errorf("'%s' is a trunk. Use 'frond rename-trunk' instead", args[0])
+	}
+	oldName, err := resolveTracked(s.Branches, args[0])
+	if err != nil {
+		return err
+	}
+	if _, tracked := s.Branches[newName]; tracked || s.IsTrunk(newName) {
+		return fmt.Errorf("'%s' is already tracked", newName)
+	}
+	exists, err := git.BranchExists(ctx, newName)
+	if err != nil {
+		return fmt.Errorf("checking branch existence: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("branch '%s' already exists", newName)
+	}
+
+	if err := git.RenameBranch(ctx, oldName, newName); err != nil {
+		return fmt.Errorf("renaming branch: %w", err)
+	}
+
+	s.Branches[newName] = s.Branches[oldName]
+	delete(s.Branches, oldName)
+
+	result := renameResult{Old: oldName, New: newName, PR: s.Branches[newName].PR, Reparented: []string{}}
+	for name, b := range s.Branches {
+		if b.Parent == oldName {
+			b.Parent = newName
+			result.Reparented = append(result.Reparented, name)
+		}
+		if b.BaseOverride == oldName {
+			b.BaseOverride = newName
+		}
+		for i, dep := range b.After {
+			if dep == oldName {
+				b.After[i] = newName
+			}
+		}
+		s.Branches[name] = b
+	}
+	for name, m := range s.Merged {
+		if m.Parent == oldName {
+			m.Parent = newName
+			s.Merged[name] = m
+		}
+	}
+	slices.Sort(result.Reparented)
+
+	if err := state.Write(ctx, s); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+
+	if jsonOut {
+		return printJSON(result)
+	}
+	fmt.Printf("Renamed %s → %s", oldName, newName)
+	if len(result.Reparented) > 0 {
+		fmt.Printf(" (%d child branch(es) reparented)", len(result.Reparented))
+	}
+	fmt.Println()
+	if result.PR != nil {
+		fmt.Fprintf(os.Stderr, "warning: PR #%d still has %s as its head branch; rename the branch on GitHub too, or push %s and open a new PR\n", *result.PR, oldName, newName)
+	}
+	if len(result.Reparented) > 0 {
+		fmt.Printf("Child PRs move to %s on their next 'frond push' or 'frond sync', once %s is on origin\n", newName, newName)
+	}
+	return nil
+}
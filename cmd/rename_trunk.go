@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var renameTrunkCmd = &cobra.Command{
+	Use:   "rename-trunk <new-name>",
+	Short: "Point frond at a renamed primary trunk (e.g. master → main)",
+	Long: "Record <new-name> as the primary trunk and rewrite every branch whose parent (or PR base override) was the old trunk. " +
+		"The new branch must exist locally. Only frond's state changes; GitHub retargets open PRs itself when the default branch is renamed. " +
+		"Running it again with the same name is a no-op.",
+	Example: `  # After the remote default branch moved from master to main
+  git fetch origin && git checkout main
+  frond rename-trunk main`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRenameTrunk,
+}
+
+func init() {
+	rootCmd.AddCommand(renameTrunkCmd)
+}
+
+func runRenameTrunk(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	newTrunk := args[0]
+
+	if err := validateBranchName(newTrunk); err != nil {
+		return err
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	oldTrunk := s.Trunk
+	result := renameTrunkResult{Old: oldTrunk, New: newTrunk, Reparented: []string{}}
+	if newTrunk != oldTrunk {
+		if slices.Contains(s.Trunks, newTrunk) {
+			return fmt.Errorf("'%s' is a secondary trunk. Remove it with 'frond trunk remove' first", newTrunk)
+		}
+		if _, tracked := s.Branches[newTrunk]; tracked {
+			return fmt.Errorf("'%s' is tracked. Untrack it before making it the trunk", newTrunk)
+		}
+		exists, err := git.BranchExists(ctx, newTrunk)
+		if err != nil {
+			return fmt.Errorf("checking branch existence: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("branch '%s' does not exist locally", newTrunk)
+		}
+
+		s.Trunk = newTrunk
+		for name, b := range s.Branches {
+			if b.Parent == oldTrunk {
+				b.Parent = newTrunk
+				result.Reparented = append(result.Reparented, name)
+			}
+			if b.BaseOverride == oldTrunk {
+				b.BaseOverride = newTrunk
+			}
+			s.Branches[name] = b
+		}
+		for name, m := range s.Merged {
+			if m.Parent == oldTrunk {
+				m.Parent = newTrunk
+				s.Merged[name] = m
+			}
+		}
+		// The recorded SHA belonged to the old trunk's history.
+		s.LastSyncedTrunk = ""
+		slices.Sort(result.Reparented)
+
+		if err := state.Write(ctx, s); err != nil {
+			return fmt.Errorf("writing state: %w", err)
+		}
+	}
+
+	if jsonOut {
+		return printJSON(result)
+	}
+	if newTrunk == oldTrunk {
+		fmt.Printf("'%s' is already the trunk; nothing to do\n", newTrunk)
+		return nil
+	}
+	fmt.Printf("Trunk renamed: %s → %s (%d branch(es) reparented)\n", oldTrunk, newTrunk, len(result.Reparented))
+	return nil
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var reorderCmd = &cobra.Command{
+	Use:   "reorder <branch> --before <other>",
+	Short: "Move a branch to just before another in the same linear stack",
+	Long: "Reorder a linear run of stacked branches so that <branch> sits directly below <other>. " +
+		"Parents are rewritten, each affected branch has its own commits replayed onto its new parent, and open PRs are retargeted. " +
+		"If any rebase conflicts, every branch and frond's state are put back as they were.",
+	Example: `  # main <- pay/api <- pay/db-schema  becomes  main <- pay/db-schema <- pay/api
+  frond reorder pay/db-schema --before pay/api`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runReorder,
+}
+
+func init() {
+	reorderCmd.Flags().String("before", "", "Branch that <branch> should end up directly below")
+	_ = reorderCmd.MarkFlagRequired("before")
+	rootCmd.AddCommand(reorderCmd)
+}
+
+func runReorder(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	name, err := resolveTracked(s.Branches, args[0])
+	if err != nil {
+		return err
+	}
+	beforeFlag, _ := cmd.Flags().GetString("before")
+	other, err := resolveTracked(s.Branches, beforeFlag)
+	if err != nil {
+		return err
+	}
+	if name == other {
+		return fmt.Errorf("'%s' cannot be placed before itself", name)
+	}
+
+	run, err := linearRun(s.Branches, name, other)
+	if err != nil {
+		return err
+	}
+
+	// The new order: name taken out and put back in front of other.
+	order := slices.DeleteFunc(slices.Clone(run), func(b string) bool { return b == name })
+	at := slices.Index(order, other)
+	order = slices.Insert(order, at, name)
+
+	// The branches hanging off the bottom of the run follow its new bottom.
+	base := s.Branches[run[0]].Parent
+	var tail []string
+	for child, b := range s.Branches {
+		if b.Parent == run[len(run)-1] {
+			tail = append(tail, child)
+		}
+	}
+	slices.Sort(tail)
+
+	newParent := map[string]string{order[0]: base}
+	for i := 1; i < len(order); i++ {
+		newParent[order[i]] = order[i-1]
+	}
+	for _, child := range tail {
+		newParent[child] = order[len(order)-1]
+	}
+
+	next := make(map[string]state.Branch, len(s.Branches))
+	for n, b := range s.Branches {
+		if p, ok := newParent[n]; ok {
+			b.Parent = p
+		}
+		next[n] = b
+	}
+	trial := *s
+	trial.Branches = next
+	if err := graphValid(&trial); err != nil {
+		return fmt.Errorf("reordered stack is invalid: %w", err)
+	}
+
+	result := reorderResult{Branch: name, Before: other, Order: order, Rebased: []string{}}
+	if slices.Equal(order, run) {
+		if jsonOut {
+			return printJSON(result)
+		}
+		fmt.Printf("'%s' is already directly before '%s'\n", name, other)
+		return nil
+	}
+
+	current, err := git.CurrentBranch(ctx)
+	if err != nil {
+		return fmt.Errorf("getting current branch: %w", err)
+	}
+
+	// Record every tip and every old parent's tip before anything moves, so
+	// each branch replays exactly its own commits and a conflict can be undone.
+	affected := append(slices.Clone(order), tail...)
+	tips := make(map[string]string, len(affected))
+	oldBase := make(map[string]string, len(affected))
+	for _, n := range affected {
+		if tips[n], err = git.RevParse(ctx, n); err != nil {
+			return err
+		}
+		if oldBase[n], err = forkPoint(ctx, n, s.Branches[n]); err != nil {
+			return err
+		}
+	}
+
+	rewritten := make(map[string]bool)
+	for _, n := range affected {
+		p := newParent[n]
+		if p == s.Branches[n].Parent && !rewritten[p] {
+			continue
+		}
+		if err := git.RebaseOnto(ctx, n, oldBase[n], p); err != nil {
+			restoreReorder(ctx, result.Rebased, tips, current)
+			var conflictErr *git.RebaseConflictError
+			if errors.As(err, &conflictErr) {
+				return fmt.Errorf("rebasing %s onto %s hit conflicts; nothing was changed", n, p)
+			}
+			return fmt.Errorf("rebasing %s onto %s: %w", n, p, err)
+		}
+		rewritten[n] = true
+		result.Rebased = append(result.Rebased, n)
+		b := next[n]
+		b.BaseSHA, _ = git.RevParse(ctx, p)
+		next[n] = b
+	}
+	if len(result.Rebased) > 0 {
+		if err := git.Checkout(ctx, current); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not restore branch %s: %v\n", current, err)
+		}
+	}
+
+	prev := s.Branches
+	s.Branches = next
+	if err := state.Write(ctx, s); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+
+	prs := false
+	for _, n := range affected {
+		b := s.Branches[n]
+		if b.PR == nil {
+			continue
+		}
+		prs = true
+		if b.BaseOverride == "" && b.Parent != prev[n].Parent {
+			if err := gh.PREdit(ctx, *b.PR, b.Parent); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: could not retarget PR #%d for %s: %v\n", *b.PR, n, err)
+			}
+		}
+	}
+	if prs {
+		updateStackComments(ctx, s)
+	}
+
+	if jsonOut {
+		return printJSON(result)
+	}
+	fmt.Printf("Reordered: %s\n", strings.Join(append([]string{base}, order...), " ← "))
+	return nil
+}
+
+// linearRun returns the branches from the upper of a and b (nearer the
+// trunk) down to the lower, in stack order. It fails unless one descends
+// from the other through branches that each have a single child.
+func linearRun(branches map[string]state.Branch, a, b string) ([]string, error) {
+	upper, lower := a, b
+	if descendsFrom(branches, a, b) {
+		upper, lower = b, a
+	} else if !descendsFrom(branches, b, a) {
+		return nil, fmt.Errorf("'%s' and '%s' are not in the same stack", a, b)
+	}
+
+	children := make(map[string]int)
+	for _, br := range branches {
+		children[br.Parent]++
+	}
+	run := []string{lower}
+	for cur := lower; cur != upper; {
+		cur = branches[cur].Parent
+		if children[cur] > 1 {
+			return nil, fmt.Errorf("'%s' has several children; reorder only works on a linear stack", cur)
+		}
+		run = append(run, cur)
+	}
+	slices.Reverse(run)
+	return run, nil
+}
+
+// restoreReorder puts every branch in rebased back at its recorded tip and
+// checks out current again, undoing a reorder that failed part-way. The
+// failed rebase has already been aborted, leaving its own branch checked
+// out and untouched.
+func restoreReorder(ctx context.Context, rebased []string, tips map[string]string, current string) {
+	for _, n := range rebased {
+		if err := git.ResetBranch(ctx, n, tips[n]); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not restore %s to %s: %v\n", n, tips[n], err)
+		}
+	}
+	if err := git.Checkout(ctx, current); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not restore branch %s: %v\n", current, err)
+	}
+}
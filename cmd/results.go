@@ -1,6 +1,10 @@
 package cmd
 
-import "github.com/nvandessel/frond/internal/dag"
+import (
+	"time"
+
+	"github.com/nvandessel/frond/internal/dag"
+)
 
 // Typed result structs for JSON output. Each command that emits JSON uses
 // a named struct here instead of map[string]any for compile-time safety.
@@ -17,13 +21,21 @@ type trackResult struct {
 	Name   string   `json:"name"`
 	Parent string   `json:"parent"`
 	After  []string `json:"after"`
+	PR     *int     `json:"pr,omitempty"`
 }
 
 // pushResult is the JSON output of "frond push".
 type pushResult struct {
-	Branch  string `json:"branch"`
-	PR      int    `json:"pr"`
-	Created bool   `json:"created"`
+	Branch      string   `json:"branch"`
+	PR          int      `json:"pr"`
+	Created     bool     `json:"created"`
+	Skipped     bool     `json:"skipped,omitempty"`      // --skip-unchanged and tip matched the last push
+	CommentOnly bool     `json:"comment_only,omitempty"` // --update-comment-only
+	URL         string   `json:"url,omitempty"`          // with --web
+	Labels      []string `json:"labels,omitempty"`       // added with --label or --label-from-path
+
+	// DetectedParent is the parent found in git by --base-branch-from-git.
+	DetectedParent string `json:"detected_parent,omitempty"`
 }
 
 // untrackResult is the JSON output of "frond untrack".
@@ -33,14 +45,281 @@ type untrackResult struct {
 	Unblocked  []string `json:"unblocked"`
 }
 
+// deleteResult is the JSON output of "frond delete".
+type deleteResult struct {
+	untrackResult
+	Deleted bool `json:"deleted"` // false when the git branch was kept for an open PR
+}
+
+// untrackAllResult is the JSON output of "frond untrack --all-merged".
+type untrackAllResult struct {
+	Untracked  []string          `json:"untracked"`
+	Reparented map[string]string `json:"reparented"` // child -> new parent
+}
+
 // statusJSONResult is the JSON output of "frond status" (without --fetch PR states).
 type statusJSONResult struct {
-	Trunk    string           `json:"trunk"`
-	Branches []dag.JSONBranch `json:"branches"`
+	Driver          string              `json:"driver"`
+	Trunk           string              `json:"trunk"`
+	Trunks          []string            `json:"trunks,omitempty"`
+	Root            string              `json:"root,omitempty"`              // with --trunk
+	Tree            string              `json:"tree,omitempty"`              // rendered ASCII tree, with --ascii-json
+	TrunkNewCommits *int                `json:"trunk_new_commits,omitempty"` // with --diff-base
+	Branches        []dag.JSONBranch    `json:"branches"`
+	Merged          []mergedResult      `json:"merged,omitempty"`    // with --show-merged
+	Groups          map[string][]string `json:"groups,omitempty"`    // author -> branches, with --group-by-author
+	Checks          *checksSummary      `json:"checks,omitempty"`    // with --pr-checks
+	Untracked       *[]string           `json:"untracked,omitempty"` // with --untracked
 }
 
 // statusFetchResult is the JSON output of "frond status --fetch" with PR states.
 type statusFetchResult struct {
-	Trunk    string         `json:"trunk"`
-	Branches []statusBranch `json:"branches"`
+	Driver          string              `json:"driver"`
+	Trunk           string              `json:"trunk"`
+	Trunks          []string            `json:"trunks,omitempty"`
+	Root            string              `json:"root,omitempty"`              // with --trunk
+	Tree            string              `json:"tree,omitempty"`              // rendered ASCII tree, with --ascii-json
+	TrunkNewCommits *int                `json:"trunk_new_commits,omitempty"` // with --diff-base
+	Branches        []statusBranch      `json:"branches"`
+	Merged          []mergedResult      `json:"merged,omitempty"`    // with --show-merged
+	Groups          map[string][]string `json:"groups,omitempty"`    // author -> branches, with --group-by-author
+	Checks          *checksSummary      `json:"checks,omitempty"`    // with --pr-checks
+	Untracked       *[]string           `json:"untracked,omitempty"` // with --untracked
+}
+
+// checksSummary counts PRs by CI check rollup, for status --pr-checks.
+type checksSummary struct {
+	Total   int `json:"total"`
+	Green   int `json:"green"`
+	Pending int `json:"pending"`
+	Failing int `json:"failing"`
+	None    int `json:"none"` // PRs without any checks
+}
+
+// mergedResult is a merged branch in status --show-merged and prune output.
+type mergedResult struct {
+	Name     string    `json:"name"`
+	Parent   string    `json:"parent"`
+	PR       *int      `json:"pr"`
+	MergedAt time.Time `json:"merged_at"`
+	Author   string    `json:"author,omitempty"` // PR author login, when sync knew it
+}
+
+// pruneResult is the JSON output of "frond prune".
+type pruneResult struct {
+	Pruned []mergedResult `json:"pruned"`
+}
+
+// trunkResult is the JSON output of "frond trunk".
+type trunkResult struct {
+	Trunk  string   `json:"trunk"`
+	Trunks []string `json:"trunks"`
+}
+
+// setBaseResult is the JSON output of "frond set-base".
+type setBaseResult struct {
+	Name string `json:"name"`
+	Base string `json:"base"`          // effective PR base
+	Ref  string `json:"base_override"` // empty after --clear
+}
+
+// rootsResult is the JSON output of "frond graph --roots".
+type rootsResult struct {
+	Roots []string `json:"roots"`
+}
+
+// renameTrunkResult is the JSON output of "frond rename-trunk".
+type renameTrunkResult struct {
+	Old        string   `json:"old"`
+	New        string   `json:"new"`
+	Reparented []string `json:"reparented"` // branches that were rooted at the old trunk
+}
+
+// landResult is the JSON output of "frond land".
+type landResult struct {
+	Branch string   `json:"branch"`
+	PR     int      `json:"pr"`
+	Method string   `json:"method"`
+	Landed []string `json:"landed"` // merged in order, bottom of the stack first
+}
+
+// cloneStackResult is the JSON output of "frond clone-stack".
+type cloneStackResult struct {
+	Path     string   `json:"path"`
+	Head     string   `json:"head"`     // branch the new worktree ends on; empty if none could be checked out
+	Branches []string `json:"branches"` // checked out in order
+	Skipped  []string `json:"skipped"`  // checked out in another worktree
+}
+
+// adoptPRResult is the JSON output of "frond adopt-pr".
+type adoptPRResult struct {
+	Branch   string `json:"branch"`
+	PR       int    `json:"pr"`
+	Previous *int   `json:"previous,omitempty"` // PR recorded before, if any
+}
+
+// abortResult is the JSON output of "frond abort".
+type abortResult struct {
+	Aborted bool `json:"aborted"`
+}
+
+// errorResult is the JSON output of any command that fails under --json.
+type errorResult struct {
+	Error string `json:"error"`
+	Code  int    `json:"code"`
+}
+
+// historyResult is the JSON output of "frond history".
+type historyResult struct {
+	Entries []historyEntry `json:"entries"`
+}
+
+// historyEntry is one state mutation in historyResult.
+type historyEntry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Summary []string  `json:"summary"`
+}
+
+// undoResult is the JSON output of "frond undo".
+type undoResult struct {
+	Undone   *historyEntry `json:"undone,omitempty"`
+	Snapshot string        `json:"snapshot,omitempty"` // set when a snapshot id was restored
+}
+
+// configResult is the JSON output of "frond config".
+type configResult struct {
+	Config map[string]string `json:"config"`
+}
+
+// validateNameResult is the JSON output of "frond validate-name".
+type validateNameResult struct {
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// importResult is the JSON output of "frond import-from-trailers".
+type importResult struct {
+	Imported []string `json:"imported"`
+	Skipped  []string `json:"skipped"` // already tracked
+}
+
+// diffResult is the JSON output of "frond diff".
+type diffResult struct {
+	Branch string `json:"branch"`
+	Parent string `json:"parent"`
+	Diff   string `json:"diff"`
+}
+
+// preflightResult is the JSON output of "frond preflight".
+type preflightResult struct {
+	OK     bool             `json:"ok"` // false if any critical check failed
+	Checks []preflightCheck `json:"checks"`
+}
+
+// preflightCheck is one environment check in preflightResult.
+type preflightCheck struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Critical bool   `json:"critical"`
+	Detail   string `json:"detail"`
+}
+
+// navigateResult is the JSON output of "frond top" and "frond bottom".
+type navigateResult struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// moveResult is the JSON output of "frond move".
+type moveResult struct {
+	Name      string `json:"name"`
+	Parent    string `json:"parent"`
+	OldParent string `json:"old_parent"`
+	Rebased   bool   `json:"rebased"` // false with --keep-commits
+}
+
+// initResult is the JSON output of "frond init".
+type initResult struct {
+	Trunk    string   `json:"trunk"`
+	Created  bool     `json:"created"`  // false when state already existed
+	Imported []string `json:"imported"` // with --import
+}
+
+// renameResult is the JSON output of "frond rename".
+type renameResult struct {
+	Old        string   `json:"old"`
+	New        string   `json:"new"`
+	PR         *int     `json:"pr,omitempty"` // the branch's PR, whose head is still the old name
+	Reparented []string `json:"reparented"`
+}
+
+// reorderResult is the JSON output of "frond reorder".
+type reorderResult struct {
+	Branch  string   `json:"branch"`
+	Before  string   `json:"before"`
+	Order   []string `json:"order"`   // the reordered run, nearest the trunk first
+	Rebased []string `json:"rebased"` // branches whose commits were replayed
+}
+
+// logResult is the JSON output of "frond log".
+type logResult struct {
+	Branches []logBranch `json:"branches"` // in topological order
+}
+
+// logBranch is one branch of logResult.
+type logBranch struct {
+	Name    string      `json:"name"`
+	Parent  string      `json:"parent"`
+	Commits []logCommit `json:"commits"` // newest first
+}
+
+// logCommit is one commit a branch adds on top of its parent.
+type logCommit struct {
+	SHA     string `json:"sha"`
+	Subject string `json:"subject"`
+}
+
+// explainResult is the JSON output of "frond status --explain".
+type explainResult struct {
+	Branch        string         `json:"branch"`
+	Ready         bool           `json:"ready"`
+	BlockedBy     []string       `json:"blocked_by"` // direct --after deps still tracked
+	Parent        string         `json:"parent"`
+	ParentIsTrunk bool           `json:"parent_is_trunk"`
+	ParentPR      *int           `json:"parent_pr,omitempty"`
+	ParentPRState string         `json:"parent_pr_state,omitempty"`
+	Chain         []explainEntry `json:"chain"` // everything that must land first, dependencies first
+}
+
+// explainEntry is one branch of explainResult's chain.
+type explainEntry struct {
+	Name      string   `json:"name"`
+	PR        *int     `json:"pr"`
+	PRState   string   `json:"pr_state,omitempty"`
+	Reason    string   `json:"reason"` // e.g. "parent of pay/api" or "after of pay/e2e"
+	Ready     bool     `json:"ready"`
+	BlockedBy []string `json:"blocked_by"`
+}
+
+// submitResult is the JSON output of "frond submit".
+type submitResult struct {
+	Branches []submitBranch `json:"branches"` // in submission order
+}
+
+// submitBranch is one pushed branch of submitResult.
+type submitBranch struct {
+	Branch  string `json:"branch"`
+	PR      int    `json:"pr"`
+	Created bool   `json:"created"`
+	Skipped bool   `json:"skipped,omitempty"` // --skip-unchanged and tip matched the last push
+}
+
+// moveDepsResult is the JSON output of "frond move-deps".
+type moveDepsResult struct {
+	Dep       string   `json:"dep"`
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	FromAfter []string `json:"from_after"` // from's after list once the dep is gone
+	ToAfter   []string `json:"to_after"`
 }
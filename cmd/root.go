@@ -1,15 +1,23 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/state"
 	"github.com/spf13/cobra"
 )
 
 var (
-	version = "dev"
-	jsonOut bool
+	version       = "dev"
+	jsonOut       bool
+	lockStaleFlag string
 )
 
 var rootCmd = &cobra.Command{
@@ -18,14 +26,73 @@ var rootCmd = &cobra.Command{
 	Version:       version,
 	SilenceUsage:  true,
 	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Label history entries with the subcommand, e.g. "trunk add".
+		state.SetCommand(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "))
+
+		// Fork workflows point every gh call at the upstream repo.
+		gh.SetRepo(state.ConfigValue(cmd.Context(), state.ConfigRepo))
+
+		// Shell completion output must stay clean.
+		if !strings.HasPrefix(cmd.Name(), "__complete") && cmd.Name() != "completion" {
+			pendingUpdate = startUpdateCheck(cmd.Context())
+		}
+
+		// --lock-stale wins over FROND_LOCK_STALE, which wins over the
+		// lock_stale config key read by state.Lock.
+		stale := lockStaleFlag
+		if stale == "" {
+			stale = os.Getenv("FROND_LOCK_STALE")
+		}
+		if stale != "" {
+			return state.ParseLockStale(stale)
+		}
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		reportUpdate(pendingUpdate)
+		pendingUpdate = nil
+	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&jsonOut, "json", false, "Output in JSON format")
+	rootCmd.PersistentFlags().StringVar(&lockStaleFlag, "lock-stale", "", "Treat lockfiles older than this as stale, e.g. 30s (default 5m; also FROND_LOCK_STALE)")
+	rootCmd.PersistentFlags().BoolVar(&noInteractive, "no-interactive", false, "Never prompt; take the safe default instead (also FROND_NO_INTERACTIVE=1)")
 }
 
+// Execute runs the root command under a context that is cancelled on SIGINT
+// or SIGTERM, so in-flight git/gh calls stop and deferred cleanup (notably
+// releasing the state lock) runs before exit. A second signal falls back to
+// the default behavior and kills the process. SIGKILL cannot be caught; a
+// lockfile left behind that way is cleared by stale-lock detection.
+//
+// When --json is set and a command fails, the error is written to stdout as
+// a JSON object so consumers have a single parse path for success and
+// failure; the returned *ExitError carries the exit code without a second,
+// human-readable report.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	if err == nil || !jsonOut {
+		return err
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		// The command already reported its outcome (e.g. sync conflicts).
+		return err
+	}
+	if jsonErr := printJSON(errorResult{Error: err.Error(), Code: 1}); jsonErr != nil {
+		return err
+	}
+	return &ExitError{Code: 1}
 }
 
 // printJSON marshals v to JSON and writes it to stdout.
@@ -34,3 +101,9 @@ func printJSON(v any) error {
 	enc.SetIndent("", "  ")
 	return enc.Encode(v)
 }
+
+// printJSONLine writes v to stdout as a single line of JSON. os.Stdout is
+// unbuffered, so each line reaches a streaming reader as it is written.
+func printJSONLine(v any) error {
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
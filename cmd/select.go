@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// selectFields are the fields a --select predicate can test, with the kind
+// of value each holds. List fields (after, blocked_by) compare by length.
+var selectFields = map[string]string{
+	"name":       "string",
+	"parent":     "string",
+	"pr":         "number", // null when the branch has no PR
+	"seq":        "number",
+	"depth":      "number", // 1 for direct children of the trunk
+	"after":      "number",
+	"blocked_by": "number",
+	"ready":      "bool",
+	"pr_state":   "string", // fetched from GitHub when used
+	"pr_author":  "string",
+}
+
+// selectOps are tried in order, so two-character operators win.
+var selectOps = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// selectPred is one "field op value" test.
+type selectPred struct {
+	field string
+	op    string
+	value any // nil, bool, float64 or string
+}
+
+// parseSelect parses a --select expression: comma-separated predicates of
+// the form field op value, all of which must hold. Values are null, true,
+// false, a number, or a string (optionally quoted).
+func parseSelect(expr string) ([]selectPred, error) {
+	var preds []selectPred
+	for term := range strings.SplitSeq(expr, ",") {
+		term = strings.TrimSpace(term)
+		i := strings.IndexAny(term, "=!<>")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid --select term %q: want field op value", term)
+		}
+		p := selectPred{field: strings.TrimSpace(term[:i])}
+		for _, op := range selectOps {
+			if strings.HasPrefix(term[i:], op) {
+				p.op = op
+				break
+			}
+		}
+		if p.op == "" {
+			return nil, fmt.Errorf("invalid --select term %q: operators are == != < <= > >=", term)
+		}
+		kind, ok := selectFields[p.field]
+		if !ok {
+			return nil, fmt.Errorf("unknown --select field %q", p.field)
+		}
+		raw := strings.TrimSpace(term[i+len(p.op):])
+		switch {
+		case raw == "null":
+			if kind != "number" || (p.op != "==" && p.op != "!=") {
+				return nil, fmt.Errorf("invalid --select term %q: only pr can be compared to null, with == or !=", term)
+			}
+		case kind == "bool":
+			b, err := strconv.ParseBool(raw)
+			if err != nil || (p.op != "==" && p.op != "!=") {
+				return nil, fmt.Errorf("invalid --select term %q: %s takes == or != with true or false", term, p.field)
+			}
+			p.value = b
+		case kind == "number":
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --select term %q: %s is a number", term, p.field)
+			}
+			p.value = n
+		default:
+			p.value = strings.Trim(raw, `"'`)
+		}
+		preds = append(preds, p)
+	}
+	return preds, nil
+}
+
+// needsPRInfo reports whether any predicate tests a field that is only
+// known after asking GitHub.
+func needsPRInfo(preds []selectPred) bool {
+	for _, p := range preds {
+		if p.field == "pr_state" || p.field == "pr_author" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSelect reports whether rec satisfies every predicate. rec maps each
+// selectFields name to nil, a bool, a float64 or a string.
+func matchSelect(preds []selectPred, rec map[string]any) bool {
+	for _, p := range preds {
+		if !p.match(rec[p.field]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p selectPred) match(v any) bool {
+	if p.value == nil {
+		// parseSelect only allows == and != against null.
+		return (v == nil) == (p.op == "==")
+	}
+	if v == nil {
+		// A missing PR is unequal to, and not ordered against, any number.
+		return p.op == "!="
+	}
+	var c int
+	switch want := p.value.(type) {
+	case bool:
+		if v.(bool) == want {
+			c = 0
+		} else {
+			c = 1
+		}
+	case float64:
+		c = cmp.Compare(v.(float64), want)
+	case string:
+		c = strings.Compare(v.(string), want)
+	}
+	switch p.op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var setBaseCmd = &cobra.Command{
+	Use:   "set-base <branch> [<ref>]",
+	Short: "Pin the PR base of a branch, independent of its parent",
+	Long: "Make push and sync target <ref> as the PR base for <branch> instead of its parent. " +
+		"<ref> must exist locally or on origin. The branch stays where it is in the tree; only the PR base changes. Use --clear to go back to the parent.",
+	Example: `  # Always open the hotfix PR against the release branch
+  frond set-base fix/login release/1.4
+
+  # Target the parent again
+  frond set-base fix/login --clear`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runSetBase,
+}
+
+func init() {
+	setBaseCmd.Flags().Bool("clear", false, "Remove the override so the PR targets the parent again")
+	rootCmd.AddCommand(setBaseCmd)
+}
+
+func runSetBase(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	clearFlag, _ := cmd.Flags().GetBool("clear")
+	if clearFlag == (len(args) == 2) {
+		return fmt.Errorf("give either a <ref> or --clear")
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	name, err := resolveTracked(s.Branches, args[0])
+	if err != nil {
+		return err
+	}
+	b := s.Branches[name]
+
+	ref := ""
+	if !clearFlag {
+		ref = args[1]
+		if ref == name {
+			return fmt.Errorf("'%s' cannot be its own base", name)
+		}
+		if err := validateBranchName(ref); err != nil {
+			return err
+		}
+		// A PR can only target a branch GitHub knows about; a local-only
+		// ref is accepted since the next push may publish it.
+		exists, err := git.BranchExists(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("checking branch existence: %w", err)
+		}
+		if !exists {
+			if exists, err = git.RemoteBranchExists(ctx, "origin", ref); err != nil {
+				return fmt.Errorf("checking origin for '%s': %w", ref, err)
+			}
+		}
+		if !exists {
+			return fmt.Errorf("branch '%s' does not exist locally or on origin", ref)
+		}
+	}
+
+	b.BaseOverride = ref
+	s.Branches[name] = b
+	if err := state.Write(ctx, s); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+
+	if b.PR != nil {
+		if err := gh.PREdit(ctx, *b.PR, b.Base()); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not retarget PR #%d for %s: %v\n", *b.PR, name, err)
+		}
+	}
+
+	if jsonOut {
+		return printJSON(setBaseResult{
+			Name: name,
+			Base: b.Base(),
+			Ref:  ref,
+		})
+	}
+	if clearFlag {
+		fmt.Printf("'%s' PRs now target its parent (%s)\n", name, b.Parent)
+	} else {
+		fmt.Printf("'%s' PRs now target %s (parent: %s)\n", name, ref, b.Parent)
+	}
+	return nil
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/nvandessel/frond/internal/dag"
 	"github.com/nvandessel/frond/internal/gh"
-	"github.com/nvandessel/frond/internal/git"
 	"github.com/nvandessel/frond/internal/state"
 )
 
@@ -33,7 +32,7 @@ func updateStackComments(ctx context.Context, st *state.State) {
 		return
 	}
 
-	repoURL, err := git.RepoWebURL(ctx)
+	repoURL, err := repoWebURL(ctx)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "warning: could not determine repo URL: %v\n", err)
 	}
@@ -55,7 +54,7 @@ func updateStackComments(ctx context.Context, st *state.State) {
 			continue
 		}
 
-		body := dag.RenderStackComment(st.Trunk, dagBranches, prNumbers, readinessMap, name, repoURL)
+		body := dag.RenderStackComment(dag.RootOf(dagBranches, name), dagBranches, prNumbers, readinessMap, name, repoURL)
 		if err := upsertComment(ctx, *b.PR, body); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: stack comment on PR #%d: %v\n", *b.PR, err)
 		}
@@ -66,7 +65,7 @@ func updateStackComments(ctx context.Context, st *state.State) {
 // it as merged and displaying the remaining stack. Called from sync after
 // merges are processed but before rebasing.
 func updateMergedComments(ctx context.Context, st *state.State, mergedData map[string]state.Branch) {
-	repoURL, err := git.RepoWebURL(ctx)
+	repoURL, err := repoWebURL(ctx)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "warning: could not determine repo URL: %v\n", err)
 	}
@@ -87,7 +86,7 @@ func updateMergedComments(ctx context.Context, st *state.State, mergedData map[s
 		if b.PR == nil {
 			continue
 		}
-		body := dag.RenderMergedStackComment(st.Trunk, dagBranches, prNumbers, readinessMap, name, repoURL)
+		body := dag.RenderMergedStackComment(dag.RootOf(dagBranches, b.Parent), dagBranches, prNumbers, readinessMap, name, repoURL)
 		if err := upsertComment(ctx, *b.PR, body); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: merged stack comment on PR #%d: %v\n", *b.PR, err)
 		}
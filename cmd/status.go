@@ -4,23 +4,79 @@ import (
 	"cmp"
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/nvandessel/frond/internal/dag"
 	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/git"
 	"github.com/nvandessel/frond/internal/state"
 	"github.com/spf13/cobra"
 )
 
-// statusBranch wraps dag.JSONBranch with an optional PR state field
-// for --fetch output.
+// statusBranch wraps dag.JSONBranch with optional PR state and author
+// fields for --fetch output.
 type statusBranch struct {
 	dag.JSONBranch
-	PRState string `json:"pr_state,omitempty"`
+	PRState   string `json:"pr_state,omitempty"`
+	PRAuthor  string `json:"pr_author,omitempty"`
+	BaseDrift string `json:"base_drift,omitempty"` // PR base on GitHub, when it differs from parent
+	Stale     *bool  `json:"stale,omitempty"`      // with --stale
+
+	SubtreeCommits *int   `json:"subtree_commits,omitempty"` // with --count-commits
+	PRChecks       string `json:"pr_checks,omitempty"`       // CI rollup: PASSING, PENDING or FAILING
+
+	ChangedSinceSync *bool `json:"changed_since_sync,omitempty"` // with --since-sync
+
+	// Ready and BlockedBy shadow the embedded fields so --no-readiness can
+	// leave them out.
+	Ready     *bool     `json:"ready,omitempty"`
+	BlockedBy *[]string `json:"blocked_by,omitempty"`
 }
 
-var fetchFlag bool
+// driverName identifies how frond manages branches, reported in status JSON
+// so tools can adapt. frond drives git and gh directly; there is no other
+// backend yet.
+const driverName = "native"
+
+var (
+	fetchFlag           bool
+	highlightFlag       string
+	highlightCurrent    bool
+	mineFlag            bool
+	includeUnpushedFlag bool
+	diffBaseFlag        bool
+	unpushedFlag        bool
+	noPRFlag            bool
+	noReadinessFlag     bool
+	statusTrunkFlag     string
+	asciiJSONFlag       bool
+	watchFlag           bool
+	jsonStreamFlag      bool
+	statusIntervalFlag  time.Duration
+	collapseFlag        bool
+	maxDepthFlag        int
+	statusOrderFlag     string
+	showMergedFlag      bool
+	groupByAuthorFlag   bool
+	checkBaseDriftFlag  bool
+	onlyFlag            string
+	legendFlag          bool
+	legendJSONFlag      bool
+	selectFlag          string
+	staleFlag           string
+	countCommitsFlag    bool
+	prChecksFlag        bool
+	sinceSyncFlag       bool
+	explainFlag         string
+	budgetFlag          int
+	untrackedFlag       bool
+)
 
 var statusCmd = &cobra.Command{
 	Use:   "status",
@@ -32,6 +88,80 @@ var statusCmd = &cobra.Command{
   # Include live PR states from GitHub
   frond status --fetch
 
+  # Structure and readiness only, no PR noise
+  frond status --no-pr
+
+  # Bird's-eye view: top-level branches only
+  frond status --trunk-only-children
+
+  # Keep tall stacks short
+  frond status --max-depth 2
+
+  # List siblings oldest first instead of alphabetically
+  frond status --branch-order created
+
+  # Mark a branch with 👈 in the tree
+  frond status --highlight pay/stripe-client
+
+  # Where am I?
+  frond status --highlight-current
+
+  # Keep merged branches in view until 'frond prune --merged'
+  frond status --show-merged
+
+  # How far has trunk moved since the last sync?
+  frond status --diff-base
+
+  # Just a handful of branches, with their ancestors for context
+  frond status --only pay/api,pay/e2e
+
+  # Only your PRs in a shared stack
+  frond status --mine --include-unpushed
+
+  # Flag PRs whose GitHub base differs from the recorded parent
+  frond status --check-base-drift
+
+  # Who owns what on a shared stack
+  frond status --group-by-author
+
+  # Branches with local commits that still need a push
+  frond status --unpushed
+
+  # Filter with a tiny predicate language (comma = and)
+  frond status --json --select 'ready==false'
+  frond status --select 'pr==null,depth>2'
+
+  # Flag branches with no commits in the last 30 days
+  frond status --stale 30d
+
+  # Huge stack: fetch only the 10 PRs nearest the current branch
+  frond status --fetch --budget 10
+
+  # One-line CI rollup across the stack's PRs
+  frond status --pr-checks
+
+  # Mark (*) branches to re-push or re-sync
+  frond status --since-sync
+
+  # Estimate review effort: commits in each branch's subtree
+  frond status --count-commits
+
+  # Live view for a review session: redraw with PR states every 10s until Ctrl-C
+  frond status --watch
+
+  # Feed a dashboard: a JSON line with PR states every 30s
+  frond status --watch --json --interval 30s
+
+  # Explain the markers, for humans or tools
+  frond status --legend
+  frond status --legend-json
+
+  # Onboarding: which local branches does frond not know about?
+  frond status --untracked
+
+  # Why is this branch blocked, and what has to land first?
+  frond status --explain pay/e2e
+
   # JSON output for scripting
   frond status --json`,
 	RunE: runStatus,
@@ -39,96 +169,929 @@ var statusCmd = &cobra.Command{
 
 func init() {
 	statusCmd.Flags().BoolVar(&fetchFlag, "fetch", false, "Fetch live PR states from GitHub (slower)")
+	statusCmd.Flags().StringVar(&highlightFlag, "highlight", "", "Mark a branch with 👈 in the tree")
+	statusCmd.Flags().BoolVar(&highlightCurrent, "highlight-current", false, "Mark the checked-out branch with 👈 (an explicit --highlight wins)")
+	statusCmd.Flags().StringVar(&onlyFlag, "only", "", "Comma-separated branches to show (ancestors are drawn for context)")
+	statusCmd.Flags().BoolVar(&mineFlag, "mine", false, "Only show branches whose PR author is the current gh user (implies --fetch)")
+	statusCmd.Flags().BoolVar(&groupByAuthorFlag, "group-by-author", false, "List branches grouped by PR author instead of as a tree (implies --fetch)")
+	statusCmd.Flags().BoolVar(&checkBaseDriftFlag, "check-base-drift", false, "Annotate PRs whose GitHub base differs from the recorded parent (implies --fetch)")
+	statusCmd.Flags().BoolVar(&includeUnpushedFlag, "include-unpushed", false, "With --mine, also show branches without a PR")
+	statusCmd.Flags().BoolVar(&unpushedFlag, "unpushed", false, "Only show branches with local commits not yet pushed")
+	statusCmd.Flags().BoolVar(&noPRFlag, "no-pr", false, "Hide PR numbers and not-pushed markers; show only structure and readiness")
+	statusCmd.Flags().BoolVar(&noReadinessFlag, "no-readiness", false, "Hide [ready]/[blocked] annotations (and ready/blocked_by in JSON)")
+	statusCmd.Flags().StringVar(&statusTrunkFlag, "trunk", "", "Draw the tree rooted at this branch instead of the trunks (display only)")
+	statusCmd.Flags().BoolVar(&collapseFlag, "trunk-only-children", false, "Show only direct children of each trunk, with a count of hidden descendants")
+	statusCmd.Flags().IntVar(&maxDepthFlag, "max-depth", 0, "Hide branches deeper than n levels below trunk (human output only; 0 = no limit)")
+	statusCmd.Flags().StringVar(&statusOrderFlag, "branch-order", "alpha", "Order of sibling branches in the tree: alpha or created")
+	statusCmd.Flags().BoolVar(&showMergedFlag, "show-merged", false, "Include branches whose PRs merged, tagged [merged]")
+	statusCmd.Flags().StringVar(&selectFlag, "select", "", "Show only branches matching comma-separated predicates such as ready==false, pr==null, depth>2")
+	statusCmd.Flags().StringVar(&staleFlag, "stale", "", "Flag branches whose last commit is older than this (e.g. 30d, 2w, 12h)")
+	statusCmd.Flags().BoolVar(&prChecksFlag, "pr-checks", false, "Fetch PRs and summarize their CI checks (implies --fetch)")
+	statusCmd.Flags().BoolVar(&sinceSyncFlag, "since-sync", false, "Mark (*) branches whose tip moved since the last push, or whose trunk moved since the last sync")
+	statusCmd.Flags().BoolVar(&countCommitsFlag, "count-commits", false, "Annotate each branch with the commits it and its descendants add (one git call per branch)")
+	statusCmd.Flags().BoolVar(&asciiJSONFlag, "ascii-json", false, "Print JSON that also carries the rendered tree as a string (implies --json)")
+	statusCmd.Flags().BoolVar(&watchFlag, "watch", false, "Redraw the tree (or, with --json, print a JSON line) with fresh PR states every --interval until interrupted")
+	statusCmd.Flags().BoolVar(&jsonStreamFlag, "json-stream", false, "Print each report as one line of JSON with PR states (NDJSON; implies --fetch)")
+	statusCmd.Flags().DurationVar(&statusIntervalFlag, "interval", 10*time.Second, "Time between reports with --watch")
+	statusCmd.Flags().BoolVar(&legendFlag, "legend", false, "Explain the tree's markers below the tree")
+	statusCmd.Flags().BoolVar(&legendJSONFlag, "legend-json", false, "Print the markers as a JSON object and exit")
+	statusCmd.Flags().IntVar(&budgetFlag, "budget", 0, "Fetch at most n PR states, nearest the current branch first; the rest show as not fetched (0 = no limit)")
+	statusCmd.Flags().BoolVar(&untrackedFlag, "untracked", false, "Also list local git branches that frond does not track")
+	statusCmd.Flags().StringVar(&explainFlag, "explain", "", "Explain why a branch is blocked: its --after blockers, parent PR, and what must land first")
+	statusCmd.Flags().BoolVar(&diffBaseFlag, "diff-base", false, "Show how many trunk commits arrived since the last sync")
 	rootCmd.AddCommand(statusCmd)
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
+	// The legend describes frond itself, not this repo; no state is needed.
+	if legendJSONFlag {
+		legend := make(map[string]string, len(dag.Legend))
+		for _, e := range dag.Legend {
+			legend[e.Key] = e.Symbol
+		}
+		return printJSON(legend)
+	}
+
+	// --watch runs until Execute's signal context is cancelled by Ctrl-C or
+	// SIGTERM. On a terminal the screen is redrawn; redirected output gets
+	// frames separated by a blank line, and JSON consumers one line per
+	// report.
+	if watchFlag {
+		if statusIntervalFlag <= 0 {
+			return fmt.Errorf("--interval must be positive, got %s", statusIntervalFlag)
+		}
+		redraw := stdoutIsTerminal()
+		for frame := 0; ; frame++ {
+			switch {
+			case streamJSON():
+			case redraw:
+				fmt.Print(clearScreen)
+			case frame > 0:
+				fmt.Println()
+			}
+			if err := statusOnce(ctx); err != nil {
+				if ctx.Err() != nil {
+					return nil // interrupted mid-snapshot
+				}
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(statusIntervalFlag):
+			}
+		}
+	}
+	return statusOnce(ctx)
+}
+
+// clearScreen moves the cursor home and clears the terminal between
+// --watch frames.
+const clearScreen = "\033[H\033[2J"
+
+// stdoutIsTerminal reports whether stdout is a terminal; tests override it.
+var stdoutIsTerminal = func() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// streamJSON reports whether reports are printed as NDJSON lines: with
+// --json-stream, or --watch with --json.
+func streamJSON() bool {
+	return jsonStreamFlag || (watchFlag && jsonOut)
+}
+
+// statusOnce reads state and prints one status report.
+func statusOnce(ctx context.Context) error {
 	// 1. Read state (do NOT create state if missing).
 	s, err := state.Read(ctx)
 	if err != nil {
 		return fmt.Errorf("reading state: %w", err)
 	}
 
-	// 2. Convert state.Branch -> dag.BranchInfo for all branches.
+	// Warn (do not fail) when a rebase is stopped mid-way.
+	if inProgress, err := git.RebaseInProgress(ctx); err == nil && inProgress {
+		fmt.Fprintln(os.Stderr, "warning: a rebase is in progress. Finish it with 'git rebase --continue' or run 'frond abort'")
+	}
+	// Two branches on one PR make sync retarget it back and forth.
+	if err := uniquePRs(s); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
+	if explainFlag != "" {
+		name, err := resolveTracked(s.Branches, explainFlag)
+		if err != nil {
+			return err
+		}
+		return explainStatus(ctx, s, name)
+	}
+
+	// 2-4. Convert state to dag form, collect PR numbers, compute readiness.
+	v := newStatusView(s)
+	v.diffBase = diffBaseFlag
+	v.opts.HidePR = noPRFlag
+	v.noReadiness = noReadinessFlag
+	v.opts.Collapse = collapseFlag
+	v.opts.MaxDepth = maxDepthFlag
+	if v.opts.Order, err = branchOrder(statusOrderFlag, v.branches); err != nil {
+		return err
+	}
+	if showMergedFlag {
+		v.merged = s.Merged
+	}
+
+	v.groupByAuthor = groupByAuthorFlag
+
+	var preds []selectPred
+	if selectFlag != "" {
+		if preds, err = parseSelect(selectFlag); err != nil {
+			return err
+		}
+	}
+
+	// 5. If --fetch (or a view that needs PR authors), get live PR states.
+	if budgetFlag < 0 {
+		return fmt.Errorf("--budget must be non-negative, got %d", budgetFlag)
+	}
+	if fetchFlag || watchFlag || streamJSON() || mineFlag || groupByAuthorFlag || checkBaseDriftFlag || prChecksFlag || needsPRInfo(preds) {
+		if budgetFlag > 0 {
+			from := s.Trunk
+			if current, err := git.CurrentBranch(ctx); err == nil {
+				if _, tracked := s.Branches[current]; tracked || s.IsTrunk(current) {
+					from = current
+				}
+			}
+			v.prStates = fetchPRStatesBudget(ctx, v.branches, v.prNumbers, from, budgetFlag)
+		} else {
+			v.prStates = fetchPRStates(ctx, v.prNumbers)
+		}
+	}
+	v.opts.Notes = make(map[string]string)
+	for name, b := range s.Branches {
+		if b.BaseOverride != "" {
+			v.addNote(name, fmt.Sprintf("(base: %s)", b.BaseOverride))
+		}
+	}
+	if checkBaseDriftFlag {
+		v.baseDrift = make(map[string]string)
+		for name, info := range v.prStates {
+			if info.BaseRefName != "" && info.BaseRefName != s.Branches[name].Base() {
+				v.baseDrift[name] = info.BaseRefName
+				v.addNote(name, fmt.Sprintf("(base drift: %s)", info.BaseRefName))
+			}
+		}
+	}
+	if staleFlag != "" {
+		maxAge, err := parseAge(staleFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --stale %q: %w", staleFlag, err)
+		}
+		v.stale = make(map[string]bool, len(s.Branches))
+		now := time.Now()
+		for name := range s.Branches {
+			last, err := git.LastCommitTime(ctx, name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: could not read last commit of %s: %v\n", name, err)
+				continue
+			}
+			if age := now.Sub(last); age > maxAge {
+				v.stale[name] = true
+				v.addNote(name, fmt.Sprintf("(stale: %dd)", int(age.Hours()/24)))
+			}
+		}
+	}
+
+	if sinceSyncFlag {
+		v.sinceSync = changedSinceSync(ctx, s, v.branches)
+		for name, changed := range v.sinceSync {
+			if changed {
+				v.addNote(name, "*")
+			}
+		}
+	}
+	if untrackedFlag {
+		untracked, err := untrackedBranches(ctx, s)
+		if err != nil {
+			return err
+		}
+		v.untracked = &untracked
+	}
+	if countCommitsFlag {
+		v.subtreeCommits = subtreeCommits(ctx, v.branches)
+		for name, n := range v.subtreeCommits {
+			if n == 1 {
+				v.addNote(name, "(1 commit)")
+			} else {
+				v.addNote(name, fmt.Sprintf("(%d commits)", n))
+			}
+		}
+	}
+
+	// Filters select which branches are shown; readiness is always computed
+	// over the full graph so hidden branches still block visible ones.
+	if mineFlag {
+		me, err := gh.CurrentUser(ctx)
+		if err != nil {
+			return fmt.Errorf("determining gh user for --mine: %w", err)
+		}
+		v.filter(func(name string) bool {
+			if v.prNumbers[name] == nil {
+				return includeUnpushedFlag
+			}
+			return v.prStates[name].Author == me
+		})
+	}
+	if onlyFlag != "" {
+		only, err := resolveBranchList(s.Branches, "--only", onlyFlag)
+		if err != nil {
+			return err
+		}
+		v.filter(func(name string) bool { return only[name] })
+	}
+	if preds != nil {
+		v.filter(func(name string) bool { return matchSelect(preds, v.selectRecord(name)) })
+	}
+	if statusTrunkFlag != "" {
+		root := statusTrunkFlag
+		if !s.IsTrunk(root) {
+			if root, err = resolveTracked(s.Branches, statusTrunkFlag); err != nil {
+				return err
+			}
+		}
+		v.filter(func(name string) bool { return descendsFrom(s.Branches, name, root) })
+		v.root = root
+	}
+	if unpushedFlag {
+		v.filter(func(name string) bool {
+			return hasUnpushedCommits(ctx, name, s.Branches[name])
+		})
+	}
+
+	if diffBaseFlag && s.LastSyncedTrunk != "" {
+		n, err := git.CountCommits(ctx, s.LastSyncedTrunk, s.Trunk)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not count new %s commits: %v\n", s.Trunk, err)
+		} else {
+			v.trunkNewCommits = &n
+		}
+	}
+
+	if prChecksFlag {
+		v.checks = v.checksSummary()
+	}
+
+	// 6. Output.
+	if jsonOut || asciiJSONFlag || jsonStreamFlag {
+		v.withTree = asciiJSONFlag
+		v.stream = streamJSON()
+		return outputJSON(v)
+	}
+	if maxDepthFlag < 0 {
+		return fmt.Errorf("--max-depth must be non-negative, got %d", maxDepthFlag)
+	}
+	if highlightFlag != "" {
+		v.opts.Highlight, err = resolveTracked(s.Branches, highlightFlag)
+		if err != nil {
+			return err
+		}
+	} else if highlightCurrent {
+		// On a trunk or an untracked branch there is nothing to mark.
+		if current, err := git.CurrentBranch(ctx); err == nil {
+			if _, tracked := s.Branches[current]; tracked {
+				v.opts.Highlight = current
+			}
+		}
+	}
+	return outputHuman(v)
+}
+
+// statusView is everything status renders, computed once from state.
+type statusView struct {
+	trunk     string
+	trunks    []string // secondary trunks
+	branches  map[string]dag.BranchInfo
+	prNumbers map[string]*int
+	readiness map[string]dag.ReadinessInfo
+	prStates  map[string]gh.PRInfo
+	visible   map[string]bool // nil shows every branch
+	opts      dag.RenderOptions
+
+	merged         map[string]state.MergedBranch // with --show-merged
+	groupByAuthor  bool                          // --group-by-author
+	baseDrift      map[string]string             // branch -> actual PR base, with --check-base-drift
+	stale          map[string]bool               // branches with no recent commits; nil without --stale
+	subtreeCommits map[string]int                // with --count-commits
+	checks         *checksSummary                // with --pr-checks
+	sinceSync      map[string]bool               // with --since-sync
+	untracked      *[]string                     // git branches frond does not track, with --untracked
+
+	stream          bool   // --json-stream: one compact JSON line per report
+	withTree        bool   // --ascii-json: add the rendered tree to the JSON
+	root            string // --trunk: the only tree drawn; empty draws every trunk
+	noReadiness     bool   // --no-readiness: readiness is still computed for --select, just not shown
+	diffBase        bool   // --diff-base was requested
+	trunkNewCommits *int   // trunk commits since the last sync; nil if unknown
+}
+
+// newStatusView builds the dag view of s with readiness computed.
+func newStatusView(s *state.State) *statusView {
 	branches := stateToDag(s.Branches)
 
-	// 3. Build prNumbers map from state branches' PR fields.
 	prNumbers := make(map[string]*int, len(s.Branches))
 	for name, b := range s.Branches {
 		prNumbers[name] = b.PR
 	}
 
-	// 4. Compute readiness.
 	readinessSlice := dag.ComputeReadiness(branches)
 	readinessMap := make(map[string]dag.ReadinessInfo, len(readinessSlice))
 	for _, ri := range readinessSlice {
 		readinessMap[ri.Name] = ri
 	}
 
-	// 5. If --fetch, get live PR states from GitHub.
-	prStates := make(map[string]string)
-	if fetchFlag {
-		prStates = fetchPRStates(ctx, prNumbers)
+	return &statusView{
+		trunk:     s.Trunk,
+		trunks:    s.Trunks,
+		branches:  branches,
+		prNumbers: prNumbers,
+		readiness: readinessMap,
+		prStates:  make(map[string]gh.PRInfo),
 	}
+}
 
-	// 6. Output.
-	if jsonOut {
-		return outputJSON(s.Trunk, branches, prNumbers, prStates)
+// filter narrows the visible branches to those for which keep is true.
+// Successive filters intersect.
+func (v *statusView) filter(keep func(name string) bool) {
+	next := make(map[string]bool)
+	for name := range v.branches {
+		if v.isVisible(name) && keep(name) {
+			next[name] = true
+		}
 	}
-	return outputHuman(s.Trunk, branches, prNumbers, readinessMap, prStates)
+	v.visible = next
 }
 
-// fetchPRStates calls gh.PRView for each branch that has a PR number.
-// On individual failures it warns to stderr and continues.
-func fetchPRStates(ctx context.Context, prNumbers map[string]*int) map[string]string {
-	states := make(map[string]string)
-	for name, pr := range prNumbers {
-		if pr == nil {
+// changedSinceSync reports, for every branch, whether it needs attention
+// since the last sync: its tip differs from what was last pushed, or it is
+// rooted on the primary trunk and the trunk has moved past LastSyncedTrunk.
+func changedSinceSync(ctx context.Context, s *state.State, branches map[string]dag.BranchInfo) map[string]bool {
+	trunkMoved := false
+	if s.LastSyncedTrunk != "" {
+		if sha, err := git.RevParse(ctx, s.Trunk); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not resolve %s: %v\n", s.Trunk, err)
+		} else {
+			trunkMoved = sha != s.LastSyncedTrunk
+		}
+	}
+	changed := make(map[string]bool, len(s.Branches))
+	for name, b := range s.Branches {
+		changed[name] = hasUnpushedCommits(ctx, name, b) ||
+			(trunkMoved && dag.RootOf(branches, name) == s.Trunk)
+	}
+	return changed
+}
+
+// checksSummary counts the visible branches' PRs by CI check rollup. Each
+// PR counts once; PRs that could not be fetched are left out.
+func (v *statusView) checksSummary() *checksSummary {
+	sum := &checksSummary{}
+	seen := make(map[int]bool)
+	for name, info := range v.prStates {
+		if !v.isVisible(name) || seen[info.Number] || info.State == prStateNotFetched {
 			continue
 		}
-		info, err := gh.PRView(ctx, *pr)
+		seen[info.Number] = true
+		sum.Total++
+		switch info.Checks {
+		case gh.ChecksPassing:
+			sum.Green++
+		case gh.ChecksPending:
+			sum.Pending++
+		case gh.ChecksFailing:
+			sum.Failing++
+		default:
+			sum.None++
+		}
+	}
+	return sum
+}
+
+// String renders the rollup line, e.g. "7 PRs: 4 green, 2 pending, 1 failing".
+func (c *checksSummary) String() string {
+	line := fmt.Sprintf("%d PRs: %d green, %d pending, %d failing", c.Total, c.Green, c.Pending, c.Failing)
+	if c.None > 0 {
+		line += fmt.Sprintf(", %d without checks", c.None)
+	}
+	return line
+}
+
+// subtreeCommits returns, for every branch, the number of commits on it
+// (relative to its parent) plus those of all its descendants. A branch whose
+// count cannot be read contributes zero, with a warning.
+func subtreeCommits(ctx context.Context, branches map[string]dag.BranchInfo) map[string]int {
+	children := make(map[string][]string)
+	for name, info := range branches {
+		children[info.Parent] = append(children[info.Parent], name)
+	}
+	totals := make(map[string]int, len(branches))
+	var total func(name string) int
+	total = func(name string) int {
+		if n, done := totals[name]; done {
+			return n
+		}
+		totals[name] = 0 // guards against parent cycles
+		n, err := git.CountCommits(ctx, branches[name].Parent, name)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to fetch PR #%d for %s: %v\n", *pr, name, err)
+			fmt.Fprintf(os.Stderr, "warning: could not count commits on %s: %v\n", name, err)
+			n = 0
+		}
+		for _, child := range children[name] {
+			n += total(child)
+		}
+		totals[name] = n
+		return n
+	}
+	for name := range branches {
+		total(name)
+	}
+	return totals
+}
+
+// addNote appends an annotation to a branch's line in the tree.
+func (v *statusView) addNote(name, note string) {
+	if prev := v.opts.Notes[name]; prev != "" {
+		note = prev + " " + note
+	}
+	v.opts.Notes[name] = note
+}
+
+// parseAge parses a --stale duration. On top of time.ParseDuration units it
+// accepts whole days ("30d") and weeks ("2w").
+func parseAge(s string) (time.Duration, error) {
+	unit := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if n := len(s); n > 1 {
+		if u, ok := unit[s[n-1]]; ok {
+			count, err := strconv.Atoi(s[:n-1])
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("want a whole number before %q", s[n-1:])
+			}
+			return time.Duration(count) * u, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return d, nil
+}
+
+// selectRecord returns the --select fields of a branch; see selectFields.
+func (v *statusView) selectRecord(name string) map[string]any {
+	info := v.branches[name]
+	depth := 1
+	for p := info.Parent; depth <= len(v.branches); depth++ {
+		parent, ok := v.branches[p]
+		if !ok {
+			break
+		}
+		p = parent.Parent
+	}
+	var pr any
+	if n := v.prNumbers[name]; n != nil {
+		pr = float64(*n)
+	}
+	return map[string]any{
+		"name":       name,
+		"parent":     info.Parent,
+		"pr":         pr,
+		"seq":        float64(info.Seq),
+		"depth":      float64(depth),
+		"after":      float64(len(info.After)),
+		"blocked_by": float64(len(v.readiness[name].BlockedBy)),
+		"ready":      v.readiness[name].Ready,
+		"pr_state":   v.prStates[name].State,
+		"pr_author":  v.prStates[name].Author,
+	}
+}
+
+// hasUnpushedCommits reports whether a branch's tip differs from what was
+// last pushed. It compares against the recorded PushedSHA, falling back to
+// the local origin/<branch> ref; neither needs the network.
+func hasUnpushedCommits(ctx context.Context, name string, b state.Branch) bool {
+	tip, err := git.CurrentCommit(ctx, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not resolve %s: %v\n", name, err)
+		return false
+	}
+	pushed := b.PushedSHA
+	if pushed == "" {
+		if pushed, err = git.RevParse(ctx, "refs/remotes/origin/"+name); err != nil {
+			return true // never pushed
+		}
+	}
+	return tip != pushed
+}
+
+// isVisible reports whether a branch passes the active filters.
+func (v *statusView) isVisible(name string) bool {
+	return v.visible == nil || v.visible[name]
+}
+
+// treeBranches returns the branches to draw: the visible ones plus their
+// ancestors, so filtered branches keep their place in the tree, plus any
+// merged branches being shown.
+func (v *statusView) treeBranches() map[string]dag.BranchInfo {
+	out := v.visibleTree()
+	if len(v.merged) == 0 {
+		return out
+	}
+	out = maps.Clone(out)
+	for name, m := range v.merged {
+		if _, tracked := v.branches[name]; tracked {
+			continue // merged once, then tracked again under the same name
+		}
+		parent := m.Parent
+		if _, ok := out[parent]; !ok && !v.isTrunk(parent) {
+			parent = v.trunk // the old parent is gone too
+		}
+		out[name] = dag.BranchInfo{Parent: parent}
+	}
+	return out
+}
+
+// isTrunk reports whether name is the primary or a secondary trunk.
+func (v *statusView) isTrunk(name string) bool {
+	return name == v.trunk || slices.Contains(v.trunks, name)
+}
+
+// visibleTree returns the visible branches plus their ancestors.
+func (v *statusView) visibleTree() map[string]dag.BranchInfo {
+	if v.visible == nil {
+		return v.branches
+	}
+	out := make(map[string]dag.BranchInfo)
+	for name := range v.visible {
+		for cur := name; ; {
+			info, ok := v.branches[cur]
+			if !ok {
+				break
+			}
+			if _, done := out[cur]; done {
+				break
+			}
+			out[cur] = info
+			cur = info.Parent
+		}
+	}
+	return out
+}
+
+// prFetchWorkers bounds how many gh.PRView calls fetchPRStates runs at once.
+const prFetchWorkers = 8
+
+// fetchPRStates calls gh.PRView for each branch that has a PR number,
+// viewing each PR only once even if several branches claim it. Up to
+// prFetchWorkers PRs are viewed concurrently. On individual failures it
+// warns to stderr and continues.
+func fetchPRStates(ctx context.Context, prNumbers map[string]*int) map[string]gh.PRInfo {
+	claims := make(map[int][]string)
+	for name, pr := range prNumbers {
+		if pr != nil {
+			claims[*pr] = append(claims[*pr], name)
+		}
+	}
+
+	jobs := make(chan int)
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		states = make(map[string]gh.PRInfo)
+	)
+	for range min(prFetchWorkers, len(claims)) {
+		wg.Go(func() {
+			for pr := range jobs {
+				names := claims[pr]
+				info, err := gh.PRView(ctx, pr)
+				mu.Lock()
+				if err != nil {
+					slices.Sort(names)
+					fmt.Fprintf(os.Stderr, "warning: failed to fetch PR #%d for %s: %v\n", pr, strings.Join(names, ", "), err)
+				} else {
+					for _, name := range names {
+						states[name] = *info
+					}
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	for pr := range claims {
+		jobs <- pr
+	}
+	close(jobs)
+	wg.Wait()
+	return states
+}
+
+// untrackedBranches returns the local git branches that are neither a trunk
+// nor tracked, sorted.
+func untrackedBranches(ctx context.Context, s *state.State) ([]string, error) {
+	all, err := git.ListBranches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing branches: %w", err)
+	}
+	untracked := []string{}
+	for _, name := range all {
+		if _, tracked := s.Branches[name]; !tracked && !s.IsTrunk(name) {
+			untracked = append(untracked, name)
+		}
+	}
+	return untracked, nil
+}
+
+// prStateNotFetched is the PR state of branches left out by --budget.
+const prStateNotFetched = "unknown (not fetched)"
+
+// fetchPRStatesBudget is fetchPRStates limited to budget distinct PRs,
+// taken from the branches nearest to from in the tree (ties by name).
+// Branches whose PR is left out get state prStateNotFetched.
+func fetchPRStatesBudget(ctx context.Context, branches map[string]dag.BranchInfo, prNumbers map[string]*int, from string, budget int) map[string]gh.PRInfo {
+	dist := treeDistances(branches, from)
+	var names []string
+	for name, pr := range prNumbers {
+		if pr != nil {
+			names = append(names, name)
+		}
+	}
+	// Branches in another trunk's tree have no distance and go last.
+	distOf := func(name string) int {
+		if d, ok := dist[name]; ok {
+			return d
+		}
+		return len(branches) + 1
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(cmp.Compare(distOf(a), distOf(b)), cmp.Compare(a, b))
+	})
+
+	chosen := make(map[int]bool)
+	picked := make(map[string]*int)
+	for _, name := range names {
+		pr := prNumbers[name]
+		if !chosen[*pr] && len(chosen) == budget {
 			continue
 		}
-		states[name] = info.State
+		chosen[*pr] = true
+		picked[name] = pr
+	}
+
+	states := fetchPRStates(ctx, picked)
+	for _, name := range names {
+		if _, ok := picked[name]; !ok {
+			states[name] = gh.PRInfo{Number: *prNumbers[name], State: prStateNotFetched}
+		}
 	}
 	return states
 }
 
+// treeDistances returns the number of parent links between from and every
+// branch in the same tree, walking both up and down.
+func treeDistances(branches map[string]dag.BranchInfo, from string) map[string]int {
+	adj := make(map[string][]string)
+	for name, info := range branches {
+		adj[name] = append(adj[name], info.Parent)
+		adj[info.Parent] = append(adj[info.Parent], name)
+	}
+	dist := map[string]int{from: 0}
+	queue := []string{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range adj[cur] {
+			if _, seen := dist[next]; !seen {
+				dist[next] = dist[cur] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return dist
+}
+
+// unassignedGroup is the --group-by-author bucket for branches without a
+// known PR author.
+const unassignedGroup = "unassigned"
+
+// authorGroups buckets the visible branches by PR author, each sorted by
+// name. Branches without a PR (or whose PR could not be fetched) go under
+// unassignedGroup.
+func (v *statusView) authorGroups() map[string][]string {
+	groups := make(map[string][]string)
+	for name := range v.branches {
+		if !v.isVisible(name) {
+			continue
+		}
+		author := v.prStates[name].Author
+		if author == "" {
+			author = unassignedGroup
+		}
+		groups[author] = append(groups[author], name)
+	}
+	for _, names := range groups {
+		slices.Sort(names)
+	}
+	return groups
+}
+
 // outputJSON renders JSON output using dag.RenderJSON, optionally enriched
-// with PR state information from --fetch.
-func outputJSON(trunk string, branches map[string]dag.BranchInfo, prNumbers map[string]*int, prStates map[string]string) error {
-	jsonBranches := dag.RenderJSON(trunk, branches, prNumbers)
+// with PR state information from --fetch. Filtered-out branches are omitted.
+func outputJSON(v *statusView) error {
+	emit := printJSON
+	if v.stream {
+		emit = printJSONLine
+	}
+	var tree string
+	if v.withTree {
+		tree = v.renderTree()
+	}
+	jsonBranches := []dag.JSONBranch{}
+	for _, jb := range dag.RenderJSON(v.trunk, v.branches, v.prNumbers) {
+		if v.isVisible(jb.Name) {
+			jsonBranches = append(jsonBranches, jb)
+		}
+	}
 
-	if len(prStates) > 0 {
-		// Wrap with statusBranch to include pr_state.
+	if len(v.prStates) > 0 || v.stale != nil || v.subtreeCommits != nil || v.sinceSync != nil || v.noReadiness {
+		// Wrap with statusBranch to include pr_state and stale.
 		wrapped := make([]statusBranch, len(jsonBranches))
 		for i, jb := range jsonBranches {
 			wrapped[i] = statusBranch{
 				JSONBranch: jb,
-				PRState:    prStates[jb.Name],
+				PRState:    v.prStates[jb.Name].State,
+				PRAuthor:   v.prStates[jb.Name].Author,
+				BaseDrift:  v.baseDrift[jb.Name],
+			}
+			if !v.noReadiness {
+				ready := jb.Ready
+				wrapped[i].Ready = &ready
+				wrapped[i].BlockedBy = &jb.BlockedBy
+			}
+			if v.stale != nil {
+				stale := v.stale[jb.Name]
+				wrapped[i].Stale = &stale
+			}
+			if n, ok := v.subtreeCommits[jb.Name]; ok {
+				wrapped[i].SubtreeCommits = &n
+			}
+			wrapped[i].PRChecks = v.prStates[jb.Name].Checks
+			if v.sinceSync != nil {
+				changed := v.sinceSync[jb.Name]
+				wrapped[i].ChangedSinceSync = &changed
 			}
 		}
-		return printJSON(statusFetchResult{
-			Trunk:    trunk,
-			Branches: wrapped,
+		return emit(statusFetchResult{
+			Driver:          driverName,
+			Trunk:           v.trunk,
+			Trunks:          v.trunks,
+			Root:            v.root,
+			Tree:            tree,
+			TrunkNewCommits: v.trunkNewCommits,
+			Branches:        wrapped,
+			Merged:          mergedResults(v.merged),
+			Groups:          v.jsonGroups(),
+			Checks:          v.checks,
+			Untracked:       v.untracked,
 		})
 	}
-	return printJSON(statusJSONResult{
-		Trunk:    trunk,
-		Branches: jsonBranches,
+	return emit(statusJSONResult{
+		Driver:          driverName,
+		Trunk:           v.trunk,
+		Trunks:          v.trunks,
+		Root:            v.root,
+		Tree:            tree,
+		TrunkNewCommits: v.trunkNewCommits,
+		Branches:        jsonBranches,
+		Merged:          mergedResults(v.merged),
+		Groups:          v.jsonGroups(),
+		Checks:          v.checks,
+		Untracked:       v.untracked,
 	})
 }
 
-// outputHuman renders the ASCII tree and optionally a PR states section.
-func outputHuman(trunk string, branches map[string]dag.BranchInfo, prNumbers map[string]*int, readiness map[string]dag.ReadinessInfo, prStates map[string]string) error {
-	tree := dag.RenderTree(trunk, branches, prNumbers, readiness)
-	fmt.Print(tree)
+// jsonGroups returns authorGroups for --group-by-author, else nil.
+func (v *statusView) jsonGroups() map[string][]string {
+	if !v.groupByAuthor {
+		return nil
+	}
+	return v.authorGroups()
+}
+
+// mergedResults lists merged branches sorted by name.
+func mergedResults(merged map[string]state.MergedBranch) []mergedResult {
+	var out []mergedResult
+	for name, m := range merged {
+		out = append(out, mergedResult{Name: name, Parent: m.Parent, PR: m.PR, MergedAt: m.MergedAt, Author: m.Author})
+	}
+	slices.SortFunc(out, func(a, b mergedResult) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return out
+}
+
+// outputGrouped renders --group-by-author: one section per author, sorted
+// by login with unassigned last, each listing that author's branches.
+func outputGrouped(v *statusView) error {
+	groups := v.authorGroups()
+	var authors []string
+	for author := range groups {
+		if author != unassignedGroup {
+			authors = append(authors, author)
+		}
+	}
+	slices.Sort(authors)
+	if _, ok := groups[unassignedGroup]; ok {
+		authors = append(authors, unassignedGroup)
+	}
+	for i, author := range authors {
+		if i > 0 {
+			fmt.Println()
+		}
+		if author == unassignedGroup {
+			fmt.Println(author)
+		} else {
+			fmt.Printf("@%s\n", author)
+		}
+		for _, name := range groups[author] {
+			line := "  " + name
+			if !v.opts.HidePR {
+				if pr := v.prNumbers[name]; pr != nil {
+					line += fmt.Sprintf("  #%d", *pr)
+				} else {
+					line += "  (not pushed)"
+				}
+			}
+			if ri := v.readiness[name]; !v.noReadiness {
+				if ri.Ready {
+					line += "  [ready]"
+				} else {
+					line += fmt.Sprintf("  [blocked: %s]", strings.Join(ri.BlockedBy, ", "))
+				}
+			}
+			if note := v.opts.Notes[name]; note != "" {
+				line += "  " + note
+			}
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+// outputHuman renders one ASCII tree per trunk and optionally a PR states section.
+func outputHuman(v *statusView) error {
+	if v.groupByAuthor {
+		return outputGrouped(v)
+	}
+	fmt.Print(v.renderTree())
+
+	if v.checks != nil {
+		fmt.Println()
+		fmt.Println(v.checks)
+	}
+
+	if legendFlag {
+		fmt.Println()
+		fmt.Println("Legend:")
+		for _, e := range dag.Legend {
+			fmt.Printf("  %-16s %s\n", e.Symbol, e.Meaning)
+		}
+	}
 
-	if len(prStates) > 0 {
+	if v.diffBase {
+		fmt.Println()
+		if v.trunkNewCommits != nil {
+			fmt.Printf("%s: %d new commit(s) since last sync\n", v.trunk, *v.trunkNewCommits)
+		} else {
+			fmt.Printf("%s: no sync recorded yet\n", v.trunk)
+		}
+	}
+
+	if v.untracked != nil {
+		fmt.Println()
+		if len(*v.untracked) == 0 {
+			fmt.Println("No untracked branches")
+		} else {
+			fmt.Println("Untracked branches:")
+			for _, name := range *v.untracked {
+				fmt.Printf("  %s\n", name)
+			}
+			fmt.Println("Track one with 'frond track <branch> --on <parent>'")
+		}
+	}
+
+	if len(v.prStates) > 0 && !v.opts.HidePR {
 		fmt.Println()
 		fmt.Println("PR states:")
 
@@ -137,20 +1100,50 @@ func outputHuman(trunk string, branches map[string]dag.BranchInfo, prNumbers map
 			name   string
 			number int
 			state  string
+			author string
 		}
 		var entries []prEntry
-		for name, st := range prStates {
-			if pr, ok := prNumbers[name]; ok && pr != nil {
-				entries = append(entries, prEntry{name: name, number: *pr, state: st})
+		for name, info := range v.prStates {
+			if pr, ok := v.prNumbers[name]; ok && pr != nil && v.isVisible(name) {
+				entries = append(entries, prEntry{name: name, number: *pr, state: info.State, author: info.Author})
 			}
 		}
 		slices.SortFunc(entries, func(a, b prEntry) int {
 			return cmp.Compare(a.name, b.name)
 		})
 		for _, e := range entries {
-			fmt.Printf("  #%d %s — %s\n", e.number, e.name, e.state)
+			if e.author != "" {
+				fmt.Printf("  #%d %s — %s (@%s)\n", e.number, e.name, e.state, e.author)
+			} else {
+				fmt.Printf("  #%d %s — %s\n", e.number, e.name, e.state)
+			}
 		}
 	}
 
 	return nil
 }
+
+// renderTree draws one ASCII tree per trunk (or just the --trunk root),
+// including merged branches when shown.
+func (v *statusView) renderTree() string {
+	trunks := append([]string{v.trunk}, v.trunks...)
+	if v.root != "" {
+		trunks = []string{v.root}
+	}
+	prNumbers := v.prNumbers
+	if len(v.merged) > 0 {
+		prNumbers = maps.Clone(v.prNumbers)
+		v.opts.Merged = make(map[string]bool, len(v.merged))
+		for name, m := range v.merged {
+			if _, tracked := v.branches[name]; !tracked {
+				prNumbers[name] = m.PR
+				v.opts.Merged[name] = true
+			}
+		}
+	}
+	readiness := v.readiness
+	if v.noReadiness {
+		readiness = nil // the renderer skips [ready]/[blocked] without it
+	}
+	return dag.RenderTreesWith(trunks, v.treeBranches(), prNumbers, readiness, v.opts)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/frond/internal/dag"
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var submitCmd = &cobra.Command{
+	Use:   "submit",
+	Short: "Push every tracked branch and create/update their PRs",
+	Long: "Push all tracked branches in dependency order, parents first, creating a PR for each branch that has none " +
+		"and retargeting existing PRs at their parent. Stops at the first failure and reports which branches were already submitted. " +
+		"Use --from to submit only a branch and its descendants. Nothing is checked out.",
+	Example: `  # Push a freshly built stack and open all its PRs
+  frond submit
+
+  # Open everything as drafts
+  frond submit --draft
+
+  # Only the pay/api subtree
+  frond submit --from pay/api
+
+  # Re-submit after a rebase, skipping branches that did not move
+  frond submit --skip-unchanged --label needs-review
+
+  # JSON summary for scripting
+  frond submit --json`,
+	Args: cobra.NoArgs,
+	RunE: runSubmit,
+}
+
+func init() {
+	submitCmd.Flags().Bool("draft", false, "Create new PRs as drafts")
+	submitCmd.Flags().String("from", "", "Only submit this branch and its descendants")
+	submitCmd.Flags().Bool("skip-unchanged", false, "Skip branches whose tip matches the last recorded push")
+	submitCmd.Flags().StringArray("label", nil, "Add a label to every PR (repeatable)")
+	submitCmd.Flags().Bool("label-from-path", false, "Also add labels from the label_rules config whose prefix matches each branch name")
+	submitCmd.Flags().String("head-repo", "", "Fork that holds the branches (owner or owner/name); new PRs' heads become owner:branch")
+	submitCmd.Flags().String("repo", "", "Upstream repo (owner/name) for PRs, for fork workflows; saved as the repo config for later commands")
+	rootCmd.AddCommand(submitCmd)
+}
+
+func runSubmit(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := gh.Available(); err != nil {
+		return fmt.Errorf("gh CLI is required. Install: https://cli.github.com")
+	}
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	st, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	if err := applyRepoFlag(cmd, st); err != nil {
+		return err
+	}
+
+	from, _ := cmd.Flags().GetString("from")
+	if from != "" {
+		if from, err = resolveTracked(st.Branches, from); err != nil {
+			return err
+		}
+	}
+
+	order, err := dag.TopoSort(withParentEdges(stateToDag(st.Branches)))
+	if err != nil {
+		return fmt.Errorf("sorting branches: %w", err)
+	}
+	var branches []string
+	for _, name := range order {
+		if from == "" || name == from || descendsFrom(st.Branches, name, from) {
+			branches = append(branches, name)
+		}
+	}
+	if len(branches) == 0 {
+		if jsonOut {
+			return printJSON(submitResult{Branches: []submitBranch{}})
+		}
+		fmt.Println("no tracked branches to submit")
+		return nil
+	}
+
+	draft, _ := cmd.Flags().GetBool("draft")
+	headRepo, _ := cmd.Flags().GetString("head-repo")
+	skip, _ := cmd.Flags().GetBool("skip-unchanged")
+	labelFlags, _ := cmd.Flags().GetStringArray("label")
+	fromPath, _ := cmd.Flags().GetBool("label-from-path")
+
+	result := submitResult{Branches: []submitBranch{}}
+	var done []string
+	// fail reports err for name together with what was submitted before it.
+	fail := func(name string, err error) error {
+		if len(done) == 0 {
+			return fmt.Errorf("submitting %s: %w (nothing submitted)", name, err)
+		}
+		return fmt.Errorf("submitting %s: %w (already submitted: %s)", name, err, strings.Join(done, ", "))
+	}
+
+	for _, name := range branches {
+		labels, err := pushLabels(st, name, labelFlags, fromPath)
+		if err != nil {
+			return err
+		}
+		// pushBranch persists each branch, so a later failure keeps the
+		// PRs already opened.
+		out, err := pushBranch(ctx, st, name, pushOpts{
+			Draft:         draft,
+			HeadRepo:      headRepo,
+			Labels:        labels,
+			SkipUnchanged: skip,
+		})
+		if err != nil {
+			return fail(name, err)
+		}
+
+		done = append(done, name)
+		result.Branches = append(result.Branches, submitBranch{Branch: name, PR: out.PR, Created: out.Created, Skipped: out.Skipped})
+		if !jsonOut {
+			switch {
+			case out.Skipped:
+				fmt.Printf("Skipped %s: unchanged since last push. PR #%d\n", name, out.PR)
+			case out.Created:
+				fmt.Printf("Pushed %s. PR #%d [created]\n", name, out.PR)
+			default:
+				fmt.Printf("Pushed %s. PR #%d [updated]\n", name, out.PR)
+			}
+		}
+	}
+
+	updateStackComments(ctx, st)
+
+	if jsonOut {
+		return printJSON(result)
+	}
+	return nil
+}
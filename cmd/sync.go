@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/nvandessel/frond/internal/dag"
@@ -17,10 +19,16 @@ import (
 type syncResult struct {
 	Merged     []string            `json:"merged"`
 	Reparented map[string]string   `json:"reparented"`
+	Retargeted map[string]string   `json:"retargeted"` // branch -> new PR base
 	Rebased    []string            `json:"rebased"`
 	Unblocked  []string            `json:"unblocked"`
 	Blocked    map[string][]string `json:"blocked"`
 	Conflicts  []string            `json:"conflicts"`
+	Skipped    map[string]string   `json:"skipped"`               // branch -> reason
+	InProgress string              `json:"in_progress,omitempty"` // branch left mid-rebase by --conflict-strategy stop
+	Snapshot   string              `json:"snapshot,omitempty"`
+	// MergedAuthors maps each merged branch to its PR author, with --notify-merged.
+	MergedAuthors map[string]string `json:"merged_authors,omitempty"`
 }
 
 // syncAction represents a single line of human-readable output.
@@ -36,17 +44,79 @@ var syncCmd = &cobra.Command{
   frond sync
 
   # Sync with JSON output
-  frond sync --json`,
+  frond sync --json
+
+  # On a shared stack, say whose PRs merged since the last sync
+  frond sync --notify-merged
+
+  # Refuse to rebase more than 10 branches without --force
+  frond sync --max-rebase 10
+
+  # On a shared stack, only rebase/retarget branches whose PRs you authored
+  frond sync --author-only
+
+  # Never touch a long-running experiment or anything stacked on it
+  frond sync --exclude exp/new-engine
+
+  # Clean up after merges and retarget PRs without touching the working copy
+  frond sync --retarget-only
+
+  # Offline: skip the fetch and restack onto the local trunk
+  frond sync --no-fetch
+
+  # On a large repo, fetch only trunk and tracked branches
+  frond sync --fetch-scope stack
+
+  # On a conflict, keep rebasing branches that do not depend on it
+  frond sync --conflict-strategy skip
+
+  # On git 2.38+, restack each linear run with a single rebase
+  frond sync --use-update-refs
+
+  # Among independent branches, rebase older branches first
+  frond sync --branch-order created
+
+  # Roll back metadata changes from a bad sync
+  frond undo <snapshot-id>`,
 	RunE: runSync,
 }
 
 func init() {
+	syncCmd.Flags().Int("max-rebase", 0, "Abort before rebasing more than this many branches (0 = no limit)")
+	syncCmd.Flags().Bool("force", false, "Proceed even when the planned rebases exceed --max-rebase")
+	syncCmd.Flags().Bool("author-only", false, "Only rebase/retarget branches whose PR author is the current gh user")
+	syncCmd.Flags().Bool("no-snapshot", false, "Skip the safety snapshot of frond.json taken before syncing")
+	syncCmd.Flags().String("exclude", "", "Comma-separated branches to leave alone, with their descendants (applied before --author-only)")
+	syncCmd.Flags().Bool("retarget-only", false, "Detect merges, reparent, and retarget PRs, but do not rebase")
+	syncCmd.Flags().Bool("no-fetch", false, "Do not fetch from origin; rebase onto the trunk as it is locally")
+	syncCmd.Flags().String("fetch-scope", "all", "What to fetch from origin: all, or stack (trunks and tracked branches only)")
+	syncCmd.Flags().String("conflict-strategy", "abort", "On a rebase conflict: abort (stop syncing), skip (continue with branches not stacked on it), or stop (leave the rebase in progress)")
+	syncCmd.Flags().String("branch-order", "alpha", "Order among independent branches: alpha or created")
+	syncCmd.Flags().Bool("notify-merged", false, "Name the PR author of each merged branch in the summary and JSON")
+	syncCmd.Flags().Bool("use-update-refs", false, "On git 2.38+, restack each linear run of branches with one 'git rebase --update-refs' of its top")
 	rootCmd.AddCommand(syncCmd)
 }
 
 func runSync(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
+	order, _ := cmd.Flags().GetString("branch-order")
+	if _, err := branchOrder(order, nil); err != nil {
+		return err
+	}
+	strategy, _ := cmd.Flags().GetString("conflict-strategy")
+	if strategy != "abort" && strategy != "skip" && strategy != "stop" {
+		return fmt.Errorf("invalid --conflict-strategy %q: want abort, skip or stop", strategy)
+	}
+	fetchScope, _ := cmd.Flags().GetString("fetch-scope")
+	if fetchScope != "all" && fetchScope != "stack" {
+		return fmt.Errorf("invalid --fetch-scope %q: want all or stack", fetchScope)
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
 	// Step 1: Lock state, defer unlock.
 	unlock, err := state.Lock(ctx)
 	if err != nil {
@@ -70,7 +140,15 @@ func runSync(cmd *cobra.Command, args []string) error {
 	}
 
 	// Step 3: Fetch from origin.
-	if err := git.Fetch(ctx); err != nil {
+	noFetch, _ := cmd.Flags().GetBool("no-fetch")
+	switch {
+	case noFetch:
+	case fetchScope == "stack":
+		err = fetchStack(ctx, st)
+	default:
+		err = git.Fetch(ctx)
+	}
+	if err != nil {
 		return fmt.Errorf("fetching: %w", err)
 	}
 
@@ -80,27 +158,104 @@ func runSync(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting current branch: %w", err)
 	}
 
+	// --exclude covers the listed branches and everything stacked on them.
+	excluded := make(map[string]bool)
+	if list, _ := cmd.Flags().GetString("exclude"); list != "" {
+		listed, err := resolveBranchList(st.Branches, "--exclude", list)
+		if err != nil {
+			return err
+		}
+		for name := range st.Branches {
+			if listed[name] {
+				excluded[name] = true
+				continue
+			}
+			for ex := range listed {
+				if descendsFrom(st.Branches, name, ex) {
+					excluded[name] = true
+					break
+				}
+			}
+		}
+	}
+
+	notifyMerged, _ := cmd.Flags().GetBool("notify-merged")
+
+	// With --author-only, resolve the gh user once up front.
+	authorOnly, _ := cmd.Flags().GetBool("author-only")
+	var me string
+	if authorOnly {
+		me, err = gh.CurrentUser(ctx)
+		if err != nil {
+			return fmt.Errorf("determining gh user for --author-only: %w", err)
+		}
+	}
+
 	result := newEmptySyncResult()
 	var actions []syncAction
 
+	// Safety snapshot so 'frond undo <id>' can roll back a bad sync.
+	if noSnapshot, _ := cmd.Flags().GetBool("no-snapshot"); !noSnapshot {
+		id, err := state.Snapshot(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not snapshot state: %v\n", err)
+		} else {
+			result.Snapshot = id
+			if !jsonOut {
+				fmt.Printf("Snapshot %s saved (restore with 'frond undo %s')\n", id, id)
+			}
+		}
+	}
+
 	// Step 4: Detect merged branches.
 	var mergedBranches []string
 	mergedData := make(map[string]state.Branch) // preserve data before deletion
+	authors := make(map[string]string)          // branch -> PR author login
+	// One gh pr list call covers most PRs; any it misses (or all of them,
+	// if it fails) are viewed one by one.
+	var prNums []int
 	for name, b := range st.Branches {
-		if b.PR == nil {
-			continue
+		if b.PR != nil && !excluded[name] {
+			prNums = append(prNums, *b.PR)
 		}
-		info, err := gh.PRView(ctx, *b.PR)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not check PR #%d for %s: %v\n", *b.PR, name, err)
+	}
+	var batch map[int]gh.PRInfo
+	if len(prNums) > 0 {
+		if batch, err = gh.PRStatesBatch(ctx, prNums); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not list PRs, checking them one by one: %v\n", err)
+		}
+	}
+	for name, b := range st.Branches {
+		if b.PR == nil || excluded[name] {
 			continue
 		}
+		info, ok := batch[*b.PR]
+		if !ok {
+			viewed, err := gh.PRView(ctx, *b.PR)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: could not check PR #%d for %s: %v\n", *b.PR, name, err)
+				continue
+			}
+			info = *viewed
+		}
+		authors[name] = info.Author
 		if info.State == gh.PRStateMerged {
 			mergedBranches = append(mergedBranches, name)
 			mergedData[name] = b
 		}
 	}
 
+	// notMine reports whether --author-only should leave a branch alone: it
+	// has a PR that is not (known to be) authored by the current user.
+	notMine := func(name string) bool {
+		return authorOnly && st.Branches[name].PR != nil && authors[name] != me
+	}
+
+	// leaveAlone reports whether sync must not rebase or retarget a branch.
+	leaveAlone := func(name string) bool {
+		return excluded[name] || notMine(name)
+	}
+
 	// Step 5: Process merged branches.
 	// reparentedFrom tracks what the old parent was for each reparented child.
 	reparentedFrom := make(map[string]string)
@@ -110,9 +265,17 @@ func runSync(cmd *cobra.Command, args []string) error {
 		mergedParent := mergedBranch.Parent
 
 		result.Merged = append(result.Merged, merged)
+		message := fmt.Sprintf("%s merged \u2192 removed", merged)
+		if author := authors[merged]; notifyMerged && author != "" {
+			if result.MergedAuthors == nil {
+				result.MergedAuthors = make(map[string]string)
+			}
+			result.MergedAuthors[merged] = author
+			message = fmt.Sprintf("%s (PR #%d by @%s) merged \u2192 removed", merged, *mergedBranch.PR, author)
+		}
 		actions = append(actions, syncAction{
 			symbol:  "\u2713",
-			message: fmt.Sprintf("%s merged \u2192 removed", merged),
+			message: message,
 		})
 
 		// 5a: Reparent children whose parent was the merged branch.
@@ -123,10 +286,17 @@ func runSync(cmd *cobra.Command, args []string) error {
 				result.Reparented[childName] = mergedParent
 				reparentedFrom[childName] = merged
 
-				// 5b: Update child PRs to point to new parent.
-				if childBranch.PR != nil {
+				// 5b: Update child PRs to point to new parent, unless
+				// they target a fixed base.
+				if childBranch.PR != nil && childBranch.BaseOverride == "" && !leaveAlone(childName) {
 					if err := gh.PREdit(ctx, *childBranch.PR, mergedParent); err != nil {
 						fmt.Fprintf(os.Stderr, "warning: could not retarget PR #%d for %s: %v\n", *childBranch.PR, childName, err)
+					} else {
+						result.Retargeted[childName] = mergedParent
+						actions = append(actions, syncAction{
+							symbol:  "\u21aa",
+							message: fmt.Sprintf("PR #%d (%s) retargeted to %s", *childBranch.PR, childName, mergedParent),
+						})
 					}
 				}
 			}
@@ -138,8 +308,10 @@ func runSync(cmd *cobra.Command, args []string) error {
 			st.Branches[name] = b
 		}
 
-		// 5d: Remove merged branch from state.
+		// 5d: Remove merged branch from state, keeping a record of it for
+		// status --show-merged.
 		delete(st.Branches, merged)
+		st.RecordMerged(merged, mergedBranch, authors[merged])
 	}
 
 	// Write state BEFORE rebasing so that if rebase fails, state is still consistent.
@@ -153,10 +325,24 @@ func runSync(cmd *cobra.Command, args []string) error {
 		updateStackComments(ctx, st)
 	}
 
+	// --retarget-only stops here: nothing local is rebased or checked out,
+	// so trunk is not recorded as synced either.
+	if retargetOnly, _ := cmd.Flags().GetBool("retarget-only"); retargetOnly {
+		if len(mergedBranches) == 0 {
+			if jsonOut {
+				return printJSON(result)
+			}
+			fmt.Println("already up to date")
+			return nil
+		}
+		return printSyncSummary(result, actions)
+	}
+
 	// Step 6: Rebase remaining branches in topological order.
 	dagBranches := stateToDag(st.Branches)
 
-	topoOrder, err := dag.TopoSort(dagBranches)
+	tieBreak, _ := branchOrder(order, dagBranches)
+	topoOrder, err := dag.TopoSortFunc(dagBranches, tieBreak)
 	if err != nil {
 		return fmt.Errorf("computing topological order: %w", err)
 	}
@@ -176,40 +362,147 @@ func runSync(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Guardrail: refuse runaway rebases unless --force is given. Merge
+	// cleanup above is already persisted; only the rebase loop is skipped.
+	maxRebase, _ := cmd.Flags().GetInt("max-rebase")
+	force, _ := cmd.Flags().GetBool("force")
+	if maxRebase > 0 && !force {
+		planned := 0
+		for _, ri := range readiness {
+			if ri.Ready && !leaveAlone(ri.Name) {
+				planned++
+			}
+		}
+		if planned > maxRebase {
+			return fmt.Errorf("sync would rebase %d branches, exceeding --max-rebase %d; rerun with --force to proceed", planned, maxRebase)
+		}
+	}
+
+	recordRebased := func(name string) {
+		b := st.Branches[name]
+		parent := b.Parent
+		result.Rebased = append(result.Rebased, name)
+		b.BaseSHA, _ = git.RevParse(ctx, parent)
+		st.Branches[name] = b
+
+		if unblockedSet[name] {
+			result.Unblocked = append(result.Unblocked, name)
+			oldParent := reparentedFrom[name]
+			actions = append(actions, syncAction{
+				symbol:  "\u2191",
+				message: fmt.Sprintf("%s now unblocked [was blocked: %s]", name, oldParent),
+			})
+		} else if oldParent, reparented := reparentedFrom[name]; reparented {
+			actions = append(actions, syncAction{
+				symbol:  "\u2191",
+				message: fmt.Sprintf("%s rebased onto %s (was: %s)", name, parent, oldParent),
+			})
+		} else {
+			actions = append(actions, syncAction{
+				symbol:  "\u2191",
+				message: fmt.Sprintf("%s rebased onto %s", name, parent),
+			})
+		}
+	}
+
+	// With --use-update-refs on git 2.38+, a linear run of branches is
+	// restacked with one --update-refs rebase of its top; forks end a run.
+	// A conflict there falls back to the per-branch loop, which pins it on
+	// the right branch. "stop" always uses the loop so the rebase it leaves
+	// in progress belongs to one branch. So does a run with other local
+	// branches inside it, which --update-refs would move too.
+	updateRefs, _ := cmd.Flags().GetBool("use-update-refs")
+	updateRefs = updateRefs && strategy != "stop" && git.SupportsUpdateRefs(ctx)
+	restacked := make(map[string]bool)
+
 	var conflictBranch string
 	for _, name := range topoOrder {
+		if conflictBranch != "" && strategy != "skip" {
+			break
+		}
+		if restacked[name] {
+			continue
+		}
+		if c := conflictedAncestor(st.Branches, name, result.Conflicts); c != "" {
+			reason := fmt.Sprintf("stacked on conflicted %s", c)
+			result.Skipped[name] = reason
+			actions = append(actions, syncAction{
+				symbol:  "-",
+				message: fmt.Sprintf("%s skipped (%s)", name, reason),
+			})
+			continue
+		}
+		if excluded[name] {
+			result.Skipped[name] = "excluded"
+			actions = append(actions, syncAction{
+				symbol:  "-",
+				message: fmt.Sprintf("%s skipped (excluded)", name),
+			})
+			continue
+		}
+		if notMine(name) {
+			reason := "not authored by you"
+			if a := authors[name]; a != "" {
+				reason = fmt.Sprintf("authored by @%s", a)
+			}
+			result.Skipped[name] = reason
+			actions = append(actions, syncAction{
+				symbol:  "-",
+				message: fmt.Sprintf("%s skipped (%s)", name, reason),
+			})
+			continue
+		}
 		ri := readinessMap[name]
 		if ri.Ready {
 			parent := st.Branches[name].Parent
-			if err := git.Rebase(ctx, parent, name); err != nil {
+			if updateRefs {
+				chain, err := linearChain(ctx, st.Branches, name, func(c string) bool {
+					return readinessMap[c].Ready && !leaveAlone(c)
+				})
+				if err != nil {
+					return err
+				}
+				if len(chain) > 1 {
+					if chain, err = onlyChainInRange(ctx, parent, chain); err != nil {
+						return err
+					}
+				}
+				if len(chain) > 1 {
+					err := git.RebaseUpdateRefs(ctx, parent, chain[len(chain)-1])
+					var conflictErr *git.RebaseConflictError
+					if err != nil && !errors.As(err, &conflictErr) {
+						return fmt.Errorf("rebasing %s: %w", strings.Join(chain, ", "), err)
+					}
+					if err == nil {
+						for _, c := range chain {
+							restacked[c] = true
+							recordRebased(c)
+						}
+						continue
+					}
+				}
+			}
+			rebase := git.Rebase
+			if strategy == "stop" {
+				rebase = git.RebaseKeepConflict
+			}
+			if err := rebase(ctx, parent, name); err != nil {
 				var conflictErr *git.RebaseConflictError
 				if errors.As(err, &conflictErr) {
 					conflictBranch = name
 					result.Conflicts = append(result.Conflicts, name)
-					break
+					if strategy == "stop" {
+						result.InProgress = name
+					}
+					actions = append(actions, syncAction{
+						symbol:  "\u2717",
+						message: fmt.Sprintf("%s conflicts with %s", name, parent),
+					})
+					continue
 				}
 				return fmt.Errorf("rebasing %s: %w", name, err)
 			}
-			result.Rebased = append(result.Rebased, name)
-
-			if unblockedSet[name] {
-				result.Unblocked = append(result.Unblocked, name)
-				oldParent := reparentedFrom[name]
-				actions = append(actions, syncAction{
-					symbol:  "\u2191",
-					message: fmt.Sprintf("%s now unblocked [was blocked: %s]", name, oldParent),
-				})
-			} else if oldParent, reparented := reparentedFrom[name]; reparented {
-				actions = append(actions, syncAction{
-					symbol:  "\u2191",
-					message: fmt.Sprintf("%s rebased onto %s (was: %s)", name, parent, oldParent),
-				})
-			} else {
-				actions = append(actions, syncAction{
-					symbol:  "\u2191",
-					message: fmt.Sprintf("%s rebased onto %s", name, parent),
-				})
-			}
+			recordRebased(name)
 		} else {
 			result.Blocked[name] = ri.BlockedBy
 			actions = append(actions, syncAction{
@@ -219,15 +512,28 @@ func runSync(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Restore original branch after rebasing.
-	if len(result.Rebased) > 0 || conflictBranch != "" {
+	// Record where each rebased branch now starts.
+	if len(result.Rebased) > 0 {
+		if err := state.Write(ctx, st); err != nil {
+			return fmt.Errorf("writing state: %w", err)
+		}
+	}
+
+	// Restore original branch after rebasing; a rebase left in progress
+	// stays checked out for resolution.
+	if (len(result.Rebased) > 0 || conflictBranch != "") && result.InProgress == "" {
 		if err := git.Checkout(ctx, originalBranch); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: could not restore branch %s: %v\n", originalBranch, err)
 		}
 	}
 
+	// Remember where trunk was so status --diff-base can count new commits.
+	if conflictBranch == "" {
+		recordSyncedTrunk(ctx, st)
+	}
+
 	// Edge case: nothing happened at all.
-	if len(mergedBranches) == 0 && len(result.Rebased) == 0 && len(result.Blocked) == 0 && conflictBranch == "" {
+	if len(mergedBranches) == 0 && len(result.Rebased) == 0 && len(result.Blocked) == 0 && len(result.Skipped) == 0 && conflictBranch == "" {
 		if jsonOut {
 			return printJSON(result)
 		}
@@ -236,21 +542,18 @@ func runSync(cmd *cobra.Command, args []string) error {
 	}
 
 	// Step 8: Print summary.
-	if jsonOut {
-		if err := printJSON(result); err != nil {
-			return fmt.Errorf("encoding JSON: %w", err)
-		}
-	} else {
-		fmt.Println("Synced:")
-		for _, a := range actions {
-			fmt.Printf("  %s %s\n", a.symbol, a.message)
-		}
+	if err := printSyncSummary(result, actions); err != nil {
+		return err
 	}
 
 	// If there was a conflict, print conflict message and exit with code 2.
 	if conflictBranch != "" {
 		if !jsonOut {
-			fmt.Fprintf(os.Stderr, "conflict: %s \u2014 resolve and run 'frond sync' again\n", conflictBranch)
+			if result.InProgress != "" {
+				fmt.Fprintf(os.Stderr, "conflict: %s \u2014 rebase left in progress; resolve and 'git rebase --continue' (or 'frond abort'), then run 'frond sync' again\n", result.InProgress)
+			} else {
+				fmt.Fprintf(os.Stderr, "conflict: %s \u2014 resolve and run 'frond sync' again\n", strings.Join(result.Conflicts, ", "))
+			}
 		}
 		return &ExitError{Code: 2}
 	}
@@ -258,6 +561,114 @@ func runSync(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// linearChain returns name followed by its single-child descendants, for
+// as long as each next branch is the only child of the one before it,
+// passes ok, and already contains its parent's tip. Such a chain can be
+// restacked by rebasing its last branch with --update-refs.
+func linearChain(ctx context.Context, branches map[string]state.Branch, name string, ok func(string) bool) ([]string, error) {
+	children := make(map[string][]string)
+	for child, b := range branches {
+		children[b.Parent] = append(children[b.Parent], child)
+	}
+	chain := []string{name}
+	for cur := name; len(children[cur]) == 1; {
+		next := children[cur][0]
+		if !ok(next) || slices.Contains(chain, next) {
+			break
+		}
+		stacked, err := git.IsAncestor(ctx, cur, next)
+		if err != nil {
+			return nil, fmt.Errorf("checking %s is stacked on %s: %w", next, cur, err)
+		}
+		if !stacked {
+			break
+		}
+		chain = append(chain, next)
+		cur = next
+	}
+	return chain, nil
+}
+
+// onlyChainInRange returns chain if no local branch outside it points into
+// the commits a rebase of its top onto parent would rewrite, and nil
+// otherwise, so --update-refs never moves a branch frond did not plan to.
+func onlyChainInRange(ctx context.Context, parent string, chain []string) ([]string, error) {
+	inRange, err := git.BranchesBetween(ctx, parent, chain[len(chain)-1])
+	if err != nil {
+		return nil, fmt.Errorf("listing branches in %s: %w", strings.Join(chain, ", "), err)
+	}
+	for _, b := range inRange {
+		if !slices.Contains(chain, b) {
+			return nil, nil
+		}
+	}
+	return chain, nil
+}
+
+// conflictedAncestor returns a branch in conflicts that name is stacked on,
+// or "" if there is none.
+func conflictedAncestor(branches map[string]state.Branch, name string, conflicts []string) string {
+	for _, c := range conflicts {
+		if descendsFrom(branches, name, c) {
+			return c
+		}
+	}
+	return ""
+}
+
+// fetchStack fetches only the trunks and tracked branches that exist on
+// origin; unpushed branches and branches deleted after merging are skipped.
+func fetchStack(ctx context.Context, st *state.State) error {
+	remote, err := git.RemoteBranches(ctx, "origin")
+	if err != nil {
+		return err
+	}
+	var refs []string
+	for _, name := range st.AllTrunks() {
+		if remote[name] {
+			refs = append(refs, name)
+		}
+	}
+	for name := range st.Branches {
+		if remote[name] {
+			refs = append(refs, name)
+		}
+	}
+	return git.FetchBranches(ctx, refs)
+}
+
+// printSyncSummary prints the sync result as JSON or as one line per action.
+func printSyncSummary(result *syncResult, actions []syncAction) error {
+	if jsonOut {
+		if err := printJSON(result); err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+		return nil
+	}
+	fmt.Println("Synced:")
+	for _, a := range actions {
+		fmt.Printf("  %s %s\n", a.symbol, a.message)
+	}
+	return nil
+}
+
+// recordSyncedTrunk stores the current trunk SHA as LastSyncedTrunk.
+// Failures only warn: the sync itself has already succeeded.
+func recordSyncedTrunk(ctx context.Context, st *state.State) {
+	sha, err := git.RevParse(ctx, st.Trunk)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not resolve %s: %v\n", st.Trunk, err)
+		return
+	}
+	if sha == st.LastSyncedTrunk {
+		return
+	}
+	st.LastSyncedTrunk = sha
+	if err := state.Write(ctx, st); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not record synced trunk: %v\n", err)
+	}
+}
+
 // removeFromSlice returns a new slice with all occurrences of val removed.
 // Returns nil if the result would be empty.
 func removeFromSlice(s []string, val string) []string {
@@ -276,9 +687,11 @@ func newEmptySyncResult() *syncResult {
 	return &syncResult{
 		Merged:     []string{},
 		Reparented: make(map[string]string),
+		Retargeted: make(map[string]string),
 		Rebased:    []string{},
 		Unblocked:  []string{},
 		Blocked:    make(map[string][]string),
 		Conflicts:  []string{},
+		Skipped:    make(map[string]string),
 	}
 }
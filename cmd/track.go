@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/nvandessel/frond/internal/gh"
 	"github.com/nvandessel/frond/internal/git"
 	"github.com/nvandessel/frond/internal/state"
 	"github.com/spf13/cobra"
@@ -16,15 +18,20 @@ var trackCmd = &cobra.Command{
   frond track my-feature --on main
 
   # Track with a dependency
-  frond track step-2 --on step-1 --after step-1`,
+  frond track step-2 --on step-1 --after step-1
+
+  # Adopt a branch that already has PR #57
+  frond track my-feature --on main --pr 57`,
 	Args: cobra.ExactArgs(1),
 	RunE: runTrack,
 }
 
 func init() {
 	trackCmd.Flags().String("on", "", "Git parent branch (PR base) [required]")
+	trackCmd.Flags().String("parent", "", "Alias for --on")
 	trackCmd.Flags().String("after", "", "Comma-separated logical dependencies")
-	_ = trackCmd.MarkFlagRequired("on")
+	trackCmd.Flags().Int("pr", 0, "Existing PR number for this branch")
+	trackCmd.MarkFlagsOneRequired("on", "parent")
 	rootCmd.AddCommand(trackCmd)
 }
 
@@ -36,6 +43,10 @@ func runTrack(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
 	// 1. Lock state, defer unlock
 	unlock, err := state.Lock(ctx)
 	if err != nil {
@@ -64,21 +75,24 @@ func runTrack(cmd *cobra.Command, args []string) error {
 	}
 
 	// 4. Validate --on branch exists (trunk or tracked)
-	onFlag, _ := cmd.Flags().GetString("on")
-	if onFlag != s.Trunk {
-		if _, tracked := s.Branches[onFlag]; !tracked {
+	on, err := onFlagValue(cmd)
+	if err != nil {
+		return err
+	}
+	if !s.IsTrunk(on) {
+		if _, tracked := s.Branches[on]; !tracked {
 			// Also check if branch exists in git at all
-			onExists, err := git.BranchExists(ctx, onFlag)
+			onExists, err := git.BranchExists(ctx, on)
 			if err != nil {
 				return fmt.Errorf("checking parent branch: %w", err)
 			}
 			if !onExists {
-				return fmt.Errorf("branch '%s' does not exist", onFlag)
+				return fmt.Errorf("branch '%s' does not exist", on)
 			}
-			return fmt.Errorf("'%s' is not tracked. Track it first with 'frond track'", onFlag)
+			return fmt.Errorf("'%s' is not tracked. Track it first with 'frond track'", on)
 		}
 	}
-	parent := onFlag
+	parent := on
 
 	// 5. Parse --after
 	afterFlag, _ := cmd.Flags().GetString("after")
@@ -87,18 +101,28 @@ func runTrack(cmd *cobra.Command, args []string) error {
 		after = strings.Split(afterFlag, ",")
 	}
 
-	// 6. Validate --after deps and check for cycles
-	if err := validateAfterDeps(s.Branches, name, after); err != nil {
-		return err
+	// 6. Bind an existing PR, checking it is really this branch's.
+	var pr *int
+	if prFlag, _ := cmd.Flags().GetInt("pr"); prFlag != 0 {
+		if err := validateExistingPR(ctx, s.Branches, name, prFlag); err != nil {
+			return err
+		}
+		pr = &prFlag
 	}
 
-	// 7. Add to state.Branches (no checkout, no git branch creation)
+	// 7. Add to state.Branches (no checkout, no git branch creation) and
+	// check the graph, including --after cycles
 	if after == nil {
 		after = []string{}
 	}
 	s.Branches[name] = state.Branch{
 		Parent: parent,
 		After:  after,
+		PR:     pr,
+		Seq:    s.NextSeq(),
+	}
+	if err := graphValid(s); err != nil {
+		return fmt.Errorf("cannot track '%s': %w", name, err)
 	}
 
 	// 8. Write state
@@ -112,12 +136,37 @@ func runTrack(cmd *cobra.Command, args []string) error {
 			Name:   name,
 			Parent: parent,
 			After:  after,
+			PR:     pr,
 		})
 	}
 	fmt.Printf("Tracking branch '%s' (parent: %s)\n", name, parent)
+	if pr != nil {
+		fmt.Printf("PR: #%d\n", *pr)
+	}
 	if len(after) > 0 {
 		fmt.Printf("Dependencies: %s\n", strings.Join(after, ", "))
 	}
 
 	return nil
 }
+
+// validateExistingPR checks that PR number pr is not bound to another tracked
+// branch and that, according to GitHub, its head is branch.
+func validateExistingPR(ctx context.Context, branches map[string]state.Branch, branch string, pr int) error {
+	if pr < 0 {
+		return fmt.Errorf("invalid PR number %d", pr)
+	}
+	for other, b := range branches {
+		if b.PR != nil && *b.PR == pr {
+			return fmt.Errorf("PR #%d is already bound to '%s'", pr, other)
+		}
+	}
+	info, err := gh.PRView(ctx, pr)
+	if err != nil {
+		return fmt.Errorf("viewing PR #%d: %w", pr, err)
+	}
+	if info.HeadRefName != branch {
+		return fmt.Errorf("PR #%d is for branch '%s', not '%s'", pr, info.HeadRefName, branch)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/nvandessel/frond/internal/git"
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var trunkCmd = &cobra.Command{
+	Use:   "trunk",
+	Short: "List trunks; add or remove secondary trunks",
+	Long:  "Secondary trunks (e.g. long-lived release branches) root their own independent stacks alongside the primary trunk.",
+	Example: `  # List trunks
+  frond trunk
+
+  # Root stacks on a release branch
+  frond trunk add release/1.x
+  frond new fix/backport --on release/1.x
+
+  # Stop treating a branch as a trunk
+  frond trunk remove release/1.x`,
+	Args: cobra.NoArgs,
+	RunE: runTrunkList,
+}
+
+var trunkAddCmd = &cobra.Command{
+	Use:   "add <branch>",
+	Short: "Mark an existing branch as a secondary trunk",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTrunkAdd,
+}
+
+var trunkRemoveCmd = &cobra.Command{
+	Use:   "remove <branch>",
+	Short: "Stop treating a branch as a secondary trunk",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTrunkRemove,
+}
+
+func init() {
+	trunkCmd.AddCommand(trunkAddCmd, trunkRemoveCmd)
+	rootCmd.AddCommand(trunkCmd)
+}
+
+func runTrunkList(cmd *cobra.Command, args []string) error {
+	s, err := state.Read(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+	return printTrunks(s)
+}
+
+func runTrunkAdd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+
+	if err := validateBranchName(name); err != nil {
+		return err
+	}
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.ReadOrInit(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	if s.IsTrunk(name) {
+		return fmt.Errorf("'%s' is already a trunk", name)
+	}
+	if _, tracked := s.Branches[name]; tracked {
+		return fmt.Errorf("'%s' is tracked. Untrack it before making it a trunk", name)
+	}
+	exists, err := git.BranchExists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("checking branch existence: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("branch '%s' does not exist", name)
+	}
+
+	s.Trunks = append(s.Trunks, name)
+	if err := state.Write(ctx, s); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	return printTrunks(s)
+}
+
+func runTrunkRemove(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	s, err := state.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+
+	if name == s.Trunk {
+		return fmt.Errorf("'%s' is the primary trunk and cannot be removed", name)
+	}
+	idx := slices.Index(s.Trunks, name)
+	if idx < 0 {
+		return fmt.Errorf("'%s' is not a trunk", name)
+	}
+	for bName, b := range s.Branches {
+		if b.Parent == name {
+			return fmt.Errorf("branch '%s' is still rooted at '%s'", bName, name)
+		}
+	}
+
+	s.Trunks = slices.Delete(s.Trunks, idx, idx+1)
+	if err := state.Write(ctx, s); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	return printTrunks(s)
+}
+
+// printTrunks writes the primary and secondary trunks in the requested format.
+func printTrunks(s *state.State) error {
+	if jsonOut {
+		trunks := s.Trunks
+		if trunks == nil {
+			trunks = []string{}
+		}
+		return printJSON(trunkResult{
+			Trunk:  s.Trunk,
+			Trunks: trunks,
+		})
+	}
+	fmt.Printf("%s (primary)\n", s.Trunk)
+	for _, t := range s.Trunks {
+		fmt.Println(t)
+	}
+	return nil
+}
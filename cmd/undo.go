@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nvandessel/frond/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [snapshot-id]",
+	Short: "Revert the last change frond made to its state",
+	Long:  "Restore frond.json to how it was before the most recent recorded mutation. Run it again to step further back, or pass a snapshot id (as printed by sync) to restore that snapshot. Only frond's metadata is restored; git branches, commits and PRs are left as they are.",
+	Example: `  # Untracked the wrong branch? Put it back
+  frond undo
+
+  # Restore the snapshot taken before a sync
+  frond undo 20260101-120000.000000000
+
+  # Report what was restored
+  frond undo --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
+	unlock, err := state.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+	defer unlock()
+
+	if len(args) == 1 {
+		return restoreSnapshot(ctx, args[0])
+	}
+
+	entry, err := state.Undo(ctx)
+	if err != nil {
+		if errors.Is(err, state.ErrNothingToUndo) {
+			return fmt.Errorf("%w: no earlier state recorded in history", err)
+		}
+		return fmt.Errorf("undoing: %w", err)
+	}
+
+	fmt.Fprintln(os.Stderr, "warning: only frond metadata was restored; git branches were not changed")
+
+	if jsonOut {
+		return printJSON(undoResult{Undone: &historyEntry{
+			Time:    entry.Time,
+			Command: entry.Command,
+			Summary: entry.Summary,
+		}})
+	}
+	fmt.Printf("Undid '%s':\n", entry.Command)
+	for _, line := range entry.Summary {
+		fmt.Printf("    %s\n", line)
+	}
+	return nil
+}
+
+// restoreSnapshot swaps frond.json back to the snapshot id.
+func restoreSnapshot(ctx context.Context, id string) error {
+	if err := state.Restore(ctx, id); err != nil {
+		return fmt.Errorf("restoring snapshot: %w", err)
+	}
+
+	fmt.Fprintln(os.Stderr, "warning: only frond metadata was restored; git branches were not changed")
+
+	if jsonOut {
+		return printJSON(undoResult{Snapshot: id})
+	}
+	fmt.Printf("Restored snapshot %s\n", id)
+	return nil
+}
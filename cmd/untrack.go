@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
 
+	"github.com/nvandessel/frond/internal/gh"
 	"github.com/nvandessel/frond/internal/git"
 	"github.com/nvandessel/frond/internal/state"
 	"github.com/spf13/cobra"
@@ -15,18 +20,30 @@ var untrackCmd = &cobra.Command{
   frond untrack
 
   # Untrack a specific branch
-  frond untrack my-feature`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runUntrack,
+  frond untrack my-feature
+
+  # Short names work when unambiguous (pay/stripe-client)
+  frond untrack stripe-client
+
+  # Untrack every branch whose PR has merged
+  frond untrack --all-merged`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeTrackedBranches,
+	RunE:              runUntrack,
 }
 
 func init() {
+	untrackCmd.Flags().Bool("all-merged", false, "Untrack every branch whose PR has merged (fetches PR states)")
 	rootCmd.AddCommand(untrackCmd)
 }
 
 func runUntrack(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
+	if err := ensureNoRebase(ctx); err != nil {
+		return err
+	}
+
 	// 1. Lock state, defer unlock
 	unlock, err := state.Lock(ctx)
 	if err != nil {
@@ -40,10 +57,20 @@ func runUntrack(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("reading state: %w", err)
 	}
 
-	// 3. Resolve branch: arg or current branch
+	if allMerged, _ := cmd.Flags().GetBool("all-merged"); allMerged {
+		if len(args) > 0 {
+			return fmt.Errorf("--all-merged does not take a branch")
+		}
+		return untrackAllMerged(ctx, s)
+	}
+
+	// 3. Resolve branch: arg (full or unambiguous short name) or current branch
 	var name string
 	if len(args) > 0 {
-		name = args[0]
+		name, err = resolveTracked(s.Branches, args[0])
+		if err != nil {
+			return err
+		}
 	} else {
 		current, err := git.CurrentBranch(ctx)
 		if err != nil {
@@ -53,45 +80,12 @@ func runUntrack(cmd *cobra.Command, args []string) error {
 	}
 
 	// 4. Must be tracked
-	branch, tracked := s.Branches[name]
-	if !tracked {
+	if _, tracked := s.Branches[name]; !tracked {
 		return fmt.Errorf("branch '%s' is not tracked", name)
 	}
 
-	removedParent := branch.Parent
-
-	// 5. Remove from state.Branches
-	delete(s.Branches, name)
-
-	// 6. Remove from ALL other branches' after lists
-	// 7. Reparent children: any branch whose parent was this branch -> set parent to this branch's parent
-	var reparented []string
-	var unblocked []string
-
-	for bName, b := range s.Branches {
-		// Remove from after lists
-		newAfter := make([]string, 0, len(b.After))
-		wasInAfter := false
-		for _, dep := range b.After {
-			if dep == name {
-				wasInAfter = true
-			} else {
-				newAfter = append(newAfter, dep)
-			}
-		}
-		if wasInAfter {
-			unblocked = append(unblocked, bName)
-			b.After = newAfter
-		}
-
-		// Reparent children
-		if b.Parent == name {
-			b.Parent = removedParent
-			reparented = append(reparented, bName)
-		}
-
-		s.Branches[bName] = b
-	}
+	// 5-7. Remove from state, clean after lists, reparent children.
+	outcome := removeTracked(s, name)
 
 	// 8. Write state
 	if err := state.Write(ctx, s); err != nil {
@@ -99,6 +93,8 @@ func runUntrack(cmd *cobra.Command, args []string) error {
 	}
 
 	// 9. Output
+	reparented := slices.Sorted(maps.Keys(outcome.reparented))
+	unblocked := slices.Sorted(maps.Keys(outcome.unblocked))
 	if jsonOut {
 		if reparented == nil {
 			reparented = []string{}
@@ -113,16 +109,159 @@ func runUntrack(cmd *cobra.Command, args []string) error {
 		})
 	}
 	fmt.Printf("Untracked branch '%s'\n", name)
-	if len(reparented) > 0 {
-		for _, child := range reparented {
-			fmt.Printf("  Reparented '%s' to '%s'\n", child, removedParent)
+	for _, child := range reparented {
+		fmt.Printf("  Reparented '%s' to '%s'\n", child, outcome.reparented[child])
+	}
+	for _, dep := range unblocked {
+		fmt.Printf("  Removed '%s' from '%s' dependencies\n", name, dep)
+	}
+
+	return nil
+}
+
+// untrackAllMerged untracks every branch whose PR is MERGED and records it
+// as merged. They are removed in one removeTracked call, which reparents
+// each surviving child past all merged ancestors at once, so the order of
+// removal cannot leave a child pointing at another merged branch.
+func untrackAllMerged(ctx context.Context, s *state.State) error {
+	// One gh pr list call covers most PRs; any it misses (or all of them,
+	// if it fails) are viewed one by one.
+	var numbers []int
+	for _, b := range s.Branches {
+		if b.PR != nil {
+			numbers = append(numbers, *b.PR)
 		}
 	}
-	if len(unblocked) > 0 {
-		for _, dep := range unblocked {
-			fmt.Printf("  Removed '%s' from '%s' dependencies\n", name, dep)
+	var batch map[int]gh.PRInfo
+	if len(numbers) > 0 {
+		var err error
+		if batch, err = gh.PRStatesBatch(ctx, numbers); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not list PRs, checking them one by one: %v\n", err)
+		}
+	}
+
+	var merged []string
+	authors := make(map[string]string) // merged branch -> PR author login
+	for name, b := range s.Branches {
+		if b.PR == nil {
+			continue
+		}
+		info, ok := batch[*b.PR]
+		if !ok {
+			viewed, err := gh.PRView(ctx, *b.PR)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: could not check PR #%d for %s: %v\n", *b.PR, name, err)
+				continue
+			}
+			info = *viewed
+		}
+		if info.State == gh.PRStateMerged {
+			merged = append(merged, name)
+			authors[name] = info.Author
 		}
 	}
+	slices.Sort(merged)
 
+	for _, name := range merged {
+		s.RecordMerged(name, s.Branches[name], authors[name])
+	}
+	outcome := removeTracked(s, merged...)
+	if len(merged) > 0 {
+		if err := state.Write(ctx, s); err != nil {
+			return fmt.Errorf("writing state: %w", err)
+		}
+	}
+
+	if jsonOut {
+		if merged == nil {
+			merged = []string{}
+		}
+		return printJSON(untrackAllResult{
+			Untracked:  merged,
+			Reparented: outcome.reparented,
+		})
+	}
+	if len(merged) == 0 {
+		fmt.Println("no merged branches to untrack")
+		return nil
+	}
+	for _, name := range merged {
+		fmt.Printf("Untracked branch '%s'\n", name)
+	}
+	for _, child := range slices.Sorted(maps.Keys(outcome.reparented)) {
+		fmt.Printf("  Reparented '%s' to '%s'\n", child, outcome.reparented[child])
+	}
 	return nil
 }
+
+// untrackOutcome describes how removing branches changed the remaining state.
+type untrackOutcome struct {
+	reparented map[string]string   // child -> new parent
+	unblocked  map[string][]string // branch -> removed after deps
+}
+
+// removeTracked deletes names from s.Branches, drops them from every other
+// branch's after list, and reparents orphaned children to their nearest
+// surviving ancestor: parent links are walked upward past every branch being
+// removed until a remaining tracked branch or a trunk is found. This keeps
+// subtrees attached when a parent and grandparent are removed together.
+func removeTracked(s *state.State, names ...string) untrackOutcome {
+	removing := make(map[string]bool, len(names))
+	for _, n := range names {
+		removing[n] = true
+	}
+
+	outcome := untrackOutcome{
+		reparented: make(map[string]string),
+		unblocked:  make(map[string][]string),
+	}
+
+	for bName, b := range s.Branches {
+		if removing[bName] {
+			continue
+		}
+		if removing[b.Parent] {
+			b.Parent = survivingAncestor(s, removing, b.Parent)
+			outcome.reparented[bName] = b.Parent
+		}
+
+		newAfter := make([]string, 0, len(b.After))
+		for _, dep := range b.After {
+			if removing[dep] {
+				outcome.unblocked[bName] = append(outcome.unblocked[bName], dep)
+			} else {
+				newAfter = append(newAfter, dep)
+			}
+		}
+		if _, ok := outcome.unblocked[bName]; ok {
+			b.After = newAfter
+		}
+
+		s.Branches[bName] = b
+	}
+
+	for _, n := range names {
+		delete(s.Branches, n)
+	}
+	return outcome
+}
+
+// survivingAncestor returns the closest ancestor of name (inclusive) that is
+// not being removed. If the walk leaves tracked branches without reaching a
+// trunk (inconsistent state), it falls back to the primary trunk.
+func survivingAncestor(s *state.State, removing map[string]bool, name string) string {
+	seen := make(map[string]bool)
+	cur := name
+	for removing[cur] && !seen[cur] {
+		seen[cur] = true
+		b, ok := s.Branches[cur]
+		if !ok {
+			break
+		}
+		cur = b.Parent
+	}
+	if _, tracked := s.Branches[cur]; (tracked && !removing[cur]) || s.IsTrunk(cur) {
+		return cur
+	}
+	return s.Trunk
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/frond/internal/gh"
+	"github.com/nvandessel/frond/internal/state"
+)
+
+const (
+	// updateCheckRepo is the repo whose latest release is compared against version.
+	updateCheckRepo = "nvandessel/frond"
+	// updateCheckTTL is how long a looked-up release tag is reused.
+	updateCheckTTL = 24 * time.Hour
+	// updateCacheFile sits next to frond.json.
+	updateCacheFile = "frond-update-check.json"
+)
+
+// updateCheckWait bounds how long a finished command waits for a release
+// lookup still in flight. The default never waits: a lookup that has not
+// finished is reported by a later run from the cache. Tests raise it.
+var updateCheckWait time.Duration
+
+// pendingUpdate carries the latest release tag from startUpdateCheck to
+// reportUpdate. nil when the check is off.
+var pendingUpdate <-chan string
+
+// updateCache is the content of updateCacheFile.
+type updateCache struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"` // empty when the lookup failed
+}
+
+// startUpdateCheck begins the opt-in newer-release check. A cached tag
+// younger than updateCheckTTL is used as is; otherwise gh is asked in the
+// background so the command itself never waits on the network. It returns
+// nil when the check is disabled, under --json, or for dev builds.
+func startUpdateCheck(ctx context.Context) <-chan string {
+	if jsonOut || version == "dev" {
+		return nil
+	}
+	s, err := state.Read(ctx)
+	if err != nil {
+		return nil
+	}
+	if on, _ := strconv.ParseBool(s.Config[state.ConfigUpdateCheck]); !on {
+		return nil
+	}
+	p, err := state.Path(ctx)
+	if err != nil {
+		return nil
+	}
+	cachePath := filepath.Join(filepath.Dir(p), updateCacheFile)
+
+	ch := make(chan string, 1)
+	var cache updateCache
+	if data, err := os.ReadFile(cachePath); err == nil { //nolint:gosec // path is constructed internally from git common dir
+		if json.Unmarshal(data, &cache) == nil && time.Since(cache.CheckedAt) < updateCheckTTL {
+			ch <- cache.Latest
+			return ch
+		}
+	}
+
+	// Claim the lookup before starting it: if the command exits first and
+	// kills it, the next run within updateCheckTTL still does not ask again.
+	if data, err := json.Marshal(updateCache{CheckedAt: time.Now().UTC(), Latest: cache.Latest}); err == nil {
+		_ = os.WriteFile(cachePath, data, 0o644) //nolint:gosec // cache is not sensitive
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		// Failures are cached too, so an offline machine asks once a day.
+		tag, _ := gh.LatestRelease(ctx, updateCheckRepo)
+		if data, err := json.Marshal(updateCache{CheckedAt: time.Now().UTC(), Latest: tag}); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o644) //nolint:gosec // cache is not sensitive
+		}
+		ch <- tag
+	}()
+	return ch
+}
+
+// reportUpdate prints a one-line notice on stderr when the tag from ch is
+// newer than version. It returns at once if no tag has arrived yet (after
+// updateCheckWait).
+func reportUpdate(ch <-chan string) {
+	if ch == nil {
+		return
+	}
+	var latest string
+	select {
+	case latest = <-ch:
+	default:
+		if updateCheckWait <= 0 {
+			return
+		}
+		select {
+		case latest = <-ch:
+		case <-time.After(updateCheckWait):
+			return
+		}
+	}
+	if newerVersion(latest, version) {
+		fmt.Fprintf(os.Stderr, "notice: frond %s is available (you have %s): https://github.com/%s/releases/latest\n", latest, version, updateCheckRepo)
+	}
+}
+
+// newerVersion reports whether release tag latest is a higher
+// major.minor.patch than current. Unparsable versions are never newer.
+func newerVersion(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses "v1.2.3" or "1.2.3", ignoring a pre-release or build
+// suffix such as "-rc.1".
+func parseVersion(v string) ([3]int, bool) {
+	var out [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
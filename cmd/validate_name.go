@@ -0,0 +1,40 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var validateNameCmd = &cobra.Command{
+	Use:   "validate-name <name>",
+	Short: "Check whether a name is acceptable for 'frond new'",
+	Long:  "Apply the same branch-name rules as 'frond new' and 'frond track'. Exits 0 for a valid name, non-zero with the reason otherwise.",
+	Example: `  # Pre-check a name from a script
+  frond validate-name feature/login
+
+  # Machine-readable verdict
+  frond validate-name --json 'feature/../x'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runValidateName,
+}
+
+func init() {
+	rootCmd.AddCommand(validateNameCmd)
+}
+
+func runValidateName(cmd *cobra.Command, args []string) error {
+	err := validateBranchName(args[0])
+
+	if jsonOut {
+		res := validateNameResult{Valid: err == nil}
+		if err != nil {
+			res.Reason = err.Error()
+		}
+		if jsonErr := printJSON(res); jsonErr != nil {
+			return jsonErr
+		}
+		if err != nil {
+			// The verdict is already reported; only the exit code remains.
+			return &ExitError{Code: 1}
+		}
+		return nil
+	}
+	return err
+}
@@ -4,6 +4,7 @@
 package dag
 
 import (
+	"cmp"
 	"fmt"
 	"slices"
 	"strings"
@@ -13,6 +14,7 @@ import (
 type BranchInfo struct {
 	Parent string
 	After  []string
+	Seq    int // creation order; 0 when unknown
 }
 
 // ReadinessInfo is the computed status for a branch.
@@ -28,8 +30,16 @@ type JSONBranch struct {
 	Parent    string   `json:"parent"`
 	After     []string `json:"after"`
 	PR        *int     `json:"pr"`
+	Seq       int      `json:"seq,omitempty"`
 	Ready     bool     `json:"ready"`
-	BlockedBy []string `json:"blocked_by,omitempty"`
+	BlockedBy []string `json:"blocked_by"`
+}
+
+// EdgeList is the graph as flat edge lists: each parent edge is
+// [child, parent] and each after edge is [from, to] ("from" merges after "to").
+type EdgeList struct {
+	ParentEdges [][2]string `json:"parent_edges"`
+	AfterEdges  [][2]string `json:"after_edges"`
 }
 
 // DetectCycle checks if adding a new branch with the given after dependencies
@@ -124,8 +134,15 @@ func DetectCycle(branches map[string]BranchInfo, newName string, newAfter []stri
 
 // TopoSort performs a topological sort of branches based on the "after"
 // dependency edges. Returns branch names in dependency order (dependencies
-// first). Returns an error if a cycle is detected.
+// first), breaking ties alphabetically. Returns an error if a cycle is
+// detected.
 func TopoSort(branches map[string]BranchInfo) ([]string, error) {
+	return TopoSortFunc(branches, strings.Compare)
+}
+
+// TopoSortFunc is TopoSort with cmp deciding the order among branches that
+// are free to go at the same time.
+func TopoSortFunc(branches map[string]BranchInfo, cmp func(a, b string) int) ([]string, error) {
 	if len(branches) == 0 {
 		return nil, nil
 	}
@@ -156,7 +173,7 @@ func TopoSort(branches map[string]BranchInfo) ([]string, error) {
 			queue = append(queue, name)
 		}
 	}
-	slices.Sort(queue)
+	slices.SortFunc(queue, cmp)
 
 	var result []string
 	for len(queue) > 0 {
@@ -165,7 +182,7 @@ func TopoSort(branches map[string]BranchInfo) ([]string, error) {
 		result = append(result, node)
 
 		deps := dependents[node]
-		slices.Sort(deps)
+		slices.SortFunc(deps, cmp)
 		for _, dep := range deps {
 			inDegree[dep]--
 			if inDegree[dep] == 0 {
@@ -181,6 +198,15 @@ func TopoSort(branches map[string]BranchInfo) ([]string, error) {
 	return result, nil
 }
 
+// ByCreation returns a comparison function ordering branch names by creation
+// sequence, oldest first. Names are the tie-breaker, so branches recorded
+// before sequence numbers existed (Seq 0) come first, alphabetically.
+func ByCreation(branches map[string]BranchInfo) func(a, b string) int {
+	return func(a, b string) int {
+		return cmp.Or(cmp.Compare(branches[a].Seq, branches[b].Seq), strings.Compare(a, b))
+	}
+}
+
 // ComputeReadiness computes whether each branch is ready or blocked.
 // A branch is "ready" when its after list is empty OR all branches in after
 // are no longer tracked (not in the map). A branch is "blocked" when some
@@ -215,49 +241,137 @@ func ComputeReadiness(branches map[string]BranchInfo) []ReadinessInfo {
 	return result
 }
 
-// shortName returns the last segment of a branch name after the last '/'.
-func shortName(name string) string {
+// ShortName returns the last segment of a branch name after the last '/'.
+func ShortName(name string) string {
 	if idx := strings.LastIndex(name, "/"); idx >= 0 {
 		return name[idx+1:]
 	}
 	return name
 }
 
-// renderOpts controls optional rendering behavior.
-type renderOpts struct {
-	highlight string // branch name to mark with 👈
-	repoURL   string // when set, PR numbers become <a> links
+// LegendEntry describes one marker drawn by the tree renderer.
+type LegendEntry struct {
+	Key     string // stable machine name, e.g. "not_pushed"
+	Symbol  string // as drawn; "…" stands for variable text
+	Meaning string
+}
+
+// Legend lists the tree markers in the order they appear on a line. It is the
+// single description of their semantics for both humans and tools.
+var Legend = []LegendEntry{
+	{"pr", "#…", "number of the branch's pull request"},
+	{"not_pushed", "(not pushed)", "branch has no pull request yet"},
+	{"collapsed", "(+…)", "descendants hidden by --trunk-only-children"},
+	{"merged", "[merged]", "pull request merged; shown by --show-merged"},
+	{"highlight", "👈", "branch picked by --highlight"},
+	{"ready", "[ready]", "every --after dependency has merged"},
+	{"blocked", "[blocked: …]", "--after dependencies still tracked"},
+	{"truncated", "… (… more)", "branches hidden below --max-depth"},
+	{"base", "(base: …)", "pull request targets a fixed base set by 'frond set-base'"},
+	{"base_drift", "(base drift: …)", "pull request base on GitHub differs from the recorded parent"},
+	{"stale", "(stale: …)", "no commits within --stale; shows the age of the last one"},
+	{"since_sync", "*", "needs a push (tip moved since the last push) or a sync (trunk moved since the last sync), with --since-sync"},
+	{"subtree_commits", "(… commits)", "commits on the branch and everything stacked on it, with --count-commits"},
+}
+
+// RenderOptions controls optional tree rendering behavior.
+type RenderOptions struct {
+	Highlight string // branch name to mark with 👈
+	RepoURL   string // when set, PR numbers become <a> links
+	HidePR    bool   // omit PR numbers and "(not pushed)" markers
+	Collapse  bool   // show only the trunk's direct children, with "(+N)" hidden descendants
+	MaxDepth  int    // when > 0, hide branches deeper than this behind "… (N more)"
+
+	// Order sorts siblings; nil sorts them alphabetically.
+	Order func(a, b string) int
+
+	// Merged marks branches whose PRs have merged; they are drawn with a
+	// "[merged]" tag and no readiness.
+	Merged map[string]bool
+
+	// Notes holds extra per-branch annotations, drawn last on the line.
+	Notes map[string]string
+
+	// Details holds extra lines drawn under a branch, indented past the
+	// tree lines of its children.
+	Details map[string][]string
 }
 
 // RenderTree renders an ASCII tree showing the branch hierarchy based on
-// parent relationships. Annotations include PR numbers and readiness status.
+// parent relationships. Annotations include PR numbers and readiness status;
+// a nil prNumbers map omits the PR annotations.
 func RenderTree(trunk string, branches map[string]BranchInfo, prNumbers map[string]*int, readiness map[string]ReadinessInfo) string {
-	return renderTree(trunk, branches, prNumbers, readiness, renderOpts{})
+	return renderTree(trunk, branches, prNumbers, readiness, RenderOptions{})
+}
+
+// RenderTrees renders one tree per trunk, separated by a blank line. It is
+// used when a repo has secondary trunks (e.g. release branches) that each
+// root an independent stack.
+func RenderTrees(trunks []string, branches map[string]BranchInfo, prNumbers map[string]*int, readiness map[string]ReadinessInfo) string {
+	return renderTrees(trunks, branches, prNumbers, readiness, RenderOptions{})
 }
 
-func renderTree(trunk string, branches map[string]BranchInfo, prNumbers map[string]*int, readiness map[string]ReadinessInfo, opts renderOpts) string {
+// RenderTreesWith is RenderTrees with explicit rendering options.
+func RenderTreesWith(trunks []string, branches map[string]BranchInfo, prNumbers map[string]*int, readiness map[string]ReadinessInfo, opts RenderOptions) string {
+	return renderTrees(trunks, branches, prNumbers, readiness, opts)
+}
+
+func renderTrees(trunks []string, branches map[string]BranchInfo, prNumbers map[string]*int, readiness map[string]ReadinessInfo, opts RenderOptions) string {
+	trees := make([]string, len(trunks))
+	for i, trunk := range trunks {
+		trees[i] = renderTree(trunk, branches, prNumbers, readiness, opts)
+	}
+	return strings.Join(trees, "\n")
+}
+
+// RootOf walks parent links from name until it reaches a parent that is not
+// in branches, and returns that parent — the trunk the branch is rooted at.
+// A cycle in parent links stops the walk at the first repeated branch.
+func RootOf(branches map[string]BranchInfo, name string) string {
+	seen := make(map[string]bool)
+	cur := name
+	for {
+		info, ok := branches[cur]
+		if !ok || seen[cur] {
+			return cur
+		}
+		seen[cur] = true
+		cur = info.Parent
+	}
+}
+
+func renderTree(trunk string, branches map[string]BranchInfo, prNumbers map[string]*int, readiness map[string]ReadinessInfo, opts RenderOptions) string {
 	// Build children map from parent relationships
 	children := make(map[string][]string)
 	for name, info := range branches {
 		children[info.Parent] = append(children[info.Parent], name)
 	}
 
-	// Sort children alphabetically
+	// Sort children alphabetically unless another order was asked for
 	for p := range children {
-		slices.Sort(children[p])
+		if opts.Order != nil {
+			slices.SortFunc(children[p], opts.Order)
+		} else {
+			slices.Sort(children[p])
+		}
 	}
 
 	var sb strings.Builder
 	sb.WriteString(trunk)
 	sb.WriteString("\n")
 
-	renderChildren(&sb, trunk, children, prNumbers, readiness, "", opts)
+	renderChildren(&sb, trunk, children, prNumbers, readiness, "", 1, opts)
 
 	return sb.String()
 }
 
-func renderChildren(sb *strings.Builder, node string, children map[string][]string, prNumbers map[string]*int, readiness map[string]ReadinessInfo, prefix string, opts renderOpts) {
+func renderChildren(sb *strings.Builder, node string, children map[string][]string, prNumbers map[string]*int, readiness map[string]ReadinessInfo, prefix string, depth int, opts RenderOptions) {
 	kids := children[node]
+	if opts.MaxDepth > 0 && depth > opts.MaxDepth && len(kids) > 0 {
+		sb.WriteString(prefix)
+		sb.WriteString(fmt.Sprintf("└── … (%d more)\n", countDescendants(node, children)))
+		return
+	}
 	for i, child := range kids {
 		isLast := i == len(kids)-1
 
@@ -270,11 +384,18 @@ func renderChildren(sb *strings.Builder, node string, children map[string][]stri
 		sb.WriteString(connector)
 		sb.WriteString(child)
 
+		// Collapsed subtree size
+		if opts.Collapse {
+			if n := countDescendants(child, children); n > 0 {
+				sb.WriteString(fmt.Sprintf(" (+%d)", n))
+			}
+		}
+
 		// PR number
-		if prNumbers != nil {
+		if prNumbers != nil && !opts.HidePR {
 			if pr, ok := prNumbers[child]; ok && pr != nil {
-				if opts.repoURL != "" {
-					sb.WriteString(fmt.Sprintf("  <a href=\"%s/pull/%d\">#%d</a>", opts.repoURL, *pr, *pr))
+				if opts.RepoURL != "" {
+					sb.WriteString(fmt.Sprintf("  <a href=\"%s/pull/%d\">#%d</a>", opts.RepoURL, *pr, *pr))
 				} else {
 					sb.WriteString(fmt.Sprintf("  #%d", *pr))
 				}
@@ -283,8 +404,12 @@ func renderChildren(sb *strings.Builder, node string, children map[string][]stri
 			}
 		}
 
+		if opts.Merged[child] {
+			sb.WriteString("  [merged]")
+		}
+
 		// Highlight marker
-		if opts.highlight != "" && child == opts.highlight {
+		if opts.Highlight != "" && child == opts.Highlight {
 			sb.WriteString("  👈")
 		}
 
@@ -296,23 +421,52 @@ func renderChildren(sb *strings.Builder, node string, children map[string][]stri
 				} else if len(ri.BlockedBy) > 0 {
 					short := make([]string, len(ri.BlockedBy))
 					for j, dep := range ri.BlockedBy {
-						short[j] = shortName(dep)
+						short[j] = ShortName(dep)
 					}
 					sb.WriteString(fmt.Sprintf("  [blocked: %s]", strings.Join(short, ", ")))
 				}
 			}
 		}
 
+		if note := opts.Notes[child]; note != "" {
+			sb.WriteString("  ")
+			sb.WriteString(note)
+		}
+
 		sb.WriteString("\n")
 
 		childPrefix := prefix + "│   "
 		if isLast {
 			childPrefix = prefix + "    "
 		}
-		renderChildren(sb, child, children, prNumbers, readiness, childPrefix, opts)
+		if details := opts.Details[child]; len(details) > 0 {
+			detailPrefix := childPrefix + "    "
+			if len(children[child]) > 0 && !opts.Collapse {
+				detailPrefix = childPrefix + "│   "
+			}
+			for _, line := range details {
+				sb.WriteString(detailPrefix)
+				sb.WriteString(line)
+				sb.WriteString("\n")
+			}
+		}
+
+		if opts.Collapse {
+			continue
+		}
+		renderChildren(sb, child, children, prNumbers, readiness, childPrefix, depth+1, opts)
 	}
 }
 
+// countDescendants returns how many branches sit below node in the tree.
+func countDescendants(node string, children map[string][]string) int {
+	n := 0
+	for _, child := range children[node] {
+		n += 1 + countDescendants(child, children)
+	}
+	return n
+}
+
 // CommentMarker is the HTML comment used to identify frond stack comments
 // on GitHub PRs. Used by both rendering (here) and upsert detection (cmd).
 const CommentMarker = "<!-- frond-stack -->"
@@ -323,7 +477,7 @@ const CommentMarker = "<!-- frond-stack -->"
 // tree is wrapped in <pre> tags instead of a code fence.
 // Returns a markdown string wrapped with the frond-stack marker.
 func RenderStackComment(trunk string, branches map[string]BranchInfo, prNumbers map[string]*int, readiness map[string]ReadinessInfo, highlight string, repoURL string) string {
-	tree := renderTree(trunk, branches, prNumbers, readiness, renderOpts{highlight: highlight, repoURL: repoURL})
+	tree := renderTree(trunk, branches, prNumbers, readiness, RenderOptions{Highlight: highlight, RepoURL: repoURL})
 
 	var sb strings.Builder
 	sb.WriteString(CommentMarker + "\n")
@@ -345,7 +499,7 @@ func RenderMergedStackComment(trunk string, branches map[string]BranchInfo, prNu
 	sb.WriteString(fmt.Sprintf("**%s** has been merged. :tada:\n\n", mergedBranch))
 
 	if len(branches) > 0 {
-		tree := renderTree(trunk, branches, prNumbers, readiness, renderOpts{repoURL: repoURL})
+		tree := renderTree(trunk, branches, prNumbers, readiness, RenderOptions{RepoURL: repoURL})
 		sb.WriteString("Remaining stack:\n")
 		sb.WriteString("<pre>\n")
 		sb.WriteString(tree)
@@ -370,7 +524,7 @@ func RenderJSON(trunk string, branches map[string]BranchInfo, prNumbers map[stri
 	}
 	slices.Sort(names)
 
-	var result []JSONBranch
+	result := make([]JSONBranch, 0, len(names))
 	for _, name := range names {
 		info := branches[name]
 		ri := readinessMap[name]
@@ -379,6 +533,7 @@ func RenderJSON(trunk string, branches map[string]BranchInfo, prNumbers map[stri
 			Name:      name,
 			Parent:    info.Parent,
 			After:     info.After,
+			Seq:       info.Seq,
 			Ready:     ri.Ready,
 			BlockedBy: ri.BlockedBy,
 		}
@@ -386,6 +541,9 @@ func RenderJSON(trunk string, branches map[string]BranchInfo, prNumbers map[stri
 		if jb.After == nil {
 			jb.After = []string{}
 		}
+		if jb.BlockedBy == nil {
+			jb.BlockedBy = []string{}
+		}
 
 		if prNumbers != nil {
 			if pr, ok := prNumbers[name]; ok {
@@ -398,3 +556,278 @@ func RenderJSON(trunk string, branches map[string]BranchInfo, prNumbers map[stri
 
 	return result
 }
+
+// RenderEdges returns the graph as edge lists, sorted for deterministic
+// output. It complements RenderJSON (node-centric) for tools that prefer
+// edges.
+func RenderEdges(branches map[string]BranchInfo) EdgeList {
+	names := make([]string, 0, len(branches))
+	for name := range branches {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	edges := EdgeList{
+		ParentEdges: [][2]string{},
+		AfterEdges:  [][2]string{},
+	}
+	for _, name := range names {
+		info := branches[name]
+		edges.ParentEdges = append(edges.ParentEdges, [2]string{name, info.Parent})
+
+		after := slices.Clone(info.After)
+		slices.Sort(after)
+		for _, dep := range after {
+			edges.AfterEdges = append(edges.AfterEdges, [2]string{name, dep})
+		}
+	}
+	return edges
+}
+
+// exportGraph is the graph as drawn by RenderDOT and RenderMermaid: every
+// branch and trunk within the depth limit, plus one summary node per
+// visible branch whose children were cut off.
+type exportGraph struct {
+	nodes   []string       // branches and trunks, sorted
+	parents [][2]string    // [child, parent]
+	after   [][2]string    // [from, to]; only between visible branches
+	hidden  map[string]int // visible branch -> number of branches hidden below it
+	depth   map[string]int // 1 for direct children of a trunk
+}
+
+func buildExportGraph(branches map[string]BranchInfo, depthLimit int) exportGraph {
+	children := make(map[string][]string)
+	for name, info := range branches {
+		children[info.Parent] = append(children[info.Parent], name)
+	}
+
+	g := exportGraph{hidden: make(map[string]int), depth: make(map[string]int)}
+	var walk func(node string, depth int)
+	walk = func(node string, depth int) {
+		if depthLimit > 0 && depth == depthLimit {
+			if n := countDescendants(node, children); n > 0 {
+				g.hidden[node] = n
+			}
+			return
+		}
+		for _, child := range children[node] {
+			if _, seen := g.depth[child]; seen {
+				continue
+			}
+			g.depth[child] = depth + 1
+			walk(child, depth+1)
+		}
+	}
+	trunks := make(map[string]bool)
+	for _, info := range branches {
+		if _, ok := branches[info.Parent]; !ok {
+			trunks[info.Parent] = true
+		}
+	}
+	for trunk := range trunks {
+		g.nodes = append(g.nodes, trunk)
+		walk(trunk, 0)
+	}
+	for name := range g.depth {
+		g.nodes = append(g.nodes, name)
+	}
+	slices.Sort(g.nodes)
+
+	for _, name := range g.nodes {
+		if _, ok := g.depth[name]; !ok {
+			continue
+		}
+		info := branches[name]
+		g.parents = append(g.parents, [2]string{name, info.Parent})
+		after := slices.Clone(info.After)
+		slices.Sort(after)
+		for _, dep := range after {
+			if _, ok := g.depth[dep]; ok {
+				g.after = append(g.after, [2]string{name, dep})
+			}
+		}
+	}
+	return g
+}
+
+// RenderDOT renders the graph in Graphviz DOT. Parent edges point from child
+// to parent; after edges are dashed. When depthLimit > 0, branches deeper
+// than depthLimit levels below a trunk are folded into a "+N hidden" node.
+func RenderDOT(branches map[string]BranchInfo, depthLimit int) string {
+	g := buildExportGraph(branches, depthLimit)
+
+	var sb strings.Builder
+	sb.WriteString("digraph frond {\n")
+	sb.WriteString("  rankdir=BT;\n")
+	for _, name := range g.nodes {
+		if _, ok := g.depth[name]; !ok {
+			fmt.Fprintf(&sb, "  %q [shape=box];\n", name)
+		} else {
+			fmt.Fprintf(&sb, "  %q;\n", name)
+		}
+	}
+	for _, e := range g.parents {
+		fmt.Fprintf(&sb, "  %q -> %q;\n", e[0], e[1])
+	}
+	for _, e := range g.after {
+		fmt.Fprintf(&sb, "  %q -> %q [style=dashed];\n", e[0], e[1])
+	}
+	for _, name := range g.nodes {
+		if n := g.hidden[name]; n > 0 {
+			id := name + " (hidden)"
+			fmt.Fprintf(&sb, "  %q [label=\"+%d hidden\", style=dotted];\n", id, n)
+			fmt.Fprintf(&sb, "  %q -> %q;\n", id, name)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// RenderMermaid renders the graph as a Mermaid flowchart, with the same
+// edges and depth limit as RenderDOT. Nodes get generated ids since branch
+// names may contain characters Mermaid does not accept in ids.
+func RenderMermaid(branches map[string]BranchInfo, depthLimit int) string {
+	g := buildExportGraph(branches, depthLimit)
+
+	ids := make(map[string]string, len(g.nodes))
+	var sb strings.Builder
+	sb.WriteString("graph BT\n")
+	for i, name := range g.nodes {
+		ids[name] = fmt.Sprintf("n%d", i)
+		if _, ok := g.depth[name]; !ok {
+			fmt.Fprintf(&sb, "  %s[[\"%s\"]]\n", ids[name], name)
+		} else {
+			fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[name], name)
+		}
+	}
+	for _, e := range g.parents {
+		fmt.Fprintf(&sb, "  %s --> %s\n", ids[e[0]], ids[e[1]])
+	}
+	for _, e := range g.after {
+		fmt.Fprintf(&sb, "  %s -.-> %s\n", ids[e[0]], ids[e[1]])
+	}
+	for _, name := range g.nodes {
+		if n := g.hidden[name]; n > 0 {
+			fmt.Fprintf(&sb, "  %sh([\"+%d hidden\"]) --> %s\n", ids[name], n, ids[name])
+		}
+	}
+	return sb.String()
+}
+
+// Roots returns the branches whose parent is not itself a branch — the
+// direct children of a trunk, one per independent stack — sorted by name.
+func Roots(branches map[string]BranchInfo) []string {
+	roots := []string{}
+	for name, info := range branches {
+		if _, ok := branches[info.Parent]; !ok {
+			roots = append(roots, name)
+		}
+	}
+	slices.Sort(roots)
+	return roots
+}
+
+// Kinds of Problem reported by Validate.
+const (
+	ProblemMissingParent = "missing_parent" // parent is neither a branch nor a trunk
+	ProblemMissingAfter  = "missing_after"  // an after entry is not a branch
+	ProblemParentCycle   = "parent_cycle"   // parent links loop back
+	ProblemAfterCycle    = "after_cycle"    // after dependencies loop back
+	ProblemOrderCycle    = "order_cycle"    // parent links and after dependencies together loop back
+)
+
+// Problem is one broken invariant found by Validate.
+type Problem struct {
+	Kind   string   `json:"kind"` // one of the Problem* constants
+	Branch string   `json:"branch"`
+	Ref    string   `json:"ref,omitempty"`   // the unresolved name, for missing_*
+	Cycle  []string `json:"cycle,omitempty"` // the loop, first name repeated last, for *_cycle
+}
+
+func (p Problem) String() string {
+	switch p.Kind {
+	case ProblemMissingParent:
+		return fmt.Sprintf("'%s' has unknown parent '%s'", p.Branch, p.Ref)
+	case ProblemMissingAfter:
+		return fmt.Sprintf("'%s' is after '%s', which is not tracked", p.Branch, p.Ref)
+	case ProblemParentCycle:
+		return "parent cycle: " + strings.Join(p.Cycle, " -> ")
+	case ProblemOrderCycle:
+		return "parent/after cycle: " + strings.Join(p.Cycle, " -> ")
+	default:
+		return "after cycle: " + strings.Join(p.Cycle, " -> ")
+	}
+}
+
+// Validate checks the graph invariants: every parent is a branch or one of
+// trunks, every after entry is a branch, and neither parent links, after
+// dependencies, nor the two combined form a cycle (a branch stacked on
+// something that must land after it). Problems are sorted by branch; at
+// most one after cycle is reported, and a combined cycle only when there is
+// no other.
+func Validate(branches map[string]BranchInfo, trunks ...string) []Problem {
+	names := make([]string, 0, len(branches))
+	for name := range branches {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var problems []Problem
+	inCycle := make(map[string]bool)
+	for _, name := range names {
+		info := branches[name]
+		if _, ok := branches[info.Parent]; !ok && !slices.Contains(trunks, info.Parent) {
+			problems = append(problems, Problem{Kind: ProblemMissingParent, Branch: name, Ref: info.Parent})
+		}
+		for _, dep := range info.After {
+			if _, ok := branches[dep]; !ok {
+				problems = append(problems, Problem{Kind: ProblemMissingAfter, Branch: name, Ref: dep})
+			}
+		}
+
+		// Walk up from name; revisiting a branch on this walk is a loop.
+		var path []string
+		onPath := make(map[string]int)
+		for cur := name; !inCycle[cur]; cur = branches[cur].Parent {
+			if _, ok := branches[cur]; !ok {
+				break
+			}
+			if i, seen := onPath[cur]; seen {
+				cycle := append(slices.Clone(path[i:]), cur)
+				for _, c := range path[i:] {
+					inCycle[c] = true
+				}
+				problems = append(problems, Problem{Kind: ProblemParentCycle, Branch: cur, Cycle: cycle})
+				break
+			}
+			onPath[cur] = len(path)
+			path = append(path, cur)
+		}
+	}
+
+	afterCycle := false
+	if len(names) > 0 {
+		if cycle, found := DetectCycle(branches, names[0], branches[names[0]].After); found {
+			problems = append(problems, Problem{Kind: ProblemAfterCycle, Branch: cycle[0], Cycle: cycle})
+			afterCycle = true
+		}
+	}
+	if len(names) > 0 && len(inCycle) == 0 && !afterCycle {
+		// A parent must land before its child, so it counts as an after edge.
+		combined := make(map[string]BranchInfo, len(branches))
+		for name, info := range branches {
+			if _, ok := branches[info.Parent]; ok {
+				info.After = append(slices.Clone(info.After), info.Parent)
+			}
+			combined[name] = info
+		}
+		if cycle, found := DetectCycle(combined, names[0], combined[names[0]].After); found {
+			problems = append(problems, Problem{Kind: ProblemOrderCycle, Branch: cycle[0], Cycle: cycle})
+		}
+	}
+
+	slices.SortStableFunc(problems, func(a, b Problem) int {
+		return cmp.Compare(a.Branch, b.Branch)
+	})
+	return problems
+}
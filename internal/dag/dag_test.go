@@ -1,6 +1,8 @@
 package dag
 
 import (
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -106,6 +108,24 @@ func TestTopoSort_LinearChain(t *testing.T) {
 	}
 }
 
+func TestTopoSortFunc_TieBreak(t *testing.T) {
+	// Independent branches follow cmp; dependencies still come first.
+	branches := map[string]BranchInfo{
+		"a": {After: []string{"c"}},
+		"b": {},
+		"c": {},
+	}
+	reverse := func(x, y string) int { return strings.Compare(y, x) }
+	result, err := TopoSortFunc(branches, reverse)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"c", "b", "a"}
+	if !equalSlice(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
 func TestTopoSort_Diamond(t *testing.T) {
 	// D after B and C, B after A, C after A => A first, then B and C, then D
 	branches := map[string]BranchInfo{
@@ -434,6 +454,100 @@ func TestRenderTree_NotPushed(t *testing.T) {
 	}
 }
 
+func TestRenderTree_HidePR(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"feature/x": {Parent: "main"},
+		"feature/y": {Parent: "main"},
+	}
+	prNumbers := map[string]*int{
+		"feature/x": intPtr(42),
+		"feature/y": nil,
+	}
+	readiness := map[string]ReadinessInfo{
+		"feature/x": {Name: "feature/x", Ready: true},
+		"feature/y": {Name: "feature/y", Ready: true},
+	}
+
+	want := "main\n├── feature/x  [ready]\n└── feature/y  [ready]\n"
+	if got := RenderTreesWith([]string{"main"}, branches, prNumbers, readiness, RenderOptions{HidePR: true}); got != want {
+		t.Errorf("HidePR render:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if got := RenderTree("main", branches, nil, readiness); got != want {
+		t.Errorf("nil prNumbers render:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderTree_Collapse(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"feature/auth": {Parent: "main"},
+		"auth/login":   {Parent: "feature/auth"},
+		"auth/signup":  {Parent: "feature/auth"},
+		"auth/e2e":     {Parent: "auth/login"},
+		"fix/typo":     {Parent: "main"},
+	}
+
+	want := "main\n├── feature/auth (+3)\n└── fix/typo\n"
+	got := RenderTreesWith([]string{"main"}, branches, nil, nil, RenderOptions{Collapse: true})
+	if got != want {
+		t.Errorf("collapsed render:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderTree_Details(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"a": {Parent: "main"},
+		"b": {Parent: "a"},
+		"c": {Parent: "main"},
+	}
+	details := map[string][]string{
+		"a": {"a1", "a2"},
+		"b": {"b1"},
+		"c": {"c1"},
+	}
+
+	want := "main\n├── a\n│   │   a1\n│   │   a2\n│   └── b\n│           b1\n└── c\n        c1\n"
+	got := RenderTreesWith([]string{"main"}, branches, nil, nil, RenderOptions{Details: details})
+	if got != want {
+		t.Errorf("render with details:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderTree_MaxDepth(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"a":   {Parent: "main"},
+		"b":   {Parent: "a"},
+		"c":   {Parent: "b"},
+		"c2":  {Parent: "b"},
+		"d":   {Parent: "c"},
+		"top": {Parent: "main"},
+	}
+
+	want := "main\n├── a\n│   └── b\n│       └── … (3 more)\n└── top\n"
+	got := RenderTreesWith([]string{"main"}, branches, nil, nil, RenderOptions{MaxDepth: 2})
+	if got != want {
+		t.Errorf("max depth render:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderTree_CreationOrder(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"a":      {Parent: "main", Seq: 3},
+		"b":      {Parent: "main", Seq: 1},
+		"legacy": {Parent: "main"},
+	}
+
+	want := "main\n├── legacy\n├── b\n└── a\n"
+	got := RenderTreesWith([]string{"main"}, branches, nil, nil, RenderOptions{Order: ByCreation(branches)})
+	if got != want {
+		t.Errorf("creation order render:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	jb := RenderJSON("main", branches, nil)
+	if jb[0].Name != "a" || jb[0].Seq != 3 {
+		t.Errorf("RenderJSON()[0] = %+v, want a with seq 3", jb[0])
+	}
+}
+
 func TestRenderTree_BlockedAnnotation(t *testing.T) {
 	branches := map[string]BranchInfo{
 		"pay/api-handlers": {Parent: "main"},
@@ -553,6 +667,51 @@ func TestRenderTree_BoxDrawing(t *testing.T) {
 	}
 }
 
+func TestRenderTrees_MultipleTrunks(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"feat":     {Parent: "main"},
+		"backport": {Parent: "release/1.x"},
+	}
+	prNumbers := map[string]*int{
+		"feat":     intPtr(1),
+		"backport": intPtr(2),
+	}
+
+	result := RenderTrees([]string{"main", "release/1.x"}, branches, prNumbers, nil)
+
+	want := "main\n└── feat  #1\n\nrelease/1.x\n└── backport  #2\n"
+	if result != want {
+		t.Errorf("RenderTrees =\n%s\nwant:\n%s", result, want)
+	}
+}
+
+func TestRootOf(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"a":        {Parent: "main"},
+		"a-child":  {Parent: "a"},
+		"backport": {Parent: "release/1.x"},
+		"loop-a":   {Parent: "loop-b"},
+		"loop-b":   {Parent: "loop-a"},
+	}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"a-child", "main"},
+		{"a", "main"},
+		{"backport", "release/1.x"},
+		{"main", "main"},
+		{"loop-a", "loop-a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RootOf(branches, tt.name); got != tt.want {
+				t.Errorf("RootOf(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
 // ─── RenderJSON Tests ───────────────────────────────────────────────────────
 
 func TestRenderJSON_AllFields(t *testing.T) {
@@ -620,6 +779,12 @@ func TestRenderJSON_EmptyAfter(t *testing.T) {
 	if len(result[0].After) != 0 {
 		t.Errorf("expected empty After, got %v", result[0].After)
 	}
+	if result[0].BlockedBy == nil {
+		t.Error("expected non-nil BlockedBy slice")
+	}
+	if got := RenderJSON("main", map[string]BranchInfo{}, nil); got == nil {
+		t.Error("expected an empty slice for no branches, not nil")
+	}
 }
 
 func TestRenderJSON_BlockedByComputed(t *testing.T) {
@@ -859,3 +1024,168 @@ func equalSlice(a, b []string) bool {
 	}
 	return true
 }
+
+// ─── RenderEdges Tests ──────────────────────────────────────────────────────
+
+func TestRenderEdges(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"b": {Parent: "a", After: []string{"c", "a"}},
+		"a": {Parent: "main"},
+		"c": {Parent: "main", After: []string{}},
+	}
+
+	edges := RenderEdges(branches)
+
+	wantParent := [][2]string{{"a", "main"}, {"b", "a"}, {"c", "main"}}
+	if !reflect.DeepEqual(edges.ParentEdges, wantParent) {
+		t.Errorf("ParentEdges = %v, want %v", edges.ParentEdges, wantParent)
+	}
+	wantAfter := [][2]string{{"b", "a"}, {"b", "c"}}
+	if !reflect.DeepEqual(edges.AfterEdges, wantAfter) {
+		t.Errorf("AfterEdges = %v, want %v", edges.AfterEdges, wantAfter)
+	}
+}
+
+func TestRenderEdges_EmptyIsArrays(t *testing.T) {
+	edges := RenderEdges(map[string]BranchInfo{})
+	if edges.ParentEdges == nil || edges.AfterEdges == nil {
+		t.Error("RenderEdges should return empty arrays, not nil")
+	}
+}
+
+func TestRenderDOT(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"a": {Parent: "main"},
+		"b": {Parent: "a"},
+		"c": {Parent: "b"},
+		"d": {Parent: "main", After: []string{"a", "c"}},
+	}
+
+	got := RenderDOT(branches, 0)
+	want := `digraph frond {
+  rankdir=BT;
+  "a";
+  "b";
+  "c";
+  "d";
+  "main" [shape=box];
+  "a" -> "main";
+  "b" -> "a";
+  "c" -> "b";
+  "d" -> "main";
+  "d" -> "a" [style=dashed];
+  "d" -> "c" [style=dashed];
+}
+`
+	if got != want {
+		t.Errorf("RenderDOT =\n%s\nwant\n%s", got, want)
+	}
+
+	// Depth 1 folds b and c; the after edge to c goes with them.
+	got = RenderDOT(branches, 1)
+	want = `digraph frond {
+  rankdir=BT;
+  "a";
+  "d";
+  "main" [shape=box];
+  "a" -> "main";
+  "d" -> "main";
+  "d" -> "a" [style=dashed];
+  "a (hidden)" [label="+2 hidden", style=dotted];
+  "a (hidden)" -> "a";
+}
+`
+	if got != want {
+		t.Errorf("RenderDOT depth 1 =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderMermaid(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"pay/a": {Parent: "main"},
+		"pay/b": {Parent: "pay/a"},
+		"pay/c": {Parent: "pay/b"},
+		"other": {Parent: "main", After: []string{"pay/a"}},
+	}
+
+	got := RenderMermaid(branches, 2)
+	want := `graph BT
+  n0[["main"]]
+  n1["other"]
+  n2["pay/a"]
+  n3["pay/b"]
+  n1 --> n0
+  n2 --> n0
+  n3 --> n2
+  n1 -.-> n2
+  n3h(["+1 hidden"]) --> n3
+`
+	if got != want {
+		t.Errorf("RenderMermaid =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRoots(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"b": {Parent: "a"},
+		"a": {Parent: "main"},
+		"c": {Parent: "main"},
+		"r": {Parent: "release"},
+	}
+
+	got := Roots(branches)
+	want := []string{"a", "c", "r"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Roots = %v, want %v", got, want)
+	}
+	if got := Roots(map[string]BranchInfo{}); got == nil {
+		t.Error("Roots should return an empty slice, not nil")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	branches := map[string]BranchInfo{
+		"a":    {Parent: "main"},
+		"r":    {Parent: "release"},
+		"lost": {Parent: "gone", After: []string{"a", "ghost"}},
+		"x":    {Parent: "y"},
+		"y":    {Parent: "x"},
+		"hang": {Parent: "x"},
+		"p":    {Parent: "main", After: []string{"q"}},
+		"q":    {Parent: "main", After: []string{"p"}},
+	}
+
+	got := Validate(branches, "main", "release")
+	want := []Problem{
+		{Kind: ProblemMissingParent, Branch: "lost", Ref: "gone"},
+		{Kind: ProblemMissingAfter, Branch: "lost", Ref: "ghost"},
+		{Kind: ProblemAfterCycle, Branch: "p", Cycle: []string{"p", "q", "p"}},
+		{Kind: ProblemParentCycle, Branch: "x", Cycle: []string{"x", "y", "x"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := Validate(map[string]BranchInfo{"a": {Parent: "main"}}, "main"); len(got) != 0 {
+		t.Errorf("Validate of a sound graph = %v, want none", got)
+	}
+	if s := want[3].String(); s != "parent cycle: x -> y -> x" {
+		t.Errorf("Problem.String() = %q", s)
+	}
+
+	// a stacked on b while b waits for a can never land.
+	mixed := map[string]BranchInfo{
+		"a": {Parent: "b"},
+		"b": {Parent: "main", After: []string{"a"}},
+	}
+	got = Validate(mixed, "main")
+	if len(got) != 1 || got[0].Kind != ProblemOrderCycle || !slices.Contains(got[0].Cycle, "a") || !slices.Contains(got[0].Cycle, "b") {
+		t.Errorf("Validate of a parent/after cycle = %+v, want one order_cycle", got)
+	}
+	sound := map[string]BranchInfo{
+		"a": {Parent: "main"},
+		"b": {Parent: "a", After: []string{"a"}},
+	}
+	if got := Validate(sound, "main"); len(got) != 0 {
+		t.Errorf("Validate of a branch after its own parent = %v, want none", got)
+	}
+}
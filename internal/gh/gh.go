@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
@@ -19,6 +20,84 @@ type PRInfo struct {
 	Number      int    `json:"number"`
 	State       string `json:"state"`
 	BaseRefName string `json:"baseRefName"`
+	HeadRefName string `json:"headRefName"`
+	URL         string `json:"url"`
+	Author      string `json:"author"`    // login of the PR author
+	Checks      string `json:"checks"`    // rollup of CI checks: one of the Checks* constants
+	Mergeable   string `json:"mergeable"` // MERGEABLE, CONFLICTING or UNKNOWN (still computing)
+}
+
+// Check rollup values for PRInfo.Checks.
+const (
+	ChecksNone    = ""        // no checks reported
+	ChecksPassing = "PASSING" // every check succeeded (or was skipped)
+	ChecksPending = "PENDING" // nothing failed, but some checks have not finished
+	ChecksFailing = "FAILING" // at least one check failed
+)
+
+// prViewFields is the --json field list requested from gh pr view.
+const prViewFields = "number,state,baseRefName,headRefName,url,author,statusCheckRollup,mergeable"
+
+// checkJSON is one entry of gh's statusCheckRollup: a CheckRun (status and
+// conclusion) or a commit StatusContext (state).
+type checkJSON struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	State      string `json:"state"`
+}
+
+// rollupChecks reduces statusCheckRollup entries to a Checks* value.
+func rollupChecks(checks []checkJSON) string {
+	if len(checks) == 0 {
+		return ChecksNone
+	}
+	pending := false
+	for _, c := range checks {
+		result := c.Conclusion
+		if c.State != "" {
+			result = c.State
+		} else if c.Status != "COMPLETED" {
+			result = "PENDING"
+		}
+		switch result {
+		case "SUCCESS", "NEUTRAL", "SKIPPED":
+		case "PENDING", "EXPECTED":
+			pending = true
+		default: // FAILURE, ERROR, CANCELLED, TIMED_OUT, ACTION_REQUIRED, ...
+			return ChecksFailing
+		}
+	}
+	if pending {
+		return ChecksPending
+	}
+	return ChecksPassing
+}
+
+// prViewJSON mirrors gh's pr view JSON, where author is an object.
+type prViewJSON struct {
+	Number      int    `json:"number"`
+	State       string `json:"state"`
+	BaseRefName string `json:"baseRefName"`
+	HeadRefName string `json:"headRefName"`
+	URL         string `json:"url"`
+	Author      struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	StatusCheckRollup []checkJSON `json:"statusCheckRollup"`
+	Mergeable         string      `json:"mergeable"`
+}
+
+func (v prViewJSON) info() PRInfo {
+	return PRInfo{
+		Number:      v.Number,
+		State:       v.State,
+		BaseRefName: v.BaseRefName,
+		HeadRefName: v.HeadRefName,
+		URL:         v.URL,
+		Author:      v.Author.Login,
+		Checks:      rollupChecks(v.StatusCheckRollup),
+		Mergeable:   v.Mergeable,
+	}
 }
 
 // GHError is returned when the gh CLI exits with a non-zero status.
@@ -29,6 +108,9 @@ type GHError struct {
 }
 
 func (e *GHError) Error() string {
+	if errors.Is(e.Err, ErrNotAuthenticated) {
+		return ErrNotAuthenticated.Error()
+	}
 	return fmt.Sprintf("gh %s: %s", strings.Join(e.Args, " "), strings.TrimSpace(e.Stderr))
 }
 
@@ -36,8 +118,24 @@ func (e *GHError) Unwrap() error {
 	return e.Err
 }
 
+// ErrNotAuthenticated is wrapped by the *GHError returned when gh has no
+// usable login, so callers can test for it with errors.Is.
+var ErrNotAuthenticated = errors.New("gh is not authenticated; run 'gh auth login'")
+
+// ErrPRExists is wrapped by the error PRCreate returns when the head branch
+// already has an open PR. PRCreate then also returns that PR's number.
+var ErrPRExists = errors.New("a pull request already exists")
+
+// isAuthError reports whether gh's stderr says it needs a login.
+func isAuthError(stderr string) bool {
+	return strings.Contains(stderr, "gh auth login") ||
+		strings.Contains(stderr, "not logged into any") ||
+		strings.Contains(stderr, "HTTP 401")
+}
+
 // run executes gh with the given arguments and returns trimmed stdout.
-// On failure it returns a *GHError containing stderr.
+// On failure it returns a *GHError containing stderr; authentication
+// failures wrap ErrNotAuthenticated.
 func run(ctx context.Context, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, "gh", args...)
 	var stdout, stderr bytes.Buffer
@@ -45,6 +143,9 @@ func run(ctx context.Context, args ...string) (string, error) {
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
+		if isAuthError(stderr.String()) {
+			err = ErrNotAuthenticated
+		}
 		return "", &GHError{
 			Args:   args,
 			Stderr: stderr.String(),
@@ -54,6 +155,39 @@ func run(ctx context.Context, args ...string) (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// repo is the upstream repo, as "owner/name", that every PR and comment
+// call targets instead of the one gh infers from the working copy. The CLI
+// sets it once per invocation via SetRepo.
+var repo string
+
+// SetRepo makes subsequent calls target r ("owner/name"); "" restores gh's
+// default of the current repo.
+func SetRepo(r string) {
+	repo = r
+}
+
+// Repo returns the repo set with SetRepo, or "" if none.
+func Repo() string {
+	return repo
+}
+
+// prArgs appends -R for the configured repo to a gh pr command.
+func prArgs(args ...string) []string {
+	if repo != "" {
+		args = append(args, "-R", repo)
+	}
+	return args
+}
+
+// apiRepo returns the REST path of the configured repo, or gh's
+// {owner}/{repo} placeholder for the current one.
+func apiRepo() string {
+	if repo != "" {
+		return "repos/" + repo
+	}
+	return "repos/{owner}/{repo}"
+}
+
 // Available checks whether the gh CLI is installed and accessible.
 // It returns a descriptive error if not found.
 func Available() error {
@@ -64,6 +198,13 @@ func Available() error {
 	return nil
 }
 
+// AuthStatus checks that gh is logged in. It returns an error wrapping
+// ErrNotAuthenticated when it is not.
+func AuthStatus(ctx context.Context) error {
+	_, err := run(ctx, "auth", "status")
+	return err
+}
+
 // PRCreateOpts configures the gh pr create command.
 type PRCreateOpts struct {
 	Base  string // Target branch (--base)
@@ -71,24 +212,56 @@ type PRCreateOpts struct {
 	Title string // PR title (-t)
 	Body  string // PR body (-b)
 	Draft bool   // Create as draft PR (--draft)
+
+	// HeadRepo is the fork Head lives in, as "owner" or "owner/name". When
+	// set, the head is passed as owner:branch.
+	HeadRepo string
+	// Labels are added to the new PR (--label, once each).
+	Labels []string
+	// Repo is the upstream repo to open the PR in, as "owner/name" (-R).
+	// Empty falls back to SetRepo, then to gh's current repo.
+	Repo string
 }
 
 // PRCreate creates a pull request and returns the new PR number.
 // gh pr create outputs a URL like https://github.com/owner/repo/pull/123.
 func PRCreate(ctx context.Context, opts PRCreateOpts) (int, error) {
+	head := opts.Head
+	if opts.HeadRepo != "" {
+		owner, _, _ := strings.Cut(opts.HeadRepo, "/")
+		head = owner + ":" + opts.Head
+	}
 	args := []string{
 		"pr", "create",
 		"--base", opts.Base,
-		"--head", opts.Head,
+		"--head", head,
 		"-t", opts.Title,
 		"-b", opts.Body,
 	}
 	if opts.Draft {
 		args = append(args, "--draft")
 	}
+	for _, l := range opts.Labels {
+		args = append(args, "--label", l)
+	}
+	if opts.Repo != "" {
+		args = append(args, "-R", opts.Repo)
+	} else {
+		args = prArgs(args...)
+	}
 
 	out, err := run(ctx, args...)
 	if err != nil {
+		// gh names the existing PR: 'a pull request for branch "x" into
+		// branch "y" already exists:' followed by its URL.
+		var ghErr *GHError
+		if errors.As(err, &ghErr) && strings.Contains(ghErr.Stderr, "already exists") {
+			fields := strings.Fields(ghErr.Stderr)
+			url := fields[len(fields)-1]
+			if n, convErr := strconv.Atoi(url[strings.LastIndex(url, "/")+1:]); convErr == nil {
+				return n, fmt.Errorf("%w: #%d", ErrPRExists, n)
+			}
+		}
 		return 0, err
 	}
 
@@ -108,21 +281,103 @@ func PRCreate(ctx context.Context, opts PRCreateOpts) (int, error) {
 
 // PRView retrieves metadata about a pull request by number.
 func PRView(ctx context.Context, prNumber int) (*PRInfo, error) {
-	out, err := run(ctx, "pr", "view", strconv.Itoa(prNumber), "--json", "number,state,baseRefName")
+	out, err := run(ctx, prArgs("pr", "view", strconv.Itoa(prNumber), "--json", prViewFields)...)
 	if err != nil {
 		return nil, err
 	}
 
-	var info PRInfo
-	if err := json.Unmarshal([]byte(out), &info); err != nil {
+	var raw prViewJSON
+	if err := json.Unmarshal([]byte(out), &raw); err != nil {
 		return nil, fmt.Errorf("parsing pr view output: %w", err)
 	}
+	info := raw.info()
 	return &info, nil
 }
 
+// PRStatesBatch looks up many PRs with a single gh pr list call and
+// returns those in prNumbers, keyed by number. Only Number, State, Author,
+// BaseRefName and HeadRefName are filled in. PRs beyond the newest
+// prListLimit are missing from the result; callers fall back to PRView.
+func PRStatesBatch(ctx context.Context, prNumbers []int) (map[int]PRInfo, error) {
+	out, err := run(ctx, prArgs("pr", "list", "--state", "all",
+		"--limit", strconv.Itoa(prListLimit),
+		"--json", "number,state,author,baseRefName,headRefName")...)
+	if err != nil {
+		return nil, err
+	}
+	var raw []prViewJSON
+	if err := json.Unmarshal([]byte(out), &raw); err != nil {
+		return nil, fmt.Errorf("parsing pr list output: %w", err)
+	}
+	want := make(map[int]bool, len(prNumbers))
+	for _, n := range prNumbers {
+		want[n] = true
+	}
+	infos := make(map[int]PRInfo, len(prNumbers))
+	for _, r := range raw {
+		if want[r.Number] {
+			infos[r.Number] = r.info()
+		}
+	}
+	return infos, nil
+}
+
+// PRSummary is one entry from PRList.
+type PRSummary struct {
+	Number      int    `json:"number"`
+	HeadRefName string `json:"headRefName"`
+	BaseRefName string `json:"baseRefName"`
+	Body        string `json:"body"`
+}
+
+// prListLimit caps how many PRs PRList asks gh for.
+const prListLimit = 1000
+
+// PRList returns the repository's open pull requests, including their bodies.
+func PRList(ctx context.Context) ([]PRSummary, error) {
+	out, err := run(ctx, prArgs("pr", "list", "--state", "open",
+		"--limit", strconv.Itoa(prListLimit),
+		"--json", "number,headRefName,baseRefName,body")...)
+	if err != nil {
+		return nil, err
+	}
+	var prs []PRSummary
+	if err := json.Unmarshal([]byte(out), &prs); err != nil {
+		return nil, fmt.Errorf("parsing pr list output: %w", err)
+	}
+	return prs, nil
+}
+
 // PREdit updates the base branch of a pull request.
 func PREdit(ctx context.Context, prNumber int, newBase string) error {
-	_, err := run(ctx, "pr", "edit", strconv.Itoa(prNumber), "--base", newBase)
+	_, err := run(ctx, prArgs("pr", "edit", strconv.Itoa(prNumber), "--base", newBase)...)
+	return err
+}
+
+// PRBody returns the body of a pull request.
+func PRBody(ctx context.Context, prNumber int) (string, error) {
+	out, err := run(ctx, prArgs("pr", "view", strconv.Itoa(prNumber), "--json", "body")...)
+	if err != nil {
+		return "", err
+	}
+	var raw struct {
+		Body string `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(out), &raw); err != nil {
+		return "", fmt.Errorf("parsing pr view output: %w", err)
+	}
+	return raw.Body, nil
+}
+
+// PREditBody replaces the body of a pull request.
+func PREditBody(ctx context.Context, prNumber int, body string) error {
+	_, err := run(ctx, prArgs("pr", "edit", strconv.Itoa(prNumber), "--body", body)...)
+	return err
+}
+
+// PRAddLabels adds labels to a pull request, keeping any it already has.
+func PRAddLabels(ctx context.Context, prNumber int, labels []string) error {
+	_, err := run(ctx, prArgs("pr", "edit", strconv.Itoa(prNumber), "--add-label", strings.Join(labels, ","))...)
 	return err
 }
 
@@ -132,13 +387,27 @@ type Comment struct {
 	Body string `json:"body"`
 }
 
+// PR merge methods accepted by PRMerge.
+const (
+	MergeMethodMerge  = "merge"
+	MergeMethodSquash = "squash"
+	MergeMethodRebase = "rebase"
+)
+
+// PRMerge merges a pull request with the given method (one of the
+// MergeMethod* constants).
+func PRMerge(ctx context.Context, prNumber int, method string) error {
+	_, err := run(ctx, prArgs("pr", "merge", strconv.Itoa(prNumber), "--"+method)...)
+	return err
+}
+
 // PRCommentList returns all comments on a pull request.
 // Uses --paginate to handle PRs with many comments. The gh CLI outputs each
 // page as a separate JSON array when paginating, so we decode them one at a
 // time and merge into a single slice.
 func PRCommentList(ctx context.Context, prNumber int) ([]Comment, error) {
 	out, err := run(ctx, "api", "--paginate",
-		fmt.Sprintf("repos/{owner}/{repo}/issues/%d/comments", prNumber))
+		fmt.Sprintf("%s/issues/%d/comments", apiRepo(), prNumber))
 	if err != nil {
 		return nil, err
 	}
@@ -164,7 +433,7 @@ func PRCommentList(ctx context.Context, prNumber int) ([]Comment, error) {
 // PRCommentCreate creates a new comment on a pull request.
 func PRCommentCreate(ctx context.Context, prNumber int, body string) error {
 	_, err := run(ctx, "api",
-		fmt.Sprintf("repos/{owner}/{repo}/issues/%d/comments", prNumber),
+		fmt.Sprintf("%s/issues/%d/comments", apiRepo(), prNumber),
 		"-f", "body="+body)
 	return err
 }
@@ -172,11 +441,48 @@ func PRCommentCreate(ctx context.Context, prNumber int, body string) error {
 // PRCommentUpdate updates an existing comment by ID.
 func PRCommentUpdate(ctx context.Context, commentID int, body string) error {
 	_, err := run(ctx, "api", "-X", "PATCH",
-		fmt.Sprintf("repos/{owner}/{repo}/issues/comments/%d", commentID),
+		fmt.Sprintf("%s/issues/comments/%d", apiRepo(), commentID),
 		"-f", "body="+body)
 	return err
 }
 
+// CurrentUser returns the login of the authenticated gh user.
+func CurrentUser(ctx context.Context) (string, error) {
+	out, err := run(ctx, "api", "user")
+	if err != nil {
+		return "", err
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.Unmarshal([]byte(out), &user); err != nil {
+		return "", fmt.Errorf("parsing api user output: %w", err)
+	}
+	if user.Login == "" {
+		return "", fmt.Errorf("gh api user returned no login")
+	}
+	return user.Login, nil
+}
+
+// LatestRelease returns the tag name of the latest release of repo
+// ("owner/name").
+func LatestRelease(ctx context.Context, repo string) (string, error) {
+	out, err := run(ctx, "api", "repos/"+repo+"/releases/latest")
+	if err != nil {
+		return "", err
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.Unmarshal([]byte(out), &release); err != nil {
+		return "", fmt.Errorf("parsing latest release output: %w", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("gh api returned no release tag for %s", repo)
+	}
+	return release.TagName, nil
+}
+
 // PR state constants returned by the GitHub API.
 const (
 	PRStateOpen   = "OPEN"
@@ -177,6 +177,31 @@ func TestPRCreate(t *testing.T) {
 	}
 }
 
+func TestSetRepo(t *testing.T) {
+	recordFile := setupFakeGH(t)
+	ctx := context.Background()
+	SetRepo("acme/widgets")
+	t.Cleanup(func() { SetRepo("") })
+
+	if _, err := PRView(ctx, 7); err != nil {
+		t.Fatalf("PRView() error: %v", err)
+	}
+	if err := PRCommentCreate(ctx, 7, "hi"); err != nil {
+		t.Fatalf("PRCommentCreate() error: %v", err)
+	}
+
+	calls := readRecord(t, recordFile)
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d: %v", len(calls), calls)
+	}
+	if !strings.HasSuffix(calls[0], "-R acme/widgets") {
+		t.Errorf("pr view call = %q, want -R acme/widgets", calls[0])
+	}
+	if !strings.Contains(calls[1], "repos/acme/widgets/issues/7/comments") {
+		t.Errorf("comment call = %q, want the upstream repo path", calls[1])
+	}
+}
+
 func TestPRCreate_Draft(t *testing.T) {
 	recordFile := setupFakeGH(t)
 	ctx := context.Background()
@@ -200,6 +225,46 @@ func TestPRCreate_Draft(t *testing.T) {
 	}
 }
 
+func TestPRCreate_Fork(t *testing.T) {
+	recordFile := setupFakeGH(t)
+	ctx := context.Background()
+
+	num, err := PRCreate(ctx, PRCreateOpts{
+		Base: "main", Head: "feature/foo", Title: "My PR", Body: "Some body",
+		HeadRepo: "alice/repo", Repo: "upstream/repo",
+	})
+	if err != nil {
+		t.Fatalf("PRCreate() error: %v", err)
+	}
+	if num != 42 {
+		t.Fatalf("PRCreate() = %d, want 42", num)
+	}
+
+	call := readRecord(t, recordFile)[0]
+	if !strings.Contains(call, "--head alice:feature/foo") {
+		t.Fatalf("expected '--head alice:feature/foo' in call, got: %s", call)
+	}
+	if !strings.Contains(call, "-R upstream/repo") {
+		t.Fatalf("expected '-R upstream/repo' in call, got: %s", call)
+	}
+}
+
+func TestPRCreate_Exists(t *testing.T) {
+	_ = setupFakeGH(t)
+	t.Setenv("FAKEGH_PR_EXISTS", "17")
+	ctx := context.Background()
+
+	num, err := PRCreate(ctx, PRCreateOpts{
+		Base: "main", Head: "feature/foo", Title: "My PR", Body: "Some body",
+	})
+	if !errors.Is(err, ErrPRExists) {
+		t.Fatalf("PRCreate() error = %v, want ErrPRExists", err)
+	}
+	if num != 17 {
+		t.Fatalf("PRCreate() = %d, want 17", num)
+	}
+}
+
 func TestPRView(t *testing.T) {
 	_ = setupFakeGH(t)
 	ctx := context.Background()
@@ -217,6 +282,15 @@ func TestPRView(t *testing.T) {
 	if info.BaseRefName != "main" {
 		t.Fatalf("PRView().BaseRefName = %q, want main", info.BaseRefName)
 	}
+	if info.HeadRefName != "feature" {
+		t.Fatalf("PRView().HeadRefName = %q, want feature", info.HeadRefName)
+	}
+	if info.URL != "https://github.com/test/repo/pull/42" {
+		t.Fatalf("PRView().URL = %q, want https://github.com/test/repo/pull/42", info.URL)
+	}
+	if info.Author != "octocat" {
+		t.Fatalf("PRView().Author = %q, want octocat", info.Author)
+	}
 }
 
 func TestPREdit(t *testing.T) {
@@ -244,6 +318,31 @@ func TestPREdit(t *testing.T) {
 	}
 }
 
+func TestPRBody(t *testing.T) {
+	_ = setupFakeGH(t)
+	t.Setenv("FAKEGH_PR_BODY", "Adds things.\n\nFrond-Parent: main")
+
+	body, err := PRBody(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("PRBody() error: %v", err)
+	}
+	if body != "Adds things.\n\nFrond-Parent: main" {
+		t.Errorf("PRBody() = %q", body)
+	}
+}
+
+func TestPREditBody(t *testing.T) {
+	recordFile := setupFakeGH(t)
+
+	if err := PREditBody(context.Background(), 42, "new body"); err != nil {
+		t.Fatalf("PREditBody() error: %v", err)
+	}
+	calls := readRecord(t, recordFile)
+	if len(calls) != 1 || calls[0] != "pr edit 42 --body new body" {
+		t.Errorf("gh calls = %v, want pr edit 42 --body new body", calls)
+	}
+}
+
 func TestPRState(t *testing.T) {
 	_ = setupFakeGH(t)
 	ctx := context.Background()
@@ -291,6 +390,28 @@ func TestPRView_Error(t *testing.T) {
 	}
 }
 
+func TestPRLabels(t *testing.T) {
+	recordFile := setupFakeGH(t)
+	ctx := context.Background()
+
+	if _, err := PRCreate(ctx, PRCreateOpts{
+		Base: "main", Head: "pay/api", Title: "t", Body: "b", Labels: []string{"area/pay", "urgent"},
+	}); err != nil {
+		t.Fatalf("PRCreate() error: %v", err)
+	}
+	if err := PRAddLabels(ctx, 42, []string{"area/pay", "urgent"}); err != nil {
+		t.Fatalf("PRAddLabels() error: %v", err)
+	}
+
+	calls := readRecord(t, recordFile)
+	if !strings.Contains(calls[0], "--label area/pay --label urgent") {
+		t.Errorf("expected one --label per label, got: %s", calls[0])
+	}
+	if !strings.Contains(calls[1], "pr edit 42 --add-label area/pay,urgent") {
+		t.Errorf("expected --add-label, got: %s", calls[1])
+	}
+}
+
 func TestPREdit_Error(t *testing.T) {
 	_ = setupFailingGH(t)
 	ctx := context.Background()
@@ -459,3 +580,194 @@ func TestPRCommentUpdate_Error(t *testing.T) {
 		t.Fatalf("expected *GHError, got %T: %v", err, err)
 	}
 }
+
+func TestCurrentUser(t *testing.T) {
+	_ = setupFakeGH(t)
+	t.Setenv("FAKEGH_USER", "alice")
+
+	login, err := CurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("CurrentUser() error: %v", err)
+	}
+	if login != "alice" {
+		t.Errorf("CurrentUser() = %q, want alice", login)
+	}
+}
+
+func TestCurrentUser_Error(t *testing.T) {
+	_ = setupFailingGH(t)
+
+	if _, err := CurrentUser(context.Background()); err == nil {
+		t.Fatal("CurrentUser() should return error when gh fails")
+	}
+}
+
+func TestPRStatesBatch(t *testing.T) {
+	recordFile := setupFakeGH(t)
+	t.Setenv("FAKEGH_PR_LIST", `[
+		{"number": 1, "state": "MERGED", "author": {"login": "alice"}, "baseRefName": "main", "headRefName": "a"},
+		{"number": 2, "state": "OPEN", "author": {"login": "bob"}, "baseRefName": "a", "headRefName": "b"},
+		{"number": 9, "state": "CLOSED", "author": {"login": "carol"}, "baseRefName": "main", "headRefName": "z"}
+	]`)
+
+	infos, err := PRStatesBatch(context.Background(), []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("PRStatesBatch() error: %v", err)
+	}
+	if len(infos) != 2 || infos[1].State != PRStateMerged || infos[1].Author != "alice" || infos[2].HeadRefName != "b" {
+		t.Errorf("PRStatesBatch() = %+v, want PRs 1 and 2 only", infos)
+	}
+
+	calls := readRecord(t, recordFile)
+	if len(calls) != 1 || !strings.HasPrefix(calls[0], "pr list --state all") {
+		t.Errorf("gh calls = %v, want a single pr list", calls)
+	}
+}
+
+func TestPRStatesBatch_Error(t *testing.T) {
+	_ = setupFailingGH(t)
+
+	if _, err := PRStatesBatch(context.Background(), []int{1}); err == nil {
+		t.Fatal("PRStatesBatch() should return error when gh fails")
+	}
+}
+
+func TestPRList(t *testing.T) {
+	recordFile := setupFakeGH(t)
+	t.Setenv("FAKEGH_PR_LIST", `[{"number": 5, "headRefName": "feat", "baseRefName": "main", "body": "Frond-Parent: main"}]`)
+
+	prs, err := PRList(context.Background())
+	if err != nil {
+		t.Fatalf("PRList() error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 5 || prs[0].HeadRefName != "feat" || prs[0].Body != "Frond-Parent: main" {
+		t.Errorf("PRList() = %+v", prs)
+	}
+
+	calls := readRecord(t, recordFile)
+	if len(calls) != 1 || !strings.HasPrefix(calls[0], "pr list --state open") {
+		t.Errorf("gh calls = %v, want a single pr list", calls)
+	}
+}
+
+func TestPRList_Error(t *testing.T) {
+	_ = setupFailingGH(t)
+
+	if _, err := PRList(context.Background()); err == nil {
+		t.Fatal("PRList() should return error when gh fails")
+	}
+}
+
+func TestPRView_NotAuthenticated(t *testing.T) {
+	_ = setupFakeGH(t)
+	t.Setenv("FAKEGH_UNAUTHENTICATED", "1")
+
+	_, err := PRView(context.Background(), 1)
+	if !errors.Is(err, ErrNotAuthenticated) {
+		t.Fatalf("PRView() error = %v, want ErrNotAuthenticated", err)
+	}
+	var ghErr *GHError
+	if !errors.As(err, &ghErr) {
+		t.Errorf("PRView() error should still be a *GHError, got %T", err)
+	}
+	if !strings.Contains(err.Error(), "gh auth login") {
+		t.Errorf("error message = %q, want a hint to run gh auth login", err.Error())
+	}
+}
+
+func TestAuthStatus(t *testing.T) {
+	_ = setupFakeGH(t)
+	if err := AuthStatus(context.Background()); err != nil {
+		t.Fatalf("AuthStatus() error: %v", err)
+	}
+
+	t.Setenv("FAKEGH_UNAUTHENTICATED", "1")
+	if err := AuthStatus(context.Background()); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("AuthStatus() error = %v, want ErrNotAuthenticated", err)
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	recordFile := setupFakeGH(t)
+	t.Setenv("FAKEGH_LATEST_RELEASE", "v1.4.0")
+
+	tag, err := LatestRelease(context.Background(), "nvandessel/frond")
+	if err != nil {
+		t.Fatalf("LatestRelease() error: %v", err)
+	}
+	if tag != "v1.4.0" {
+		t.Errorf("LatestRelease() = %q, want v1.4.0", tag)
+	}
+	call := readRecord(t, recordFile)[0]
+	if !strings.Contains(call, "api repos/nvandessel/frond/releases/latest") {
+		t.Errorf("unexpected call: %s", call)
+	}
+}
+
+func TestLatestRelease_Error(t *testing.T) {
+	setupFakeGH(t)
+	t.Setenv("FAKEGH_FAIL_API", "1")
+
+	if _, err := LatestRelease(context.Background(), "nvandessel/frond"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRollupChecks(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []checkJSON
+		want   string
+	}{
+		{"none", nil, ChecksNone},
+		{"passing", []checkJSON{{Status: "COMPLETED", Conclusion: "SUCCESS"}, {Status: "COMPLETED", Conclusion: "SKIPPED"}, {State: "SUCCESS"}}, ChecksPassing},
+		{"pending run", []checkJSON{{Status: "COMPLETED", Conclusion: "SUCCESS"}, {Status: "QUEUED"}}, ChecksPending},
+		{"pending status", []checkJSON{{State: "PENDING"}}, ChecksPending},
+		{"failing wins", []checkJSON{{Status: "IN_PROGRESS"}, {Status: "COMPLETED", Conclusion: "TIMED_OUT"}}, ChecksFailing},
+		{"status error", []checkJSON{{State: "ERROR"}}, ChecksFailing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rollupChecks(tt.checks); got != tt.want {
+				t.Errorf("rollupChecks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPRView_Checks(t *testing.T) {
+	setupFakeGH(t)
+	t.Setenv("FAKEGH_CHECKS", "42=FAILURE")
+
+	info, err := PRView(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("PRView() error: %v", err)
+	}
+	if info.Checks != ChecksFailing {
+		t.Errorf("Checks = %q, want %q", info.Checks, ChecksFailing)
+	}
+	if info.Mergeable != "MERGEABLE" {
+		t.Errorf("Mergeable = %q, want MERGEABLE", info.Mergeable)
+	}
+}
+
+func TestPRMerge(t *testing.T) {
+	recordFile := setupFakeGH(t)
+
+	if err := PRMerge(context.Background(), 7, MergeMethodSquash); err != nil {
+		t.Fatalf("PRMerge() error: %v", err)
+	}
+	call := readRecord(t, recordFile)[0]
+	if !strings.Contains(call, "pr merge 7 --squash") {
+		t.Errorf("unexpected call: %s", call)
+	}
+}
+
+func TestPRMerge_Error(t *testing.T) {
+	setupFakeGH(t)
+	t.Setenv("FAKEGH_FAIL", "1")
+
+	if err := PRMerge(context.Background(), 7, MergeMethodMerge); err == nil {
+		t.Fatal("expected error")
+	}
+}
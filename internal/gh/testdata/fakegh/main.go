@@ -3,10 +3,13 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // nextPRNumber returns an incrementing PR number when FAKEGH_PR_COUNTER is
@@ -27,6 +30,40 @@ func nextPRNumber() int {
 	return n
 }
 
+// checkRollup returns the statusCheckRollup JSON for a PR. FAKEGH_CHECKS
+// maps PR numbers to a CheckRun conclusion, e.g. "1=SUCCESS,2=FAILURE";
+// PENDING reports an in-progress run. Unlisted PRs have no checks.
+func checkRollup(prNum string) string {
+	for entry := range strings.SplitSeq(os.Getenv("FAKEGH_CHECKS"), ",") {
+		n, result, ok := strings.Cut(entry, "=")
+		if !ok || n != prNum {
+			continue
+		}
+		if result == "PENDING" {
+			return `[{"__typename": "CheckRun", "status": "IN_PROGRESS", "conclusion": ""}]`
+		}
+		return fmt.Sprintf(`[{"__typename": "CheckRun", "status": "COMPLETED", "conclusion": "%s"}]`, result)
+	}
+	return "[]"
+}
+
+// pendingView reports whether this pr view should still show checks as
+// pending: FAKEGH_PENDING_VIEWS=n makes the first n views (counted in the
+// FAKEGH_VIEW_COUNTER file) report an in-progress check.
+func pendingView() bool {
+	limit, err := strconv.Atoi(os.Getenv("FAKEGH_PENDING_VIEWS"))
+	counterFile := os.Getenv("FAKEGH_VIEW_COUNTER")
+	if err != nil || counterFile == "" {
+		return false
+	}
+	n := 0
+	if data, err := os.ReadFile(counterFile); err == nil {
+		n, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+	}
+	os.WriteFile(counterFile, []byte(strconv.Itoa(n+1)+"\n"), 0o644) //nolint:errcheck
+	return n < limit
+}
+
 // handleAPI handles "gh api" subcommands for comment operations.
 func handleAPI(args []string) {
 	// Fail mode for API-only: if FAKEGH_FAIL_API is set, exit non-zero.
@@ -57,6 +94,30 @@ func handleAPI(args []string) {
 		}
 	}
 
+	// Authenticated user.
+	if endpoint == "user" {
+		login := "octocat"
+		if u := os.Getenv("FAKEGH_USER"); u != "" {
+			login = u
+		}
+		fmt.Printf("{\"login\": \"%s\"}\n", login)
+		return
+	}
+
+	// Latest release: FAKEGH_LATEST_RELEASE sets the tag and
+	// FAKEGH_RELEASE_DELAY (a duration) slows the lookup down.
+	if strings.HasSuffix(endpoint, "/releases/latest") {
+		if d, err := time.ParseDuration(os.Getenv("FAKEGH_RELEASE_DELAY")); err == nil {
+			time.Sleep(d)
+		}
+		tag := "v0.0.0"
+		if v := os.Getenv("FAKEGH_LATEST_RELEASE"); v != "" {
+			tag = v
+		}
+		fmt.Printf("{\"tag_name\": \"%s\"}\n", tag)
+		return
+	}
+
 	// Update comment: PATCH to /issues/comments/{id}.
 	if strings.Contains(endpoint, "/issues/comments/") && method == "PATCH" {
 		fmt.Println(`{}`)
@@ -103,6 +164,12 @@ func main() {
 		}
 	}
 
+	// Unauthenticated mode: mimic gh with no login.
+	if os.Getenv("FAKEGH_UNAUTHENTICATED") != "" && (len(args) == 0 || args[0] != "--version") {
+		fmt.Fprintln(os.Stderr, "To get started with GitHub CLI, please run:  gh auth login")
+		os.Exit(4)
+	}
+
 	// Fail mode: if FAKEGH_FAIL is set, exit non-zero.
 	if os.Getenv("FAKEGH_FAIL") != "" {
 		fmt.Fprintln(os.Stderr, "fatal: something went wrong")
@@ -117,21 +184,64 @@ func main() {
 	if len(args) >= 2 && args[0] == "pr" {
 		switch args[1] {
 		case "create":
+			// FAKEGH_PR_EXISTS=n makes gh refuse because PR n is already open.
+			if n := os.Getenv("FAKEGH_PR_EXISTS"); n != "" {
+				head := args[slices.Index(args, "--head")+1]
+				fmt.Fprintf(os.Stderr, "a pull request for branch %q into branch \"main\" already exists:\nhttps://github.com/test/repo/pull/%s\n", head, n)
+				os.Exit(1)
+			}
 			n := nextPRNumber()
-			fmt.Printf("https://github.com/test/repo/pull/%d\n", n)
+			// -R names the repo the PR is opened in (fork workflows).
+			repo := "test/repo"
+			if i := slices.Index(args, "-R"); i >= 0 && i+1 < len(args) {
+				repo = args[i+1]
+			}
+			fmt.Printf("https://github.com/%s/pull/%d\n", repo, n)
 		case "view":
 			// Parse the requested PR number from args.
 			prNum := "42"
 			if len(args) > 2 && !strings.HasPrefix(args[2], "-") {
 				prNum = args[2]
 			}
+			// --json body asks for the body alone: FAKEGH_PR_BODY.
+			if i := slices.Index(args, "--json"); i >= 0 && i+1 < len(args) && args[i+1] == "body" {
+				body, _ := json.Marshal(os.Getenv("FAKEGH_PR_BODY"))
+				fmt.Printf("{\"body\": %s}\n", body)
+				break
+			}
 			prState := "OPEN"
 			if s := os.Getenv("FAKEGH_PR_STATE"); s != "" {
 				prState = s
 			}
-			fmt.Printf("{\"number\": %s, \"state\": \"%s\", \"baseRefName\": \"main\"}\n", prNum, prState)
-		case "edit":
+			// FAKEGH_MERGED_PRS lists PR numbers reported as MERGED.
+			if slices.Contains(strings.Split(os.Getenv("FAKEGH_MERGED_PRS"), ","), prNum) {
+				prState = "MERGED"
+			}
+			author := "octocat"
+			if a := os.Getenv("FAKEGH_PR_AUTHOR"); a != "" {
+				author = a
+			}
+			head := "feature"
+			if h := os.Getenv("FAKEGH_PR_HEAD"); h != "" {
+				head = h
+			}
+			checks := checkRollup(prNum)
+			if pendingView() {
+				checks = `[{"__typename": "CheckRun", "status": "IN_PROGRESS", "conclusion": ""}]`
+			}
+			mergeable := "MERGEABLE"
+			if m := os.Getenv("FAKEGH_MERGEABLE"); m != "" {
+				mergeable = m
+			}
+			fmt.Printf("{\"number\": %s, \"state\": \"%s\", \"baseRefName\": \"main\", \"headRefName\": \"%s\", \"url\": \"https://github.com/test/repo/pull/%s\", \"author\": {\"login\": \"%s\"}, \"statusCheckRollup\": %s, \"mergeable\": \"%s\"}\n", prNum, prState, head, prNum, author, checks, mergeable)
+		case "edit", "merge":
 			// no output
+		case "list":
+			list := "[]"
+			if l := os.Getenv("FAKEGH_PR_LIST"); l != "" {
+				list = l
+			}
+			fmt.Println(list)
 		}
 		os.Exit(0)
 	}
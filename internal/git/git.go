@@ -7,8 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GitError represents a failure from a git command invocation.
@@ -26,6 +30,9 @@ func (e *GitError) Unwrap() error {
 	return e.Err
 }
 
+// ErrNoRebaseInProgress is returned by RebaseAbort when there is no rebase to abort.
+var ErrNoRebaseInProgress = errors.New("no rebase in progress")
+
 // RebaseConflictError is returned when a rebase fails due to merge conflicts.
 type RebaseConflictError struct {
 	Branch string
@@ -97,6 +104,42 @@ func CreateBranch(ctx context.Context, name, startPoint string) error {
 	return nil
 }
 
+// RenameBranch renames a local branch, keeping it checked out if it was.
+// It runs: git branch -m <old> <new>
+func RenameBranch(ctx context.Context, oldName, newName string) error {
+	_, err := run(ctx, "branch", "-m", oldName, newName)
+	if err != nil {
+		return fmt.Errorf("git branch -m %s %s: %w", oldName, newName, err)
+	}
+	return nil
+}
+
+// DeleteBranch deletes a local branch. Without force git refuses to delete
+// a branch whose commits are not merged.
+// It runs: git branch -d|-D <name>
+func DeleteBranch(ctx context.Context, name string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	_, err := run(ctx, "branch", flag, name)
+	if err != nil {
+		return fmt.Errorf("git branch %s %s: %w", flag, name, err)
+	}
+	return nil
+}
+
+// ResetBranch points branch at sha without touching the working tree. It
+// must not be used on the checked-out branch.
+// It runs: git update-ref refs/heads/<branch> <sha>
+func ResetBranch(ctx context.Context, branch, sha string) error {
+	_, err := run(ctx, "update-ref", "refs/heads/"+branch, sha)
+	if err != nil {
+		return fmt.Errorf("git update-ref %s %s: %w", branch, sha, err)
+	}
+	return nil
+}
+
 // Checkout switches to the named branch.
 // It runs: git checkout <name>
 func Checkout(ctx context.Context, name string) error {
@@ -107,6 +150,82 @@ func Checkout(ctx context.Context, name string) error {
 	return nil
 }
 
+// CheckoutIn switches the worktree at dir to the named branch.
+// It runs: git -C <dir> checkout <name>
+func CheckoutIn(ctx context.Context, dir, name string) error {
+	_, err := run(ctx, "-C", dir, "checkout", name)
+	if err != nil {
+		return fmt.Errorf("git checkout %s in %s: %w", name, dir, err)
+	}
+	return nil
+}
+
+// WorktreeAdd creates a new worktree at path with HEAD detached at ref.
+// It runs: git worktree add --detach <path> <ref>
+func WorktreeAdd(ctx context.Context, path, ref string) error {
+	_, err := run(ctx, "worktree", "add", "--detach", path, ref)
+	if err != nil {
+		return fmt.Errorf("git worktree add %s: %w", path, err)
+	}
+	return nil
+}
+
+// WorktreeBranches returns the branches checked out in any worktree, mapped
+// to that worktree's path.
+// It runs: git worktree list --porcelain
+func WorktreeBranches(ctx context.Context) (map[string]string, error) {
+	out, err := run(ctx, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("git worktree list: %w", err)
+	}
+	branches := make(map[string]string)
+	var path string
+	for line := range strings.Lines(out) {
+		line = strings.TrimSpace(line)
+		if p, ok := strings.CutPrefix(line, "worktree "); ok {
+			path = p
+		} else if ref, ok := strings.CutPrefix(line, "branch refs/heads/"); ok {
+			branches[ref] = path
+		}
+	}
+	return branches, nil
+}
+
+// ListBranches returns the names of all local branches, sorted. A detached
+// HEAD is not a branch and is never listed.
+// It runs: git for-each-ref --format=%(refname) refs/heads/
+func ListBranches(ctx context.Context) ([]string, error) {
+	out, err := run(ctx, "for-each-ref", "--format=%(refname)", "refs/heads/")
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref: %w", err)
+	}
+	var branches []string
+	for line := range strings.Lines(out) {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "refs/heads/"); ok {
+			branches = append(branches, name)
+		}
+	}
+	return branches, nil
+}
+
+// BranchesBetween returns the local branches whose tips are reachable from
+// tip but not from base: the branches a rebase of tip onto base with
+// --update-refs would move.
+// It runs: git for-each-ref --format=%(refname) --merged <tip> --no-merged <base> refs/heads/
+func BranchesBetween(ctx context.Context, base, tip string) ([]string, error) {
+	out, err := run(ctx, "for-each-ref", "--format=%(refname)", "--merged", tip, "--no-merged", base, "refs/heads/")
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref: %w", err)
+	}
+	var branches []string
+	for line := range strings.Lines(out) {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "refs/heads/"); ok {
+			branches = append(branches, name)
+		}
+	}
+	return branches, nil
+}
+
 // Fetch fetches from the origin remote.
 // It runs: git fetch origin
 func Fetch(ctx context.Context) error {
@@ -117,25 +236,254 @@ func Fetch(ctx context.Context) error {
 	return nil
 }
 
+// FetchBranches fetches only the named branches from origin, updating their
+// remote-tracking refs. Every branch must exist on origin. It is a no-op for
+// an empty list.
+// It runs: git fetch origin +refs/heads/<b>:refs/remotes/origin/<b>...
+func FetchBranches(ctx context.Context, branches []string) error {
+	if len(branches) == 0 {
+		return nil
+	}
+	args := []string{"fetch", "origin"}
+	for _, b := range branches {
+		args = append(args, "+refs/heads/"+b+":refs/remotes/origin/"+b)
+	}
+	if _, err := run(ctx, args...); err != nil {
+		return fmt.Errorf("git fetch: %w", err)
+	}
+	return nil
+}
+
+// RevParse resolves a revision (branch, tag, SHA) to a full commit SHA.
+// It runs: git rev-parse --verify <rev>^{commit}
+func RevParse(ctx context.Context, rev string) (string, error) {
+	sha, err := run(ctx, "rev-parse", "--verify", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse %s: %w", rev, err)
+	}
+	return sha, nil
+}
+
+// CurrentCommit returns the SHA at the tip of a local branch.
+// It runs: git rev-parse --verify refs/heads/<branch>^{commit}
+func CurrentCommit(ctx context.Context, branch string) (string, error) {
+	return RevParse(ctx, "refs/heads/"+branch)
+}
+
+// CountCommits returns the number of commits reachable from to but not
+// from from.
+// It runs: git rev-list --count <from>..<to>
+func CountCommits(ctx context.Context, from, to string) (int, error) {
+	out, err := run(ctx, "rev-list", "--count", from+".."+to)
+	if err != nil {
+		return 0, fmt.Errorf("git rev-list %s..%s: %w", from, to, err)
+	}
+	n, err := strconv.Atoi(out)
+	if err != nil {
+		return 0, fmt.Errorf("parsing rev-list count %q: %w", out, err)
+	}
+	return n, nil
+}
+
+// Commit is one entry of LogRange.
+type Commit struct {
+	SHA     string
+	Subject string
+}
+
+// LogRange returns the commits reachable from branch but not from base,
+// newest first.
+// It runs: git log --format=%H %s <base>..<branch>
+func LogRange(ctx context.Context, base, branch string) ([]Commit, error) {
+	out, err := run(ctx, "log", "--format=%H %s", base+".."+branch)
+	if err != nil {
+		return nil, fmt.Errorf("git log %s..%s: %w", base, branch, err)
+	}
+	var commits []Commit
+	for line := range strings.Lines(out) {
+		sha, subject, _ := strings.Cut(strings.TrimRight(line, "\n"), " ")
+		if sha != "" {
+			commits = append(commits, Commit{SHA: sha, Subject: subject})
+		}
+	}
+	return commits, nil
+}
+
+// LastCommitTime returns the committer date of the tip of a local branch.
+// It runs: git log -1 --format=%ct refs/heads/<branch>
+func LastCommitTime(ctx context.Context, branch string) (time.Time, error) {
+	out, err := run(ctx, "log", "-1", "--format=%ct", "refs/heads/"+branch)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("git log %s: %w", branch, err)
+	}
+	secs, err := strconv.ParseInt(out, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing commit time %q: %w", out, err)
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// MergeBase returns the best common ancestor of a and b.
+// It runs: git merge-base <a> <b>
+func MergeBase(ctx context.Context, a, b string) (string, error) {
+	sha, err := run(ctx, "merge-base", a, b)
+	if err != nil {
+		return "", fmt.Errorf("git merge-base %s %s: %w", a, b, err)
+	}
+	return sha, nil
+}
+
+// IsAncestor reports whether ancestor is reachable from rev, i.e. whether
+// rev already contains it.
+// It runs: git merge-base --is-ancestor <ancestor> <rev>
+func IsAncestor(ctx context.Context, ancestor, rev string) (bool, error) {
+	_, err := run(ctx, "merge-base", "--is-ancestor", ancestor, rev)
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("git merge-base --is-ancestor %s %s: %w", ancestor, rev, err)
+}
+
 // Rebase rebases branch onto the given base.
 // It runs: git rebase <onto> <branch>
 // If a conflict is detected, it returns a *RebaseConflictError.
 func Rebase(ctx context.Context, onto, branch string) error {
-	_, err := run(ctx, "rebase", onto, branch)
+	return rebase(ctx, branch, true, onto, branch)
+}
+
+// RebaseKeepConflict is Rebase, except that on a conflict the rebase is left
+// in progress for manual resolution instead of being aborted.
+// It runs: git rebase <onto> <branch>
+func RebaseKeepConflict(ctx context.Context, onto, branch string) error {
+	return rebase(ctx, branch, false, onto, branch)
+}
+
+// RebaseUpdateRefs is Rebase with --update-refs: every local branch whose tip
+// lies in the rebased range moves along with it, so rebasing the top of a
+// stack restacks the branches below it in one pass. Needs git 2.38 or newer;
+// see SupportsUpdateRefs.
+// It runs: git rebase --update-refs <onto> <branch>
+// If a conflict is detected, the rebase is aborted and a *RebaseConflictError
+// is returned.
+func RebaseUpdateRefs(ctx context.Context, onto, branch string) error {
+	return rebase(ctx, branch, true, "--update-refs", onto, branch)
+}
+
+// RebaseOnto replays only the commits of branch that are not in oldBase
+// onto newBase, so moving a branch never drags along its old parent's commits.
+// It runs: git rebase --onto <newBase> <oldBase> <branch>
+// If a conflict is detected, it returns a *RebaseConflictError.
+func RebaseOnto(ctx context.Context, branch, oldBase, newBase string) error {
+	return rebase(ctx, branch, true, "--onto", newBase, oldBase, branch)
+}
+
+// rebase runs git rebase with args. On a conflict it returns a
+// *RebaseConflictError for branch and, if abort is set, aborts the rebase so
+// the repo is left clean.
+func rebase(ctx context.Context, branch string, abort bool, args ...string) error {
+	_, err := run(ctx, append([]string{"rebase"}, args...)...)
 	if err != nil {
 		var gitErr *GitError
 		if errors.As(err, &gitErr) {
 			if strings.Contains(gitErr.Stderr, "CONFLICT") ||
 				strings.Contains(gitErr.Stderr, "could not apply") {
-				// Abort the in-progress rebase so the repo is left clean.
-				_, _ = run(ctx, "rebase", "--abort")
+				if abort {
+					_, _ = run(ctx, "rebase", "--abort")
+				}
 				return &RebaseConflictError{
 					Branch: branch,
 					Stderr: gitErr.Stderr,
 				}
 			}
 		}
-		return fmt.Errorf("git rebase %s %s: %w", onto, branch, err)
+		return fmt.Errorf("git rebase %s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// Version returns the major, minor and patch version of the git binary.
+// It runs: git version
+func Version(ctx context.Context) ([3]int, error) {
+	out, err := run(ctx, "version")
+	if err != nil {
+		return [3]int{}, fmt.Errorf("git version: %w", err)
+	}
+	v, ok := ParseVersion(out)
+	if !ok {
+		return [3]int{}, fmt.Errorf("unrecognized git version %q", out)
+	}
+	return v, nil
+}
+
+// ParseVersion parses the output of "git version", e.g. "git version 2.39.5"
+// or "git version 2.39.3 (Apple Git-146)". A missing patch number is 0.
+func ParseVersion(out string) ([3]int, bool) {
+	var v [3]int
+	fields := strings.Fields(out)
+	if len(fields) < 3 || fields[0] != "git" || fields[1] != "version" {
+		return v, false
+	}
+	parts := strings.Split(fields[2], ".")
+	if len(parts) < 2 {
+		return v, false
+	}
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			// Suffixes like "rc0" end the usable part of the version.
+			if i < 2 {
+				return v, false
+			}
+			break
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// SupportsUpdateRefs reports whether git is new enough (2.38+) for
+// RebaseUpdateRefs. Any error reading the version counts as no.
+func SupportsUpdateRefs(ctx context.Context) bool {
+	v, err := Version(ctx)
+	if err != nil {
+		return false
+	}
+	return v[0] > 2 || (v[0] == 2 && v[1] >= 38)
+}
+
+// RebaseInProgress reports whether a rebase is stopped mid-way in the current
+// worktree, i.e. whether .git/rebase-merge or .git/rebase-apply exists.
+// It runs: git rev-parse --git-path rebase-merge (and rebase-apply)
+func RebaseInProgress(ctx context.Context) (bool, error) {
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		p, err := run(ctx, "rev-parse", "--git-path", name)
+		if err != nil {
+			return false, fmt.Errorf("git rev-parse --git-path %s: %w", name, err)
+		}
+		if _, err := os.Stat(p); err == nil {
+			return true, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("stat %s: %w", p, err)
+		}
+	}
+	return false, nil
+}
+
+// RebaseAbort aborts an in-progress rebase, restoring the branch that was
+// being rebased. It returns ErrNoRebaseInProgress if there is nothing to abort.
+// It runs: git rebase --abort
+func RebaseAbort(ctx context.Context) error {
+	_, err := run(ctx, "rebase", "--abort")
+	if err != nil {
+		var gitErr *GitError
+		if errors.As(err, &gitErr) && strings.Contains(gitErr.Stderr, "No rebase in progress") {
+			return ErrNoRebaseInProgress
+		}
+		return fmt.Errorf("git rebase --abort: %w", err)
 	}
 	return nil
 }
@@ -190,3 +538,88 @@ func Push(ctx context.Context, branch string) error {
 	}
 	return nil
 }
+
+// RemoteBranchExists reports whether branch exists on remote, asking the
+// remote directly rather than reading local remote-tracking refs.
+// It runs: git ls-remote --heads <remote> refs/heads/<branch>
+func RemoteBranchExists(ctx context.Context, remote, branch string) (bool, error) {
+	sha, err := lsRemoteHead(ctx, remote, branch)
+	if err != nil {
+		return false, err
+	}
+	return sha != "", nil
+}
+
+// RemoteHeadSHA returns the commit at the tip of branch on remote, without
+// fetching it. It fails if the remote has no such branch.
+// It runs: git ls-remote --heads <remote> refs/heads/<branch>
+func RemoteHeadSHA(ctx context.Context, remote, branch string) (string, error) {
+	sha, err := lsRemoteHead(ctx, remote, branch)
+	if err != nil {
+		return "", err
+	}
+	if sha == "" {
+		return "", fmt.Errorf("branch %s not found on %s", branch, remote)
+	}
+	return sha, nil
+}
+
+// RemoteBranches returns the names of all branches on remote.
+// It runs: git ls-remote --heads <remote>
+func RemoteBranches(ctx context.Context, remote string) (map[string]bool, error) {
+	out, err := run(ctx, "ls-remote", "--heads", remote)
+	if err != nil {
+		return nil, fmt.Errorf("git ls-remote %s: %w", remote, err)
+	}
+	branches := make(map[string]bool)
+	for line := range strings.Lines(out) {
+		_, ref, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if name, found := strings.CutPrefix(ref, "refs/heads/"); ok && found {
+			branches[name] = true
+		}
+	}
+	return branches, nil
+}
+
+// lsRemoteHead returns the SHA of refs/heads/<branch> on remote, or "" if
+// the remote has no such branch.
+func lsRemoteHead(ctx context.Context, remote, branch string) (string, error) {
+	ref := "refs/heads/" + branch
+	out, err := run(ctx, "ls-remote", "--heads", remote, ref)
+	if err != nil {
+		return "", fmt.Errorf("git ls-remote %s %s: %w", remote, branch, err)
+	}
+	// The pattern matches any ref ending in /<ref>; keep only the exact one.
+	for line := range strings.Lines(out) {
+		sha, name, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if ok && name == ref {
+			return sha, nil
+		}
+	}
+	return "", nil
+}
+
+// Diff writes the changes on branch since it forked from base to w, or a
+// diffstat when stat is true. Output is streamed rather than captured, so
+// large diffs are not held in memory.
+// It runs: git diff [--stat] <base>...<branch>
+func Diff(ctx context.Context, w io.Writer, base, branch string, stat bool) error {
+	args := []string{"diff"}
+	if stat {
+		args = append(args, "--stat")
+	}
+	args = append(args, base+"..."+branch, "--")
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	var stderr strings.Builder
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return &GitError{
+			Args:   args,
+			Stderr: strings.TrimSpace(stderr.String()),
+			Err:    err,
+		}
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 // initRepo creates a temporary git repo with an initial commit and returns
@@ -157,6 +159,96 @@ func TestCreateBranch(t *testing.T) {
 	}
 }
 
+func TestRenameBranch(t *testing.T) {
+	_, ctx := initRepo(t)
+
+	if err := CreateBranch(ctx, "old-name", "main"); err != nil {
+		t.Fatalf("CreateBranch() error: %v", err)
+	}
+	if err := RenameBranch(ctx, "old-name", "new-name"); err != nil {
+		t.Fatalf("RenameBranch() error: %v", err)
+	}
+
+	if got, _ := CurrentBranch(ctx); got != "new-name" {
+		t.Errorf("after RenameBranch, CurrentBranch() = %q, want new-name", got)
+	}
+	if exists, _ := BranchExists(ctx, "old-name"); exists {
+		t.Error("BranchExists(old-name) = true after RenameBranch")
+	}
+	if err := RenameBranch(ctx, "missing", "other"); err == nil {
+		t.Error("RenameBranch of a missing branch should fail")
+	}
+}
+
+func TestDeleteBranch(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	if err := CreateBranch(ctx, "unmerged", "main"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commitFile(t, dir, "work.txt", "work", "unmerged work")
+	if err := Checkout(ctx, "main"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	if err := DeleteBranch(ctx, "unmerged", false); err == nil {
+		t.Error("DeleteBranch without force should refuse an unmerged branch")
+	}
+	if err := DeleteBranch(ctx, "unmerged", true); err != nil {
+		t.Fatalf("DeleteBranch(force) error: %v", err)
+	}
+	if exists, _ := BranchExists(ctx, "unmerged"); exists {
+		t.Error("BranchExists(unmerged) = true after DeleteBranch")
+	}
+}
+
+func TestResetBranch(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	before, err := RevParse(ctx, "main")
+	if err != nil {
+		t.Fatalf("RevParse: %v", err)
+	}
+	if err := CreateBranch(ctx, "moving", "main"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commitFile(t, dir, "moving.txt", "x\n", "move ahead")
+	if err := Checkout(ctx, "main"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	if err := ResetBranch(ctx, "moving", before); err != nil {
+		t.Fatalf("ResetBranch() error: %v", err)
+	}
+	if got, _ := RevParse(ctx, "moving"); got != before {
+		t.Errorf("moving = %s after ResetBranch, want %s", got, before)
+	}
+}
+
+func TestLogRange(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	if err := CreateBranch(ctx, "logged", "main"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if got, err := LogRange(ctx, "main", "logged"); err != nil || len(got) != 0 {
+		t.Fatalf("LogRange() on an empty branch = %v, %v; want none", got, err)
+	}
+	commitFile(t, dir, "one.txt", "1\n", "add one")
+	commitFile(t, dir, "two.txt", "2\n", "add two: with colon")
+
+	got, err := LogRange(ctx, "main", "logged")
+	if err != nil {
+		t.Fatalf("LogRange() error: %v", err)
+	}
+	if len(got) != 2 || got[0].Subject != "add two: with colon" || got[1].Subject != "add one" {
+		t.Errorf("LogRange() = %+v, want both commits newest first", got)
+	}
+	if tip, _ := RevParse(ctx, "logged"); got[0].SHA != tip {
+		t.Errorf("newest SHA = %s, want tip %s", got[0].SHA, tip)
+	}
+}
+
 func TestCheckout(t *testing.T) {
 	_, ctx := initRepo(t)
 
@@ -181,6 +273,92 @@ func TestCheckout(t *testing.T) {
 	}
 }
 
+func TestListBranches(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	for _, name := range []string{"feat/b", "a"} {
+		if err := CreateBranch(ctx, name, "main"); err != nil {
+			t.Fatalf("CreateBranch(%s) error: %v", name, err)
+		}
+	}
+	// A detached HEAD must not show up as a branch.
+	cmd := exec.Command("git", "checkout", "--detach", "main")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git checkout --detach: %s\n%s", err, out)
+	}
+
+	got, err := ListBranches(ctx)
+	if err != nil {
+		t.Fatalf("ListBranches() error: %v", err)
+	}
+	if want := []string{"a", "feat/b", "main"}; !slices.Equal(got, want) {
+		t.Errorf("ListBranches() = %v, want %v", got, want)
+	}
+}
+
+func TestBranchesBetween(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	commit := func(msg string) {
+		t.Helper()
+		c := exec.Command("git", "commit", "--allow-empty", "-m", msg)
+		c.Dir = dir
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git commit: %s\n%s", err, out)
+		}
+	}
+	for _, name := range []string{"lower", "side", "upper"} {
+		if err := CreateBranch(ctx, name, "HEAD"); err != nil {
+			t.Fatalf("CreateBranch(%s) error: %v", name, err)
+		}
+		if name != "side" {
+			commit(name + " work")
+		}
+	}
+
+	got, err := BranchesBetween(ctx, "main", "upper")
+	if err != nil {
+		t.Fatalf("BranchesBetween() error: %v", err)
+	}
+	if want := []string{"lower", "side", "upper"}; !slices.Equal(got, want) {
+		t.Errorf("BranchesBetween() = %v, want %v", got, want)
+	}
+}
+
+func TestWorktreeBranches(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	if err := CreateBranch(ctx, "other", "main"); err != nil {
+		t.Fatalf("CreateBranch() error: %v", err)
+	}
+	if err := Checkout(ctx, "main"); err != nil {
+		t.Fatalf("Checkout(main) error: %v", err)
+	}
+	wt := filepath.Join(t.TempDir(), "wt")
+	if err := WorktreeAdd(ctx, wt, "main"); err != nil {
+		t.Fatalf("WorktreeAdd() error: %v", err)
+	}
+	if err := CheckoutIn(ctx, wt, "other"); err != nil {
+		t.Fatalf("CheckoutIn(other) error: %v", err)
+	}
+	// main is already checked out in the first worktree.
+	if err := CheckoutIn(ctx, wt, "main"); err == nil {
+		t.Error("CheckoutIn(main) should fail while main is checked out elsewhere")
+	}
+
+	got, err := WorktreeBranches(ctx)
+	if err != nil {
+		t.Fatalf("WorktreeBranches() error: %v", err)
+	}
+	if len(got) != 2 || got["main"] == "" || got["other"] == "" {
+		t.Fatalf("WorktreeBranches() = %v, want main and other", got)
+	}
+	if filepath.Base(got["other"]) != "wt" || filepath.Base(got["main"]) != filepath.Base(dir) {
+		t.Errorf("WorktreeBranches() = %v, want other in wt and main in %s", got, dir)
+	}
+}
+
 func TestRebase(t *testing.T) {
 	dir, ctx := initRepo(t)
 
@@ -235,6 +413,61 @@ func TestRebase(t *testing.T) {
 	})
 }
 
+func TestRebaseUpdateRefs(t *testing.T) {
+	dir, ctx := initRepo(t)
+	if !SupportsUpdateRefs(ctx) {
+		t.Skip("git too old for --update-refs")
+	}
+
+	// main <- lower <- upper, then main moves on.
+	if err := CreateBranch(ctx, "lower", "main"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commitFile(t, dir, "lower.txt", "lower\n", "lower work")
+	if err := CreateBranch(ctx, "upper", "lower"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commitFile(t, dir, "upper.txt", "upper\n", "upper work")
+	if err := Checkout(ctx, "main"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	commitFile(t, dir, "main.txt", "main\n", "main work")
+
+	if err := RebaseUpdateRefs(ctx, "main", "upper"); err != nil {
+		t.Fatalf("RebaseUpdateRefs() error: %v", err)
+	}
+	for _, pair := range [][2]string{{"main", "lower"}, {"lower", "upper"}} {
+		ok, err := IsAncestor(ctx, pair[0], pair[1])
+		if err != nil {
+			t.Fatalf("IsAncestor(%s, %s): %v", pair[0], pair[1], err)
+		}
+		if !ok {
+			t.Errorf("%s is not an ancestor of %s after RebaseUpdateRefs", pair[0], pair[1])
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want [3]int
+		ok   bool
+	}{
+		{"git version 2.39.5", [3]int{2, 39, 5}, true},
+		{"git version 2.39.3 (Apple Git-146)", [3]int{2, 39, 3}, true},
+		{"git version 2.45.1.windows.1", [3]int{2, 45, 1}, true},
+		{"git version 2.40.0-rc0", [3]int{2, 40, 0}, true},
+		{"git version 2.38", [3]int{2, 38, 0}, true},
+		{"git version two", [3]int{}, false},
+		{"hub version 2.14.2", [3]int{}, false},
+	} {
+		got, ok := ParseVersion(tc.in)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("ParseVersion(%q) = %v, %v; want %v, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
 func TestRebaseConflict(t *testing.T) {
 	dir, ctx := initRepo(t)
 
@@ -285,6 +518,18 @@ func TestRebaseConflict(t *testing.T) {
 	if conflictErr.Branch != "conflict-branch" {
 		t.Errorf("RebaseConflictError.Branch = %q, want %q", conflictErr.Branch, "conflict-branch")
 	}
+	if inProgress, _ := RebaseInProgress(ctx); inProgress {
+		t.Error("Rebase() left the conflicted rebase in progress")
+	}
+
+	// RebaseKeepConflict leaves it for manual resolution.
+	err = RebaseKeepConflict(ctx, "main", "conflict-branch")
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("RebaseKeepConflict() error = %v, want *RebaseConflictError", err)
+	}
+	if inProgress, _ := RebaseInProgress(ctx); !inProgress {
+		t.Error("RebaseKeepConflict() should leave the rebase in progress")
+	}
 }
 
 func TestPush(t *testing.T) {
@@ -359,3 +604,358 @@ func TestGitError(t *testing.T) {
 		t.Error("GitError.Stderr is empty")
 	}
 }
+
+// commitFile writes content to filename in dir and commits it.
+func commitFile(t *testing.T, dir, filename, content, msg string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, filename), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", filename}, {"commit", "-m", msg}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %s\n%s", args[0], err, out)
+		}
+	}
+}
+
+// startConflictingRebase leaves a rebase of conflict-branch onto main stopped
+// on a conflict, bypassing Rebase's automatic abort.
+func startConflictingRebase(t *testing.T, dir string, ctx context.Context) {
+	t.Helper()
+	commitFile(t, dir, "shared.txt", "original\n", "add shared file")
+	if err := CreateBranch(ctx, "conflict-branch", "main"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commitFile(t, dir, "shared.txt", "branch change\n", "modify shared on branch")
+	if err := Checkout(ctx, "main"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	commitFile(t, dir, "shared.txt", "main change\n", "modify shared on main")
+
+	if _, err := run(ctx, "rebase", "main", "conflict-branch"); err == nil {
+		t.Fatal("expected the rebase to stop on a conflict")
+	}
+}
+
+func TestRebaseAbort(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	t.Run("nothing to abort", func(t *testing.T) {
+		if err := RebaseAbort(ctx); !errors.Is(err, ErrNoRebaseInProgress) {
+			t.Fatalf("RebaseAbort() error = %v, want ErrNoRebaseInProgress", err)
+		}
+	})
+
+	t.Run("aborts stopped rebase", func(t *testing.T) {
+		startConflictingRebase(t, dir, ctx)
+
+		if err := RebaseAbort(ctx); err != nil {
+			t.Fatalf("RebaseAbort() error: %v", err)
+		}
+		got, err := CurrentBranch(ctx)
+		if err != nil {
+			t.Fatalf("CurrentBranch() error: %v", err)
+		}
+		if got != "conflict-branch" {
+			t.Errorf("after abort, CurrentBranch() = %q, want %q", got, "conflict-branch")
+		}
+	})
+}
+
+func TestRebaseOnto(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	// main ← old-parent ← child; move child onto main without old-parent's commit.
+	if err := CreateBranch(ctx, "old-parent", "main"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commitFile(t, dir, "parent.txt", "parent\n", "parent work")
+	if err := CreateBranch(ctx, "child", "old-parent"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commitFile(t, dir, "child.txt", "child\n", "child work")
+
+	if err := RebaseOnto(ctx, "child", "old-parent", "main"); err != nil {
+		t.Fatalf("RebaseOnto() error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "parent.txt")); !os.IsNotExist(err) {
+		t.Error("parent.txt present after RebaseOnto; old parent's commits were replayed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "child.txt")); err != nil {
+		t.Errorf("child.txt missing after RebaseOnto: %v", err)
+	}
+}
+
+func TestRebaseOntoConflict(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	commitFile(t, dir, "shared.txt", "original\n", "add shared file")
+	if err := CreateBranch(ctx, "old-parent", "main"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := CreateBranch(ctx, "child", "old-parent"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	commitFile(t, dir, "shared.txt", "child change\n", "child change")
+	if err := Checkout(ctx, "main"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	commitFile(t, dir, "shared.txt", "main change\n", "main change")
+
+	err := RebaseOnto(ctx, "child", "old-parent", "main")
+	var conflictErr *RebaseConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("RebaseOnto() error = %v, want *RebaseConflictError", err)
+	}
+	if conflictErr.Branch != "child" {
+		t.Errorf("RebaseConflictError.Branch = %q, want %q", conflictErr.Branch, "child")
+	}
+}
+
+func TestRebaseInProgress(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	inProgress, err := RebaseInProgress(ctx)
+	if err != nil {
+		t.Fatalf("RebaseInProgress() error: %v", err)
+	}
+	if inProgress {
+		t.Fatal("RebaseInProgress() = true on a clean repo")
+	}
+
+	startConflictingRebase(t, dir, ctx)
+
+	inProgress, err = RebaseInProgress(ctx)
+	if err != nil {
+		t.Fatalf("RebaseInProgress() error: %v", err)
+	}
+	if !inProgress {
+		t.Error("RebaseInProgress() = false during a stopped rebase")
+	}
+}
+
+func TestRevParseAndCountCommits(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	base, err := RevParse(ctx, "main")
+	if err != nil {
+		t.Fatalf("RevParse() error: %v", err)
+	}
+	if len(base) != 40 {
+		t.Errorf("RevParse() = %q, want a full SHA", base)
+	}
+
+	commitFile(t, dir, "a.txt", "a\n", "a")
+	commitFile(t, dir, "b.txt", "b\n", "b")
+
+	n, err := CountCommits(ctx, base, "main")
+	if err != nil {
+		t.Fatalf("CountCommits() error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("CountCommits() = %d, want 2", n)
+	}
+
+	if _, err := RevParse(ctx, "no-such-branch"); err == nil {
+		t.Error("RevParse() should fail for an unknown revision")
+	}
+}
+
+func TestMergeBase(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	if err := CreateBranch(ctx, "feature", "main"); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "f.txt", "f\n", "feature work")
+	if err := Checkout(ctx, "main"); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "m.txt", "m\n", "main work")
+
+	base, err := MergeBase(ctx, "main", "feature")
+	if err != nil {
+		t.Fatalf("MergeBase() error: %v", err)
+	}
+	want, err := RevParse(ctx, "main~1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base != want {
+		t.Errorf("MergeBase() = %q, want %q", base, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	if err := CreateBranch(ctx, "feature", "main"); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "f.txt", "feature\n", "feature work")
+	if err := Checkout(ctx, "main"); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "m.txt", "main\n", "main work")
+
+	var out strings.Builder
+	if err := Diff(ctx, &out, "main", "feature", false); err != nil {
+		t.Fatalf("Diff() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "+feature") || strings.Contains(out.String(), "m.txt") {
+		t.Errorf("Diff() should show only the branch's own changes:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := Diff(ctx, &out, "main", "feature", true); err != nil {
+		t.Fatalf("Diff(stat) error: %v", err)
+	}
+	if !strings.Contains(out.String(), "1 file changed") {
+		t.Errorf("Diff(stat) = %q, want a diffstat", out.String())
+	}
+
+	if err := Diff(ctx, &out, "main", "no-such-branch", false); err == nil {
+		t.Error("Diff() should fail for an unknown branch")
+	}
+}
+
+func TestIsAncestor(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	if err := CreateBranch(ctx, "feature", "main"); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "f.txt", "f\n", "feature work")
+
+	if ok, err := IsAncestor(ctx, "main", "feature"); err != nil || !ok {
+		t.Errorf("IsAncestor(main, feature) = %v, %v; want true", ok, err)
+	}
+	if ok, err := IsAncestor(ctx, "feature", "main"); err != nil || ok {
+		t.Errorf("IsAncestor(feature, main) = %v, %v; want false", ok, err)
+	}
+	if _, err := IsAncestor(ctx, "no-such-branch", "main"); err == nil {
+		t.Error("IsAncestor() should fail for an unknown revision")
+	}
+}
+
+func TestRemoteBranchExistsAndHeadSHA(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	// Set up a bare remote with main pushed.
+	remoteDir := t.TempDir()
+	cmd := exec.Command("git", "init", "--bare")
+	cmd.Dir = remoteDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %s\n%s", err, out)
+	}
+	cmd = exec.Command("git", "remote", "add", "origin", remoteDir)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git remote add: %s\n%s", err, out)
+	}
+	if err := Push(ctx, "main"); err != nil {
+		t.Fatalf("Push() error: %v", err)
+	}
+
+	exists, err := RemoteBranchExists(ctx, "origin", "main")
+	if err != nil {
+		t.Fatalf("RemoteBranchExists(main) error: %v", err)
+	}
+	if !exists {
+		t.Error("RemoteBranchExists(main) = false, want true")
+	}
+	exists, err = RemoteBranchExists(ctx, "origin", "ain")
+	if err != nil {
+		t.Fatalf("RemoteBranchExists(ain) error: %v", err)
+	}
+	if exists {
+		t.Error("RemoteBranchExists(ain) = true, want false")
+	}
+
+	sha, err := RemoteHeadSHA(ctx, "origin", "main")
+	if err != nil {
+		t.Fatalf("RemoteHeadSHA(main) error: %v", err)
+	}
+	want, err := RevParse(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sha != want {
+		t.Errorf("RemoteHeadSHA(main) = %q, want %q", sha, want)
+	}
+	if _, err := RemoteHeadSHA(ctx, "origin", "missing"); err == nil {
+		t.Error("RemoteHeadSHA(missing) should fail")
+	}
+
+	if _, err := RemoteBranchExists(ctx, "nope", "main"); err == nil {
+		t.Error("RemoteBranchExists on an unknown remote should fail")
+	}
+}
+
+func TestLastCommitTime(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	cmd := exec.Command("git", "commit", "--allow-empty", "-m", "old")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2020-01-02T03:04:05Z")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %s\n%s", err, out)
+	}
+
+	got, err := LastCommitTime(ctx, "main")
+	if err != nil {
+		t.Fatalf("LastCommitTime() error: %v", err)
+	}
+	want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("LastCommitTime() = %v, want %v", got, want)
+	}
+	if _, err := LastCommitTime(ctx, "missing"); err == nil {
+		t.Error("LastCommitTime(missing) should fail")
+	}
+}
+
+func TestFetchBranchesAndRemoteBranches(t *testing.T) {
+	dir, ctx := initRepo(t)
+
+	remoteDir := t.TempDir()
+	cmd := exec.Command("git", "init", "--bare")
+	cmd.Dir = remoteDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %s\n%s", err, out)
+	}
+	cmd = exec.Command("git", "remote", "add", "origin", remoteDir)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git remote add: %s\n%s", err, out)
+	}
+	if err := CreateBranch(ctx, "feature", "main"); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []string{"main", "feature"} {
+		if err := Push(ctx, b); err != nil {
+			t.Fatalf("Push(%s) error: %v", b, err)
+		}
+	}
+
+	remote, err := RemoteBranches(ctx, "origin")
+	if err != nil {
+		t.Fatalf("RemoteBranches() error: %v", err)
+	}
+	if len(remote) != 2 || !remote["main"] || !remote["feature"] {
+		t.Errorf("RemoteBranches() = %v, want main and feature", remote)
+	}
+
+	if err := FetchBranches(ctx, nil); err != nil {
+		t.Errorf("FetchBranches(nil) error: %v", err)
+	}
+	if err := FetchBranches(ctx, []string{"main", "feature"}); err != nil {
+		t.Fatalf("FetchBranches() error: %v", err)
+	}
+	if err := FetchBranches(ctx, []string{"missing"}); err == nil {
+		t.Error("FetchBranches(missing) should fail")
+	}
+}
@@ -0,0 +1,204 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config keys stored in State.Config.
+const (
+	ConfigLockStale     = "lock_stale"     // duration after which a lockfile is stale, e.g. "30s"
+	ConfigMaxSnapshots  = "max_snapshots"  // snapshots retained by Snapshot
+	ConfigMaxHistory    = "max_history"    // entries retained in the history log
+	ConfigStackTrailers = "stack_trailers" // "true" keeps Frond-Parent/Frond-After trailers in PR bodies
+	ConfigUpdateCheck   = "update_check"   // "true" enables the daily newer-release notice
+	ConfigLabelRules    = "label_rules"    // prefix=label pairs for push --label-from-path, e.g. "pay/=area/pay,auth/=area/auth"
+	ConfigRepo          = "repo"           // upstream "owner/name" for every gh call, set by push --repo in fork workflows
+)
+
+// configValidators checks values for every known config key.
+var configValidators = map[string]func(string) error{
+	ConfigLockStale: func(v string) error {
+		_, err := parsePositiveDuration(v)
+		return err
+	},
+	ConfigMaxSnapshots:  validateCount,
+	ConfigMaxHistory:    validateCount,
+	ConfigStackTrailers: validateBool,
+	ConfigUpdateCheck:   validateBool,
+	ConfigLabelRules: func(v string) error {
+		_, err := ParseLabelRules(v)
+		return err
+	},
+	ConfigRepo: func(v string) error {
+		owner, name, ok := strings.Cut(v, "/")
+		if !ok || owner == "" || name == "" || strings.ContainsAny(name, "/ ") || strings.Contains(owner, " ") {
+			return fmt.Errorf("want owner/name, got %q", v)
+		}
+		return nil
+	},
+}
+
+// LabelRule maps branches whose name starts with Prefix to a PR label.
+type LabelRule struct {
+	Prefix string
+	Label  string
+}
+
+// ParseLabelRules parses a label_rules value: comma-separated prefix=label
+// pairs with no empty parts.
+func ParseLabelRules(v string) ([]LabelRule, error) {
+	var rules []LabelRule
+	for pair := range strings.SplitSeq(v, ",") {
+		prefix, label, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		prefix, label = strings.TrimSpace(prefix), strings.TrimSpace(label)
+		if !ok || prefix == "" || label == "" {
+			return nil, fmt.Errorf("want comma-separated prefix=label pairs, got %q", pair)
+		}
+		rules = append(rules, LabelRule{Prefix: prefix, Label: label})
+	}
+	return rules, nil
+}
+
+// validateCount accepts a non-negative integer.
+func validateCount(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return fmt.Errorf("must be a non-negative integer, got %q", v)
+	}
+	return nil
+}
+
+// validateBool accepts the values strconv.ParseBool does.
+func validateBool(v string) error {
+	if _, err := strconv.ParseBool(v); err != nil {
+		return fmt.Errorf("must be true or false, got %q", v)
+	}
+	return nil
+}
+
+// ConfigKeys returns the known config keys, sorted.
+func ConfigKeys() []string {
+	keys := make([]string, 0, len(configValidators))
+	for k := range configValidators {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// ValidateConfig returns an error if key is unknown or value is invalid for it.
+func ValidateConfig(key, value string) error {
+	validate, ok := configValidators[key]
+	if !ok {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+	if err := validate(value); err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
+
+// parsePositiveDuration parses a Go duration string that must be > 0.
+func parsePositiveDuration(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("must be positive, got %s", v)
+	}
+	return d, nil
+}
+
+// lockStaleOverride, when positive, takes precedence over the config file.
+var lockStaleOverride time.Duration
+
+// SetLockStale overrides the stale-lock duration for subsequent Locks (e.g.
+// from a flag or environment variable). d must be positive.
+func SetLockStale(d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("lock stale duration must be positive, got %s", d)
+	}
+	lockStaleOverride = d
+	return nil
+}
+
+// ParseLockStale parses a stale-lock duration such as "90s" and applies it
+// with SetLockStale.
+func ParseLockStale(v string) error {
+	d, err := parsePositiveDuration(v)
+	if err != nil {
+		return fmt.Errorf("invalid lock stale duration: %w", err)
+	}
+	return SetLockStale(d)
+}
+
+// lockStale returns the effective stale-lock duration: the override, then
+// lock_stale from frond.json in dir, then the 5 minute default.
+func lockStale(dir string) time.Duration {
+	if lockStaleOverride > 0 {
+		return lockStaleOverride
+	}
+	if v := fileConfig(dir)[ConfigLockStale]; v != "" {
+		if d, err := parsePositiveDuration(v); err == nil {
+			return d
+		}
+	}
+	return lockStaleDuration
+}
+
+// maxSnapshots returns the snapshot retention limit: max_snapshots from
+// frond.json in dir, else MaxSnapshots.
+func maxSnapshots(dir string) int {
+	if v := fileConfig(dir)[ConfigMaxSnapshots]; v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return MaxSnapshots
+}
+
+// maxHistory returns the history log retention limit: max_history from
+// frond.json in dir, else MaxHistory.
+func maxHistory(dir string) int {
+	if v := fileConfig(dir)[ConfigMaxHistory]; v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return MaxHistory
+}
+
+// ConfigValue returns key from the config section of frond.json without
+// taking the lock, or "" when it is unset or the state cannot be read.
+func ConfigValue(ctx context.Context, key string) string {
+	dir, err := gitCommonDir(ctx)
+	if err != nil {
+		return ""
+	}
+	return fileConfig(dir)[key]
+}
+
+// fileConfig reads the config section of frond.json in dir without taking
+// the lock. Missing or unreadable state yields an empty config.
+func fileConfig(dir string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(dir, stateFile)) //nolint:gosec // path is constructed internally from git common dir
+	if err != nil {
+		return nil
+	}
+	var s struct {
+		Config map[string]string `json:"config"`
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+	return s.Config
+}
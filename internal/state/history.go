@@ -0,0 +1,341 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const historyFile = "frond.log.jsonl"
+
+// MaxHistory bounds how many entries the history log keeps; each write
+// drops the oldest beyond it. Zero or less keeps them all. The max_history
+// config key overrides it per repo.
+var MaxHistory = 200
+
+// HistoryEntry records one state mutation in frond.log.jsonl.
+type HistoryEntry struct {
+	Time    time.Time       `json:"time"`
+	Command string          `json:"command"`
+	Summary []string        `json:"summary"`
+	Before  json.RawMessage `json:"before,omitempty"` // frond.json prior to the change; empty on init
+}
+
+// command is the name recorded with each history entry. The CLI sets it
+// once per invocation via SetCommand.
+var command = "unknown"
+
+// SetCommand sets the command name recorded by subsequent Writes.
+func SetCommand(name string) {
+	command = name
+}
+
+// appendHistory adds an entry describing the change from before to after.
+// before is the raw frond.json content prior to the write (nil if none) and
+// data the content just written. Nothing is recorded when the bytes match.
+func appendHistory(dir string, before, data []byte, after *State) error {
+	if sameJSON(before, data) {
+		return nil
+	}
+
+	var prev *State
+	if len(before) > 0 {
+		prev = &State{}
+		if err := json.Unmarshal(before, prev); err != nil {
+			// Unparseable previous state: still record the full snapshot.
+			prev = nil
+		}
+	}
+
+	summary := diffSummary(prev, after)
+	if len(summary) == 0 {
+		// Only formatting or unknown fields changed; still undoable.
+		summary = []string{"rewrite state"}
+	}
+
+	entry := HistoryEntry{
+		Time:    time.Now().UTC(),
+		Command: command,
+		Summary: summary,
+	}
+	if len(before) > 0 {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, before); err == nil {
+			entry.Before = compact.Bytes()
+		}
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshalling history entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	p := filepath.Join(dir, historyFile)
+	if err := rejectSymlink(p); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // path is constructed internally from git common dir
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", p, err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", p, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", p, err)
+	}
+	return pruneHistory(dir, maxHistory(dir))
+}
+
+// pruneHistory drops the oldest entries of the history log beyond limit.
+// Each entry holds a whole earlier state, so an unbounded log grows with
+// state size times the number of writes. The log is rewritten atomically.
+func pruneHistory(dir string, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	p := filepath.Join(dir, historyFile)
+	data, err := os.ReadFile(p) //nolint:gosec // path is constructed internally from git common dir
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", p, err)
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= limit {
+		return nil
+	}
+
+	tmp := p + ".tmp"
+	if err := rejectSymlink(tmp); err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmp, bytes.Join(lines[len(lines)-limit:], nil), 0o600); err != nil {
+		return fmt.Errorf("writing temp file %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("renaming %s to %s: %w", tmp, p, err)
+	}
+	return nil
+}
+
+// sameJSON reports whether a and b hold the same JSON, ignoring
+// whitespace. Unparseable input is compared byte for byte.
+func sameJSON(a, b []byte) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// History returns all recorded state mutations, oldest first. A missing
+// log yields an empty slice.
+func History(ctx context.Context) ([]HistoryEntry, error) {
+	dir, err := gitCommonDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := filepath.Join(dir, historyFile)
+
+	f, err := os.Open(p) //nolint:gosec // path is constructed internally from git common dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []HistoryEntry{}, nil
+		}
+		return nil, fmt.Errorf("opening %s: %w", p, err)
+	}
+	defer f.Close()
+
+	entries := []HistoryEntry{}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var e HistoryEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("parsing %s line %d: %w", p, n, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", p, err)
+	}
+	return entries, nil
+}
+
+// ErrNothingToUndo is returned by Undo when no earlier state is recorded.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// undoCommand is the command name Undo records, so repeated undos walk
+// further back instead of toggling. Snapshot restores record "undo <id>"
+// instead: they are ordinary mutations that a later undo reverts.
+const undoCommand = "undo"
+
+// Undo restores frond.json to the state before the most recent mutation
+// that has not already been undone, and returns that mutation's entry.
+// Callers must hold the lock. Only metadata is restored; git branches are
+// left alone.
+func Undo(ctx context.Context) (*HistoryEntry, error) {
+	entries, err := History(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each undo entry cancels out the nearest earlier mutation.
+	skip := 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Command == undoCommand {
+			skip++
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		if len(e.Before) == 0 {
+			return nil, ErrNothingToUndo
+		}
+
+		var prev State
+		if err := json.Unmarshal(e.Before, &prev); err != nil {
+			return nil, fmt.Errorf("parsing recorded state: %w", err)
+		}
+		saved := command
+		command = undoCommand
+		err := Write(ctx, &prev)
+		command = saved
+		if err != nil {
+			return nil, err
+		}
+		return &e, nil
+	}
+	return nil, ErrNothingToUndo
+}
+
+// diffSummary describes the differences between two states as short
+// human-readable lines, sorted for stable output. prev may be nil.
+func diffSummary(prev, next *State) []string {
+	if prev == nil {
+		return []string{fmt.Sprintf("init (trunk %s)", next.Trunk)}
+	}
+
+	var out []string
+	if prev.Version != next.Version {
+		out = append(out, fmt.Sprintf("version %d → %d", prev.Version, next.Version))
+	}
+	if prev.Trunk != next.Trunk {
+		out = append(out, fmt.Sprintf("trunk %s → %s", prev.Trunk, next.Trunk))
+	}
+	if prev.LastSyncedTrunk != next.LastSyncedTrunk {
+		out = append(out, fmt.Sprintf("last synced trunk %s → %s", orNone(prev.LastSyncedTrunk), orNone(next.LastSyncedTrunk)))
+	}
+	if prev.LastSeq != next.LastSeq {
+		out = append(out, fmt.Sprintf("last seq %d → %d", prev.LastSeq, next.LastSeq))
+	}
+	for _, t := range next.Trunks {
+		if !slices.Contains(prev.Trunks, t) {
+			out = append(out, "add trunk "+t)
+		}
+	}
+	for _, t := range prev.Trunks {
+		if !slices.Contains(next.Trunks, t) {
+			out = append(out, "remove trunk "+t)
+		}
+	}
+
+	for k, v := range next.Config {
+		if prev.Config[k] != v {
+			out = append(out, fmt.Sprintf("config %s = %s", k, v))
+		}
+	}
+	for k := range prev.Config {
+		if _, ok := next.Config[k]; !ok {
+			out = append(out, "config unset "+k)
+		}
+	}
+
+	for name, nb := range next.Branches {
+		pb, existed := prev.Branches[name]
+		if !existed {
+			out = append(out, fmt.Sprintf("track %s (parent %s)", name, nb.Parent))
+			continue
+		}
+		if pb.Parent != nb.Parent {
+			out = append(out, fmt.Sprintf("%s: parent %s → %s", name, pb.Parent, nb.Parent))
+		}
+		if !slices.Equal(pb.After, nb.After) {
+			out = append(out, fmt.Sprintf("%s: after [%s] → [%s]", name, strings.Join(pb.After, ", "), strings.Join(nb.After, ", ")))
+		}
+		if prNum(pb.PR) != prNum(nb.PR) {
+			out = append(out, fmt.Sprintf("%s: pr %s → %s", name, prNum(pb.PR), prNum(nb.PR)))
+		}
+		if pb.BaseOverride != nb.BaseOverride {
+			out = append(out, fmt.Sprintf("%s: base %s → %s", name, orNone(pb.BaseOverride), orNone(nb.BaseOverride)))
+		}
+		if pb.PushedSHA != nb.PushedSHA {
+			out = append(out, fmt.Sprintf("%s: pushed %s → %s", name, orNone(pb.PushedSHA), orNone(nb.PushedSHA)))
+		}
+		if pb.BaseSHA != nb.BaseSHA {
+			out = append(out, fmt.Sprintf("%s: base sha %s → %s", name, orNone(pb.BaseSHA), orNone(nb.BaseSHA)))
+		}
+		if pb.Seq != nb.Seq {
+			out = append(out, fmt.Sprintf("%s: seq %d → %d", name, pb.Seq, nb.Seq))
+		}
+	}
+	for name := range prev.Branches {
+		if _, ok := next.Branches[name]; !ok {
+			out = append(out, "untrack "+name)
+		}
+	}
+
+	for name, nm := range next.Merged {
+		pm, existed := prev.Merged[name]
+		switch {
+		case !existed:
+			out = append(out, fmt.Sprintf("record merged %s (pr %s)", name, prNum(nm.PR)))
+		case pm.Parent != nm.Parent || prNum(pm.PR) != prNum(nm.PR) || !pm.MergedAt.Equal(nm.MergedAt) || pm.Author != nm.Author:
+			out = append(out, "update merged "+name)
+		}
+	}
+	for name := range prev.Merged {
+		if _, ok := next.Merged[name]; !ok {
+			out = append(out, "forget merged "+name)
+		}
+	}
+
+	slices.Sort(out)
+	return out
+}
+
+// orNone formats an optional string value for diff summaries.
+func orNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
+
+// prNum formats an optional PR number for diff summaries.
+func prNum(pr *int) string {
+	if pr == nil {
+		return "none"
+	}
+	return fmt.Sprintf("#%d", *pr)
+}
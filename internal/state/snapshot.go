@@ -0,0 +1,150 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	snapshotDir    = "frond-snapshots"
+	snapshotFormat = "20060102-150405.000000000"
+)
+
+// MaxSnapshots bounds how many snapshots are retained; Snapshot prunes the
+// oldest beyond it. Zero or less disables pruning. The max_snapshots config
+// key overrides it per repo.
+var MaxSnapshots = 20
+
+// ErrSnapshotNotFound is returned by Restore for an unknown snapshot id.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// Snapshot copies the current frond.json to a timestamped backup under
+// <git-common-dir>/frond-snapshots and returns its id.
+func Snapshot(ctx context.Context) (string, error) {
+	p, err := Path(ctx)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(p) //nolint:gosec // path is constructed internally from git common dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ErrNotInitialized
+		}
+		return "", fmt.Errorf("reading %s: %w", p, err)
+	}
+
+	dir := filepath.Join(filepath.Dir(p), snapshotDir)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("creating directory %s: %w", dir, err)
+	}
+
+	id := time.Now().UTC().Format(snapshotFormat)
+	dst := filepath.Join(dir, id+".json")
+	if err := rejectSymlink(dst); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint:gosec // path is constructed internally
+	if err != nil {
+		return "", fmt.Errorf("creating snapshot %s: %w", dst, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return "", fmt.Errorf("writing snapshot %s: %w", dst, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(dst)
+		return "", fmt.Errorf("closing snapshot %s: %w", dst, err)
+	}
+
+	if err := pruneSnapshots(dir, maxSnapshots(filepath.Dir(p))); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+// Restore replaces frond.json with the snapshot id. The swap goes through
+// Write, so it is atomic and recorded in the history log, where a later
+// Undo reverts it. Callers must hold the lock.
+func Restore(ctx context.Context, id string) error {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return fmt.Errorf("invalid snapshot id %q", id)
+	}
+	dir, err := gitCommonDir(ctx)
+	if err != nil {
+		return err
+	}
+	src := filepath.Join(dir, snapshotDir, id+".json")
+
+	data, err := os.ReadFile(src) //nolint:gosec // id is validated above
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrSnapshotNotFound, id)
+		}
+		return fmt.Errorf("reading snapshot %s: %w", src, err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("parsing snapshot %s: %w", src, err)
+	}
+	// Record the restore under its own name so Undo treats it as a
+	// mutation to revert rather than as a step back.
+	saved := command
+	command = undoCommand + " " + id
+	err = Write(ctx, &s)
+	command = saved
+	return err
+}
+
+// Snapshots returns the ids of retained snapshots, oldest first.
+func Snapshots(ctx context.Context) ([]string, error) {
+	dir, err := gitCommonDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return listSnapshots(filepath.Join(dir, snapshotDir))
+}
+
+func listSnapshots(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	ids := []string{}
+	for _, e := range entries {
+		if id, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	// Ids are fixed-width timestamps, so lexical order is chronological.
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// pruneSnapshots removes the oldest snapshots beyond limit.
+func pruneSnapshots(dir string, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	ids, err := listSnapshots(dir)
+	if err != nil {
+		return err
+	}
+	for len(ids) > limit {
+		if err := os.Remove(filepath.Join(dir, ids[0]+".json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("pruning snapshot %s: %w", ids[0], err)
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
@@ -10,23 +10,126 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
+	"github.com/nvandessel/frond/internal/dag"
 	"github.com/nvandessel/frond/internal/git"
 )
 
 // Branch holds metadata for a single tracked branch.
 type Branch struct {
-	Parent string   `json:"parent"`
-	After  []string `json:"after"`
-	PR     *int     `json:"pr"`
+	Parent    string   `json:"parent"`
+	After     []string `json:"after"`
+	PR        *int     `json:"pr"`
+	PushedSHA string   `json:"pushed_sha,omitempty"` // branch tip at the last frond push
+	Seq       int      `json:"seq,omitempty"`        // creation order; 0 for branches tracked before it was recorded
+	BaseSHA   string   `json:"base_sha,omitempty"`   // parent tip when the branch was created or last restacked
+
+	// BaseOverride, when set, is the PR base instead of Parent. The tree
+	// still hangs the branch under Parent.
+	BaseOverride string `json:"base_override,omitempty"`
+}
+
+// Base returns the branch the PR should target: BaseOverride if set,
+// otherwise Parent.
+func (b Branch) Base() string {
+	if b.BaseOverride != "" {
+		return b.BaseOverride
+	}
+	return b.Parent
+}
+
+// MergedBranch records a branch that sync removed from Branches because its
+// PR merged. It is kept for context until pruned.
+type MergedBranch struct {
+	Parent   string    `json:"parent"` // parent at the time of the merge
+	PR       *int      `json:"pr"`
+	MergedAt time.Time `json:"merged_at"`        // when sync noticed the merge
+	Author   string    `json:"author,omitempty"` // PR author login, when known
 }
 
 // State is the top-level structure persisted to frond.json.
 type State struct {
 	Version  int               `json:"version"`
 	Trunk    string            `json:"trunk"`
+	Trunks   []string          `json:"trunks,omitempty"`
 	Branches map[string]Branch `json:"branches"`
+
+	// LastSyncedTrunk is the trunk commit SHA at the end of the last
+	// successful sync.
+	LastSyncedTrunk string `json:"last_synced_trunk,omitempty"`
+
+	// Config holds repo-level settings; see the Config* keys.
+	Config map[string]string `json:"config,omitempty"`
+
+	// LastSeq is the highest Seq handed out so far.
+	LastSeq int `json:"last_seq,omitempty"`
+
+	// Merged holds branches whose PRs merged, until 'frond prune --merged'.
+	Merged map[string]MergedBranch `json:"merged,omitempty"`
+}
+
+// NextSeq returns the next creation sequence number for a newly tracked
+// branch. Numbers are never reused, even after the branch is untracked.
+func (s *State) NextSeq() int {
+	s.LastSeq++
+	return s.LastSeq
+}
+
+// IsTrunk reports whether name is the primary trunk or one of the
+// secondary trunks (e.g. a long-lived release branch).
+func (s *State) IsTrunk(name string) bool {
+	return name == s.Trunk || slices.Contains(s.Trunks, name)
+}
+
+// AllTrunks returns the primary trunk followed by any secondary trunks.
+func (s *State) AllTrunks() []string {
+	return append([]string{s.Trunk}, s.Trunks...)
+}
+
+// Validate checks the branch graph (see dag.Validate): parents are tracked
+// branches or trunks, after entries are tracked, and there are no cycles.
+func (s *State) Validate() []dag.Problem {
+	branches := make(map[string]dag.BranchInfo, len(s.Branches))
+	for name, b := range s.Branches {
+		branches[name] = dag.BranchInfo{Parent: b.Parent, After: b.After, Seq: b.Seq}
+	}
+	return dag.Validate(branches, s.AllTrunks()...)
+}
+
+// DuplicatePRs returns every PR number recorded on more than one branch,
+// with the sorted names of the branches that claim it.
+func (s *State) DuplicatePRs() map[int][]string {
+	claims := make(map[int][]string)
+	for name, b := range s.Branches {
+		if b.PR != nil {
+			claims[*b.PR] = append(claims[*b.PR], name)
+		}
+	}
+	dups := make(map[int][]string)
+	for pr, names := range claims {
+		if len(names) > 1 {
+			slices.Sort(names)
+			dups[pr] = names
+		}
+	}
+	return dups
+}
+
+// RecordMerged remembers branch name, whose PR merged, in s.Merged until
+// 'frond prune --merged'. b is the branch as it was tracked; author is the
+// PR author's login, or "" if unknown.
+func (s *State) RecordMerged(name string, b Branch, author string) {
+	if s.Merged == nil {
+		s.Merged = make(map[string]MergedBranch)
+	}
+	s.Merged[name] = MergedBranch{
+		Parent:   b.Parent,
+		PR:       b.PR,
+		MergedAt: time.Now().UTC(),
+		Author:   author,
+	}
 }
 
 // ErrNotInitialized is returned by Read when frond.json does not exist.
@@ -88,6 +191,7 @@ func Read(ctx context.Context) (*State, error) {
 
 // Write atomically persists state to frond.json. It writes to a temporary
 // file first, then renames it into place so readers never see partial data.
+// Each effective change is appended to the history log (frond.log.jsonl).
 func Write(ctx context.Context, s *State) error {
 	p, err := Path(ctx)
 	if err != nil {
@@ -112,6 +216,13 @@ func Write(ctx context.Context, s *State) error {
 	if err := rejectSymlink(p); err != nil {
 		return err
 	}
+
+	// Keep the previous contents so the mutation can be logged (and undone).
+	before, err := os.ReadFile(p) //nolint:gosec // path is constructed internally from git common dir
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading %s: %w", p, err)
+	}
+
 	if err := os.WriteFile(tmp, data, 0o600); err != nil {
 		return fmt.Errorf("writing temp file %s: %w", tmp, err)
 	}
@@ -122,12 +233,17 @@ func Write(ctx context.Context, s *State) error {
 		return fmt.Errorf("renaming %s to %s: %w", tmp, p, err)
 	}
 
+	// History is best-effort: failing to log must not fail a write that
+	// has already landed.
+	_ = appendHistory(dir, before, data, s)
+
 	return nil
 }
 
 // Lock acquires an exclusive lockfile (frond.json.lock) to serialise
 // concurrent access from multiple worktrees. It returns an unlock function
-// that removes the lockfile. If a lockfile older than 5 minutes exists it
+// that removes the lockfile. If a lockfile older than the stale duration
+// (5 minutes unless set via SetLockStale or the lock_stale config) exists it
 // is treated as stale, removed, and the lock is retried once.
 //
 // Usage:
@@ -154,7 +270,7 @@ func Lock(ctx context.Context) (unlock func(), err error) {
 		if statErr != nil {
 			return noop, fmt.Errorf("stat lockfile %s: %w", lockPath, statErr)
 		}
-		stale := time.Since(info.ModTime()) > lockStaleDuration || !lockPIDAlive(lockPath)
+		stale := time.Since(info.ModTime()) > lockStale(dir) || !lockPIDAlive(lockPath)
 		if stale {
 			// Stale lock — remove and retry once.
 			if removeErr := os.Remove(lockPath); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
@@ -226,21 +342,27 @@ func ReadOrInit(ctx context.Context) (*State, error) {
 		return s, nil
 	}
 
+	if s, err = New(ctx); err != nil {
+		return nil, err
+	}
+	if err := Write(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// New returns an initial state with auto-detected trunk and no branches,
+// without writing it.
+func New(ctx context.Context) (*State, error) {
 	trunk, err := detectTrunk(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("detecting trunk branch: %w", err)
 	}
-
-	s = &State{
+	return &State{
 		Version:  stateVersion,
 		Trunk:    trunk,
 		Branches: make(map[string]Branch),
-	}
-
-	if err := Write(ctx, s); err != nil {
-		return nil, err
-	}
-	return s, nil
+	}, nil
 }
 
 // detectTrunk determines the trunk branch name. It checks for "main" first,
@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -509,3 +510,389 @@ func TestReadOrInitExistingState(t *testing.T) {
 		t.Error("ReadOrInit() re-initialized instead of reading existing state")
 	}
 }
+
+func TestIsTrunk(t *testing.T) {
+	s := &State{Trunk: "main", Trunks: []string{"release/1.x"}}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"main", true},
+		{"release/1.x", true},
+		{"feature", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IsTrunk(tt.name); got != tt.want {
+				t.Errorf("IsTrunk(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	all := s.AllTrunks()
+	if len(all) != 2 || all[0] != "main" || all[1] != "release/1.x" {
+		t.Errorf("AllTrunks() = %v, want [main release/1.x]", all)
+	}
+}
+
+func TestHistoryAndUndo(t *testing.T) {
+	setupGitRepo(t)
+	ctx := context.Background()
+	SetCommand("test")
+
+	s := &State{Version: 1, Trunk: "main", Branches: map[string]Branch{}}
+	if err := Write(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	s.Branches["feat"] = Branch{Parent: "main", After: []string{}}
+	if err := Write(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	// Rewriting identical state must not add an entry.
+	if err := Write(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := History(ctx)
+	if err != nil {
+		t.Fatalf("History() error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %+v", len(entries), entries)
+	}
+	if got := entries[1].Summary; len(got) != 1 || got[0] != "track feat (parent main)" {
+		t.Errorf("summary = %q", got)
+	}
+	if entries[1].Command != "test" {
+		t.Errorf("command = %q, want test", entries[1].Command)
+	}
+
+	undone, err := Undo(ctx)
+	if err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if undone.Summary[0] != "track feat (parent main)" {
+		t.Errorf("undid %q", undone.Summary)
+	}
+	got, err := Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.Branches["feat"]; ok {
+		t.Error("feat still tracked after undo")
+	}
+
+	// Only the initial write remains, and it has nothing before it.
+	if _, err := Undo(ctx); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("second Undo() error = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestUndoEveryMutationKind(t *testing.T) {
+	pr := 7
+	tests := []struct {
+		name   string
+		mutate func(s *State)
+	}{
+		{"base override", func(s *State) { b := s.Branches["feat"]; b.BaseOverride = "release"; s.Branches["feat"] = b }},
+		{"pushed sha", func(s *State) { b := s.Branches["feat"]; b.PushedSHA = "abc123"; s.Branches["feat"] = b }},
+		{"base sha", func(s *State) { b := s.Branches["feat"]; b.BaseSHA = "def456"; s.Branches["feat"] = b }},
+		{"seq", func(s *State) { b := s.Branches["feat"]; b.Seq = 9; s.Branches["feat"] = b }},
+		{"last synced trunk", func(s *State) { s.LastSyncedTrunk = "fedcba" }},
+		{"last seq", func(s *State) { s.LastSeq = 3 }},
+		{"merged", func(s *State) {
+			s.Merged = map[string]MergedBranch{"old": {Parent: "main", PR: &pr, MergedAt: time.Unix(0, 0).UTC()}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupGitRepo(t)
+			ctx := context.Background()
+			SetCommand("test")
+
+			s := &State{Version: 1, Trunk: "main", Branches: map[string]Branch{}}
+			if err := Write(ctx, s); err != nil {
+				t.Fatal(err)
+			}
+			s.Branches["feat"] = Branch{Parent: "main", After: []string{}}
+			if err := Write(ctx, s); err != nil {
+				t.Fatal(err)
+			}
+			tt.mutate(s)
+			if err := Write(ctx, s); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := History(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 3 {
+				t.Fatalf("got %d entries, want 3: %+v", len(entries), entries)
+			}
+			if got := entries[2].Summary; len(got) != 1 || got[0] == "rewrite state" {
+				t.Errorf("summary = %q, want one specific line", got)
+			}
+
+			if _, err := Undo(ctx); err != nil {
+				t.Fatalf("Undo() error: %v", err)
+			}
+			got, err := Read(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := got.Branches["feat"]; !ok {
+				t.Fatal("undo reverted the track instead of the latest mutation")
+			}
+			want := &State{Version: 1, Trunk: "main", Branches: map[string]Branch{"feat": {Parent: "main", After: []string{}}}}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("after undo = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestUndoAfterRestore(t *testing.T) {
+	setupGitRepo(t)
+	ctx := context.Background()
+	SetCommand("test")
+
+	s := &State{Version: 1, Trunk: "main", Branches: map[string]Branch{}}
+	if err := Write(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	id, err := Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Branches["feat"] = Branch{Parent: "main", After: []string{}}
+	if err := Write(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undo reverts the restore itself, bringing feat back.
+	undone, err := Undo(ctx)
+	if err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if undone.Command != "undo "+id {
+		t.Errorf("undone command = %q, want %q", undone.Command, "undo "+id)
+	}
+	got, err := Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.Branches["feat"]; !ok {
+		t.Error("feat not tracked after undoing the restore")
+	}
+}
+
+func TestHistoryMissingLog(t *testing.T) {
+	setupGitRepo(t)
+
+	entries, err := History(context.Background())
+	if err != nil {
+		t.Fatalf("History() error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("got %d entries, want 0", len(entries))
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	setupGitRepo(t)
+	ctx := context.Background()
+
+	if _, err := Snapshot(ctx); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("Snapshot() without state error = %v, want ErrNotInitialized", err)
+	}
+
+	s := &State{Version: 1, Trunk: "main", Branches: map[string]Branch{
+		"keep": {Parent: "main", After: []string{}},
+	}}
+	if err := Write(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	id, err := Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot() error: %v", err)
+	}
+
+	delete(s.Branches, "keep")
+	if err := Write(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(ctx, id); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	got, err := Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.Branches["keep"]; !ok {
+		t.Error("keep missing after Restore")
+	}
+
+	if err := Restore(ctx, "19700101-000000.000000000"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("Restore(unknown) error = %v, want ErrSnapshotNotFound", err)
+	}
+	if err := Restore(ctx, "../frond"); err == nil {
+		t.Error("Restore() should reject path traversal")
+	}
+}
+
+func TestSnapshotPrunesOldest(t *testing.T) {
+	setupGitRepo(t)
+	ctx := context.Background()
+
+	orig := MaxSnapshots
+	MaxSnapshots = 2
+	t.Cleanup(func() { MaxSnapshots = orig })
+
+	if err := Write(ctx, &State{Version: 1, Trunk: "main", Branches: map[string]Branch{}}); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for range 3 {
+		id, err := Snapshot(ctx)
+		if err != nil {
+			t.Fatalf("Snapshot() error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	got, err := Snapshots(ctx)
+	if err != nil {
+		t.Fatalf("Snapshots() error: %v", err)
+	}
+	if len(got) != 2 || got[0] != ids[1] || got[1] != ids[2] {
+		t.Errorf("Snapshots() = %v, want %v", got, ids[1:])
+	}
+}
+
+func TestHistoryPrunesOldest(t *testing.T) {
+	setupGitRepo(t)
+	ctx := context.Background()
+
+	orig := MaxHistory
+	MaxHistory = 2
+	t.Cleanup(func() { MaxHistory = orig })
+
+	for i := range 4 {
+		if err := Write(ctx, &State{Version: 1, Trunk: "main", LastSeq: i, Branches: map[string]Branch{}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := History(ctx)
+	if err != nil {
+		t.Fatalf("History() error: %v", err)
+	}
+	if len(entries) != 2 || !slices.Equal(entries[1].Summary, []string{"last seq 2 → 3"}) {
+		t.Fatalf("History() = %+v, want the last two entries", entries)
+	}
+
+	// The config key overrides the default; 0 keeps everything.
+	if err := Write(ctx, &State{Version: 1, Trunk: "main", LastSeq: 4, Branches: map[string]Branch{}, Config: map[string]string{ConfigMaxHistory: "0"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(ctx, &State{Version: 1, Trunk: "main", LastSeq: 5, Branches: map[string]Branch{}, Config: map[string]string{ConfigMaxHistory: "0"}}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ = History(ctx); len(entries) != 4 {
+		t.Errorf("with max_history 0, len(History()) = %d, want 4", len(entries))
+	}
+}
+
+func TestLockStaleConfig(t *testing.T) {
+	dir := setupGitRepo(t)
+	gitDir := filepath.Join(dir, ".git")
+	ctx := context.Background()
+	t.Cleanup(func() { lockStaleOverride = 0 })
+
+	if got := lockStale(gitDir); got != lockStaleDuration {
+		t.Errorf("default lockStale = %s, want %s", got, lockStaleDuration)
+	}
+
+	s := &State{Version: 1, Trunk: "main", Branches: map[string]Branch{}, Config: map[string]string{ConfigLockStale: "30s"}}
+	if err := Write(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	if got := lockStale(gitDir); got != 30*time.Second {
+		t.Errorf("config lockStale = %s, want 30s", got)
+	}
+
+	if err := ParseLockStale("2m"); err != nil {
+		t.Fatalf("ParseLockStale() error: %v", err)
+	}
+	if got := lockStale(gitDir); got != 2*time.Minute {
+		t.Errorf("override lockStale = %s, want 2m", got)
+	}
+
+	for _, bad := range []string{"0s", "-1m", "soon"} {
+		if err := ParseLockStale(bad); err == nil {
+			t.Errorf("ParseLockStale(%q) should fail", bad)
+		}
+	}
+	if err := ValidateConfig(ConfigLockStale, "0s"); err == nil {
+		t.Error("ValidateConfig should reject a non-positive lock_stale")
+	}
+	if err := ValidateConfig("nope", "1"); err == nil {
+		t.Error("ValidateConfig should reject unknown keys")
+	}
+}
+
+func TestParseLabelRules(t *testing.T) {
+	rules, err := ParseLabelRules("pay/=area/pay, auth/ = area/auth")
+	if err != nil {
+		t.Fatalf("ParseLabelRules() error: %v", err)
+	}
+	want := []LabelRule{{Prefix: "pay/", Label: "area/pay"}, {Prefix: "auth/", Label: "area/auth"}}
+	if !slices.Equal(rules, want) {
+		t.Errorf("ParseLabelRules() = %v, want %v", rules, want)
+	}
+	for _, bad := range []string{"", "pay/", "=area/pay", "pay/=", "pay/=x,,auth/=y"} {
+		if err := ValidateConfig(ConfigLabelRules, bad); err == nil {
+			t.Errorf("ValidateConfig(label_rules, %q) should fail", bad)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := &State{
+		Trunk:  "main",
+		Trunks: []string{"release/1"},
+		Branches: map[string]Branch{
+			"a":   {Parent: "main"},
+			"fix": {Parent: "release/1", After: []string{"a"}},
+			"odd": {Parent: "nowhere"},
+		},
+	}
+	problems := s.Validate()
+	if len(problems) != 1 || problems[0].Branch != "odd" || problems[0].Ref != "nowhere" {
+		t.Errorf("Validate() = %+v, want only odd's parent reported", problems)
+	}
+}
+
+func TestDuplicatePRs(t *testing.T) {
+	pr := func(n int) *int { return &n }
+	s := &State{
+		Trunk: "main",
+		Branches: map[string]Branch{
+			"a":     {Parent: "main", PR: pr(5)},
+			"b":     {Parent: "a", PR: pr(6)},
+			"copy":  {Parent: "main", PR: pr(5)},
+			"fresh": {Parent: "main"},
+			"other": {Parent: "main"},
+		},
+	}
+	dups := s.DuplicatePRs()
+	if len(dups) != 1 || !slices.Equal(dups[5], []string{"a", "copy"}) {
+		t.Errorf("DuplicatePRs() = %v, want only PR 5 claimed by a and copy", dups)
+	}
+}